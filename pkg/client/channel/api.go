@@ -7,12 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/rwset"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
 )
 
 // CCEvent contains the data for a chaincocde event
@@ -28,10 +31,23 @@ type CCEvent struct {
 type Registration interface {
 }
 
+// Phase identifies a stage of transaction processing that may be given its own deadline
+type Phase int
+
+const (
+	// EndorsementPhase covers sending the transaction proposal to the endorsing peers
+	EndorsementPhase Phase = iota
+	// OrderingPhase covers broadcasting the endorsed transaction to the orderer
+	OrderingPhase
+	// CommitPhase covers waiting for the transaction to be committed by the peers
+	CommitPhase
+)
+
 // Opts allows the user to specify more advanced options
 type Opts struct {
 	ProposalProcessors []fab.ProposalProcessor // targets
 	Timeout            time.Duration
+	PhaseTimeouts      map[Phase]time.Duration
 	Retry              retry.Opts
 }
 
@@ -53,6 +69,27 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	Proposal         *fab.TransactionProposal
 	Responses        []*fab.TransactionProposalResponse
+	// Attempts records every attempt made to process the request, including retries
+	Attempts []Attempt
+	txStatus *txStatus
+}
+
+// Attempt records the outcome of a single attempt to process a request. Each attempt
+// endorses the request again and so has its own transaction ID.
+type Attempt struct {
+	TransactionID    fab.TransactionID
+	TxValidationCode pb.TxValidationCode
+	Error            error
+}
+
+// ChaincodeAction decodes the chaincode action of the endorsed proposal, including the read-write set,
+// the chaincode response and the chaincode event. Since the endorsements must match, only the first
+// proposal response is decoded.
+func (r *Response) ChaincodeAction() (*rwset.ChaincodeAction, error) {
+	if len(r.Responses) == 0 {
+		return nil, errors.New("response does not contain any proposal responses")
+	}
+	return rwset.FromProposalResponse(r.Responses[0].ProposalResponse)
 }
 
 //Handler for chaining transaction executions
@@ -62,12 +99,13 @@ type Handler interface {
 
 //ClientContext contains context parameters for handler execution
 type ClientContext struct {
-	CryptoSuite core.CryptoSuite
-	Discovery   fab.DiscoveryService
-	Selection   fab.SelectionService
-	Channel     fab.Channel // TODO: this should be removed when we have MSP split out.
-	Transactor  fab.Transactor
-	EventHub    fab.EventHub
+	CryptoSuite  core.CryptoSuite
+	Discovery    fab.DiscoveryService
+	Selection    fab.SelectionService
+	Channel      fab.Channel // TODO: this should be removed when we have MSP split out.
+	Transactor   fab.Transactor
+	EventHub     fab.EventHub
+	EventService fab.EventService
 }
 
 //RequestContext contains request, opts, response parameters for handler execution
@@ -77,6 +115,23 @@ type RequestContext struct {
 	Response     Response
 	Error        error
 	RetryHandler retry.Handler
+	// CommitRetryHandler decides whether a transaction rejected at commit is endorsed and submitted again
+	CommitRetryHandler retry.Handler
+	// Ctx is done when the request is cancelled by the caller or its overall timeout expires
+	Ctx reqContext.Context
+}
+
+// PhaseContext returns a context for the given phase of the request. The context is
+// derived from Ctx and additionally expires after the phase timeout, if one was set.
+func (rc *RequestContext) PhaseContext(phase Phase) (reqContext.Context, reqContext.CancelFunc) {
+	parent := rc.Ctx
+	if parent == nil {
+		parent = reqContext.Background()
+	}
+	if timeout, ok := rc.Opts.PhaseTimeouts[phase]; ok && timeout > 0 {
+		return reqContext.WithTimeout(parent, timeout)
+	}
+	return reqContext.WithCancel(parent)
 }
 
 //WithTimeout encapsulates time.Duration to Option
@@ -87,6 +142,18 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+//WithPhaseTimeout limits the time spent in the given phase of the request. The
+//overall request timeout still applies.
+func WithPhaseTimeout(phase Phase, timeout time.Duration) Option {
+	return func(opts *Opts) error {
+		if opts.PhaseTimeouts == nil {
+			opts.PhaseTimeouts = make(map[Phase]time.Duration)
+		}
+		opts.PhaseTimeouts[phase] = timeout
+		return nil
+	}
+}
+
 //WithProposalProcessor encapsulates ProposalProcessors to Option
 func WithProposalProcessor(proposalProcessors ...fab.ProposalProcessor) Option {
 	return func(opts *Opts) error {
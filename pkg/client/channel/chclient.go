@@ -8,7 +8,9 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
@@ -36,13 +38,14 @@ const (
 // An application that requires interaction with multiple channels should create a separate
 // instance of the channel client for each channel. Channel client supports non-admin functions only.
 type Client struct {
-	context    context.ProviderContext
-	discovery  fab.DiscoveryService
-	selection  fab.SelectionService
-	channel    fab.Channel
-	transactor fab.Transactor
-	eventHub   fab.EventHub
-	greylist   *greylist.Filter
+	context      context.ProviderContext
+	discovery    fab.DiscoveryService
+	selection    fab.SelectionService
+	channel      fab.Channel
+	transactor   fab.Transactor
+	eventHub     fab.EventHub
+	eventService fab.EventService
+	greylist     *greylist.Filter
 }
 
 // Context holds the providers and services needed to create a Client.
@@ -62,6 +65,11 @@ func New(c Context) (*Client, error) {
 		return nil, errors.WithMessage(err, "event hub creation failed")
 	}
 
+	eventService, err := c.ChannelService.EventService()
+	if err != nil {
+		return nil, errors.WithMessage(err, "event service creation failed")
+	}
+
 	transactor, err := c.ChannelService.Transactor()
 	if err != nil {
 		return nil, errors.WithMessage(err, "transactor creation failed")
@@ -74,13 +82,14 @@ func New(c Context) (*Client, error) {
 	}
 
 	channelClient := Client{
-		greylist:   greylistProvider,
-		context:    c,
-		discovery:  discovery.NewDiscoveryFilterService(c.DiscoveryService, greylistProvider),
-		selection:  c.SelectionService,
-		channel:    channel,
-		transactor: transactor,
-		eventHub:   eventHub,
+		greylist:     greylistProvider,
+		context:      c,
+		discovery:    discovery.NewDiscoveryFilterService(c.DiscoveryService, greylistProvider),
+		selection:    c.SelectionService,
+		channel:      channel,
+		transactor:   transactor,
+		eventHub:     eventHub,
+		eventService: eventService,
 	}
 
 	return &channelClient, nil
@@ -88,16 +97,57 @@ func New(c Context) (*Client, error) {
 
 // Query chaincode using request and optional options provided
 func (cc *Client) Query(request Request, options ...Option) (Response, error) {
-	return cc.InvokeHandler(NewQueryHandler(), request, cc.addDefaultTimeout(core.Query, options...)...)
+	return cc.QueryWithContext(reqContext.Background(), request, options...)
+}
+
+// QueryWithContext queries chaincode using request and optional options provided.
+// The query is abandoned when ctx is cancelled or its deadline expires.
+func (cc *Client) QueryWithContext(ctx reqContext.Context, request Request, options ...Option) (Response, error) {
+	return cc.InvokeHandlerWithContext(ctx, NewQueryHandler(), request, cc.addDefaultTimeout(core.Query, options...)...)
 }
 
 // Execute prepares and executes transaction using request and optional options provided
 func (cc *Client) Execute(request Request, options ...Option) (Response, error) {
-	return cc.InvokeHandler(NewExecuteHandler(), request, cc.addDefaultTimeout(core.Execute, options...)...)
+	return cc.ExecuteWithContext(reqContext.Background(), request, options...)
+}
+
+// ExecuteWithContext prepares and executes transaction using request and optional options provided.
+// The transaction is abandoned when ctx is cancelled or its deadline expires. Note that a transaction
+// which has already been sent to the orderer may still be committed.
+func (cc *Client) ExecuteWithContext(ctx reqContext.Context, request Request, options ...Option) (Response, error) {
+	return cc.InvokeHandlerWithContext(ctx, NewExecuteHandler(), request, cc.addDefaultTimeout(core.Execute, options...)...)
+}
+
+// ExecuteAsync prepares and executes transaction using request and optional options provided.
+// It returns as soon as the transaction has been accepted by the orderer; the commit status is
+// delivered in the background through the returned handle.
+func (cc *Client) ExecuteAsync(request Request, options ...Option) (*TxHandle, error) {
+	response, err := cc.InvokeHandler(NewExecuteAsyncHandler(), request, cc.addDefaultTimeout(core.Execute, options...)...)
+	if err != nil {
+		return nil, err
+	}
+	if response.txStatus == nil {
+		return nil, errors.New("transaction was not submitted")
+	}
+
+	return &TxHandle{
+		TransactionID: response.TransactionID,
+		Payload:       response.Payload,
+		Proposal:      response.Proposal,
+		Responses:     response.Responses,
+		status:        response.txStatus,
+	}, nil
 }
 
 //InvokeHandler invokes handler using request and options provided
 func (cc *Client) InvokeHandler(handler Handler, request Request, options ...Option) (Response, error) {
+	return cc.InvokeHandlerWithContext(reqContext.Background(), handler, request, options...)
+}
+
+//InvokeHandlerWithContext invokes handler using request and options provided.
+//The handler chain is abandoned when ctx is cancelled or its deadline expires, in which
+//case the response holds the attempts completed so far.
+func (cc *Client) InvokeHandlerWithContext(ctx reqContext.Context, handler Handler, request Request, options ...Option) (Response, error) {
 	//Read execute tx options
 	txnOpts, err := cc.prepareOptsFromOptions(options...)
 	if err != nil {
@@ -110,13 +160,27 @@ func (cc *Client) InvokeHandler(handler Handler, request Request, options ...Opt
 		return Response{}, err
 	}
 
-	complete := make(chan bool)
+	reqCtx, cancel := reqContext.WithTimeout(ctx, requestContext.Opts.Timeout)
+	defer cancel()
+	requestContext.Ctx = reqCtx
+
+	complete := make(chan bool, 1)
 
+	var mutex sync.Mutex
+	var attempts []Attempt
 	go func() {
 	handleInvoke:
 		//Perform action through handler
 		handler.Handle(requestContext, clientContext)
-		if cc.resolveRetry(requestContext, txnOpts) {
+		mutex.Lock()
+		attempts = append(attempts, Attempt{
+			TransactionID:    requestContext.Response.TransactionID,
+			TxValidationCode: requestContext.Response.TxValidationCode,
+			Error:            requestContext.Error,
+		})
+		requestContext.Response.Attempts = attempts
+		mutex.Unlock()
+		if reqCtx.Err() == nil && cc.resolveRetry(requestContext, txnOpts) {
 			goto handleInvoke
 		}
 		complete <- true
@@ -124,33 +188,51 @@ func (cc *Client) InvokeHandler(handler Handler, request Request, options ...Opt
 	select {
 	case <-complete:
 		return requestContext.Response, requestContext.Error
-	case <-time.After(requestContext.Opts.Timeout):
-		return Response{}, status.New(status.ClientStatus, status.Timeout.ToInt32(),
-			"request timed out", nil)
+	case <-reqCtx.Done():
+		mutex.Lock()
+		defer mutex.Unlock()
+		return Response{Attempts: append([]Attempt(nil), attempts...)}, status.NewFromContextError(status.ClientStatus, reqCtx.Err(), nil)
 	}
 }
 
 func (cc *Client) resolveRetry(ctx *RequestContext, opts Opts) bool {
+	if ctx.Error != nil && ctx.CommitRetryHandler != nil && retryRequired(ctx.Ctx, ctx.CommitRetryHandler, ctx.Error) {
+		logger.Infof("Transaction [%s] was rejected with code %s, retrying with a new transaction ID", ctx.Response.TransactionID, ctx.Response.TxValidationCode)
+		resetRequestContext(ctx, opts)
+		return true
+	}
+
 	errs, ok := ctx.Error.(multi.Errors)
 	if !ok {
 		errs = append(errs, ctx.Error)
 	}
 	for _, e := range errs {
-		if ctx.RetryHandler.Required(e) {
+		if retryRequired(ctx.Ctx, ctx.RetryHandler, e) {
 			logger.Infof("Retrying on error %s", e)
 			cc.greylist.Greylist(e)
-
-			// Reset context parameters
-			ctx.Opts.ProposalProcessors = opts.ProposalProcessors
-			ctx.Error = nil
-			ctx.Response = Response{}
-
+			resetRequestContext(ctx, opts)
 			return true
 		}
 	}
 	return false
 }
 
+// retryRequired asks the handler whether the error is retried. The backoff of handlers which
+// support it ends once the request context is done.
+func retryRequired(ctx reqContext.Context, handler retry.Handler, err error) bool {
+	if h, ok := handler.(retry.ContextHandler); ok && ctx != nil {
+		return h.RequiredWithContext(ctx, err)
+	}
+	return handler.Required(err)
+}
+
+// resetRequestContext resets the context parameters before the request is retried
+func resetRequestContext(ctx *RequestContext, opts Opts) {
+	ctx.Opts.ProposalProcessors = opts.ProposalProcessors
+	ctx.Error = nil
+	ctx.Response = Response{}
+}
+
 //prepareHandlerContexts prepares context objects for handlers
 func (cc *Client) prepareHandlerContexts(request Request, options Opts) (*RequestContext, *ClientContext, error) {
 
@@ -159,18 +241,20 @@ func (cc *Client) prepareHandlerContexts(request Request, options Opts) (*Reques
 	}
 
 	clientContext := &ClientContext{
-		Selection:  cc.selection,
-		Discovery:  cc.discovery,
-		Channel:    cc.channel,
-		Transactor: cc.transactor,
-		EventHub:   cc.eventHub,
+		Selection:    cc.selection,
+		Discovery:    cc.discovery,
+		Channel:      cc.channel,
+		Transactor:   cc.transactor,
+		EventHub:     cc.eventHub,
+		EventService: cc.eventService,
 	}
 
 	requestContext := &RequestContext{
-		Request:      request,
-		Opts:         options,
-		Response:     Response{},
-		RetryHandler: retry.New(options.Retry),
+		Request:            request,
+		Opts:               options,
+		Response:           Response{},
+		RetryHandler:       retry.New(options.Retry),
+		CommitRetryHandler: retry.NewCommit(options.Retry.Commit),
 	}
 
 	if requestContext.Opts.Timeout == 0 {
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"fmt"
 	"testing"
 	"time"
@@ -26,6 +27,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 )
 
@@ -66,7 +68,12 @@ func TestTxProposalResponseFilter(t *testing.T) {
 	assert.True(t, ok, "Expected status error")
 	assert.EqualValues(t, status.EndorsementMismatch, status.ToSDKStatusCode(statusError.Code))
 	assert.Equal(t, status.EndorserClientStatus, statusError.Group)
-	assert.Equal(t, "ProposalResponsePayloads do not match", statusError.Message, "Expected response message from server")
+	if assert.Len(t, statusError.Details, 1) {
+		mismatch, ok := statusError.Details[0].(*EndorsementMismatch)
+		assert.True(t, ok, "Expected endorsement mismatch details")
+		assert.Equal(t, "ProposalResponsePayloads do not match: "+mismatch.String(), statusError.Message, "Expected the mismatch in the message")
+		assert.Len(t, mismatch.Groups, 2)
+	}
 }
 
 func TestQuery(t *testing.T) {
@@ -244,6 +251,42 @@ func TestInvokeHandler(t *testing.T) {
 	}
 }
 
+// rejectingHandler rejects the first transactions with a read conflict, and then blocks until
+// it is released
+type rejectingHandler struct {
+	rejections int
+	release    chan struct{}
+}
+
+func (h *rejectingHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	if h.rejections == 0 {
+		<-h.release
+		return
+	}
+	h.rejections--
+	requestContext.Response.TransactionID = fab.TransactionID(fmt.Sprintf("tx%d", h.rejections))
+	requestContext.Response.TxValidationCode = pb.TxValidationCode_MVCC_READ_CONFLICT
+	requestContext.Error = status.New(status.EventServerStatus, int32(pb.TxValidationCode_MVCC_READ_CONFLICT), "received invalid transaction", nil)
+}
+
+func TestInvokeHandlerTimeoutAttempts(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	handler := &rejectingHandler{rejections: 1, release: make(chan struct{})}
+	defer close(handler.release)
+
+	retryOpts := retry.Opts{Commit: retry.CommitOpts{Attempts: 1}}
+	response, err := chClient.InvokeHandler(handler, Request{ChaincodeID: "testCC", Fcn: "move", Args: [][]byte{[]byte("a"), []byte("b"), []byte("1")}},
+		WithRetry(retryOpts), WithTimeout(100*time.Millisecond))
+
+	s, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.Timeout.ToInt32(), s.Code, "expected timeout status")
+	if assert.Len(t, response.Attempts, 1, "expected the attempt made before the timeout") {
+		assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, response.Attempts[0].TxValidationCode)
+	}
+}
+
 // customEndorsementHandler ignores the channel in the ClientContext
 // and instead sends the proposal to the given channel
 type customEndorsementHandler struct {
@@ -354,7 +397,7 @@ func TestOrdererStatusError(t *testing.T) {
 	testOrderer1 := fcmocks.NewMockOrderer("", make(chan *fab.SignedEnvelope))
 	orderers := []fab.Orderer{testOrderer1}
 	chClient := setupChannelClientWithNodes(peers, orderers, t)
-	chClient.eventHub = fcmocks.NewMockEventHub()
+	chClient.eventService = fcmocks.NewMockEventService()
 
 	mockOrderer, ok := testOrderer1.(fcmocks.MockOrderer)
 	assert.True(t, ok, "Expected object to be mock orderer")
@@ -374,24 +417,28 @@ func TestOrdererStatusError(t *testing.T) {
 
 func TestTransactionValidationError(t *testing.T) {
 	validationCode := pb.TxValidationCode_BAD_RWSET
-	mockEventHub := fcmocks.NewMockEventHub()
+	mockEventService := fcmocks.NewMockEventService()
 	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
 	peers := []fab.Peer{testPeer1}
 
+	errch := make(chan error, 1)
 	go func() {
 		select {
-		case callback := <-mockEventHub.RegisteredTxCallbacks:
-			callback("txid", validationCode,
-				status.New(status.EventServerStatus, int32(validationCode), "test", nil))
+		case reg := <-mockEventService.TxStatusRegistrations:
+			reg.Eventch <- &fab.TxStatusEvent{TxID: reg.TxID, TxValidationCode: validationCode}
+			errch <- nil
 		case <-time.After(time.Second * 5):
-			t.Fatal("Timed out waiting for execute Tx to register event callback")
+			errch <- errors.New("Timed out waiting for execute Tx to register for TxStatus event")
 		}
 	}()
 
 	chClient := setupChannelClient(peers, t)
-	chClient.eventHub = mockEventHub
+	chClient.eventService = mockEventService
 	response, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke",
 		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	if err := <-errch; err != nil {
+		t.Fatal(err)
+	}
 	assert.Nil(t, response.Payload, "Expected nil result on failed execute operation")
 	assert.NotNil(t, err, "expected error")
 	statusError, ok := status.FromError(err)
@@ -399,6 +446,272 @@ func TestTransactionValidationError(t *testing.T) {
 	assert.EqualValues(t, validationCode, status.ToTransactionValidationCode(statusError.Code))
 }
 
+func TestCommitRetry(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+
+	errch := make(chan error, 1)
+	go func() {
+		codes := []pb.TxValidationCode{pb.TxValidationCode_MVCC_READ_CONFLICT, pb.TxValidationCode_PHANTOM_READ_CONFLICT, pb.TxValidationCode_VALID}
+		for _, code := range codes {
+			select {
+			case reg := <-mockEventService.TxStatusRegistrations:
+				reg.Eventch <- &fab.TxStatusEvent{TxID: reg.TxID, TxValidationCode: code}
+			case <-time.After(time.Second * 5):
+				errch <- errors.New("Timed out waiting for execute Tx to register for TxStatus event")
+				return
+			}
+		}
+		errch <- nil
+	}()
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	retryOpts := retry.Opts{Commit: retry.CommitOpts{Attempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}}
+	response, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}},
+		WithRetry(retryOpts))
+	if err != nil {
+		t.Fatalf("Failed to execute transaction: %s", err)
+	}
+	if err := <-errch; err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, pb.TxValidationCode_VALID, response.TxValidationCode)
+
+	if !assert.Len(t, response.Attempts, 3) {
+		t.FailNow()
+	}
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, response.Attempts[0].TxValidationCode)
+	assert.NotNil(t, response.Attempts[0].Error)
+	assert.Equal(t, pb.TxValidationCode_PHANTOM_READ_CONFLICT, response.Attempts[1].TxValidationCode)
+	assert.Equal(t, pb.TxValidationCode_VALID, response.Attempts[2].TxValidationCode)
+	assert.Nil(t, response.Attempts[2].Error)
+	assert.Equal(t, response.TransactionID, response.Attempts[2].TransactionID)
+	assert.NotEqual(t, response.Attempts[0].TransactionID, response.Attempts[1].TransactionID, "expected a new transaction ID for each attempt")
+	assert.NotEqual(t, response.Attempts[1].TransactionID, response.Attempts[2].TransactionID, "expected a new transaction ID for each attempt")
+}
+
+func TestCommitRetryExhausted(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+
+	errch := make(chan error, 1)
+	go func() {
+		for i := 0; i < 2; i++ {
+			select {
+			case reg := <-mockEventService.TxStatusRegistrations:
+				reg.Eventch <- &fab.TxStatusEvent{TxID: reg.TxID, TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT}
+			case <-time.After(time.Second * 5):
+				errch <- errors.New("Timed out waiting for execute Tx to register for TxStatus event")
+				return
+			}
+		}
+		errch <- nil
+	}()
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	retryOpts := retry.Opts{Commit: retry.CommitOpts{Attempts: 1}}
+	response, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}},
+		WithRetry(retryOpts))
+	if err := <-errch; err != nil {
+		t.Fatal(err)
+	}
+	statusError, ok := status.FromError(err)
+	assert.True(t, ok, "Expected status error got %+v", err)
+	assert.EqualValues(t, pb.TxValidationCode_MVCC_READ_CONFLICT, statusError.Code)
+	assert.Len(t, response.Attempts, 2)
+}
+
+func TestExecuteAsync(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("value")
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	handle, err := chClient.ExecuteAsync(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	if err != nil {
+		t.Fatalf("Failed to submit transaction: %s", err)
+	}
+	assert.NotEmpty(t, handle.TransactionID, "expected transaction ID")
+	assert.Equal(t, []byte("value"), handle.Payload)
+	assert.Len(t, handle.Responses, 1, "expected endorsement from one peer")
+
+	select {
+	case <-handle.Done():
+		t.Fatalf("Transaction should not be committed before the event is received")
+	default:
+	}
+
+	_, err = handle.Wait(100 * time.Millisecond)
+	assert.NotNil(t, err, "expected timeout waiting for status")
+
+	select {
+	case reg := <-mockEventService.TxStatusRegistrations:
+		assert.Equal(t, string(handle.TransactionID), reg.TxID)
+		reg.Eventch <- &fab.TxStatusEvent{TxID: reg.TxID, TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for execute async to register for TxStatus event")
+	}
+
+	code, err := handle.Wait(time.Second * 5)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, code)
+	statusError, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, pb.TxValidationCode_MVCC_READ_CONFLICT, statusError.Code)
+
+	<-handle.Done()
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, handle.Response().TxValidationCode)
+}
+
+func TestExecuteAsyncOrdererError(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testOrderer := fcmocks.NewMockOrderer("", make(chan *fab.SignedEnvelope))
+
+	chClient := setupChannelClientWithNodes([]fab.Peer{testPeer1}, []fab.Orderer{testOrderer}, t)
+	chClient.eventService = fcmocks.NewMockEventService()
+
+	mockOrderer, ok := testOrderer.(fcmocks.MockOrderer)
+	assert.True(t, ok, "Expected object to be mock orderer")
+	mockOrderer.EnqueueSendBroadcastError(status.New(status.OrdererClientStatus,
+		status.ConnectionFailed.ToInt32(), "orderer unavailable", nil))
+
+	handle, err := chClient.ExecuteAsync(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	assert.Nil(t, handle, "expected no handle when the orderer rejects the transaction")
+	assert.NotNil(t, err, "expected orderer error")
+}
+
+func TestExecuteAsyncClose(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	handle, err := chClient.ExecuteAsync(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	if err != nil {
+		t.Fatalf("Failed to submit transaction: %s", err)
+	}
+	reg := <-mockEventService.TxStatusRegistrations
+
+	handle.Close()
+
+	select {
+	case unregistered := <-mockEventService.Unregistrations:
+		assert.Equal(t, reg, unregistered, "expected the transaction status registration to be removed")
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for the transaction status registration to be removed")
+	}
+
+	_, err = handle.Wait(time.Second * 5)
+	statusError, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.Canceled.ToInt32(), statusError.Code, "expected canceled status")
+}
+
+func TestExecuteAsyncCommitTimeout(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	handle, err := chClient.ExecuteAsync(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}},
+		WithPhaseTimeout(CommitPhase, 100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to submit transaction: %s", err)
+	}
+	reg := <-mockEventService.TxStatusRegistrations
+
+	code, err := handle.Wait(time.Second * 5)
+	assert.Equal(t, pb.TxValidationCode_INVALID_OTHER_REASON, code)
+	statusError, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.Timeout.ToInt32(), statusError.Code, "expected timeout status")
+
+	select {
+	case unregistered := <-mockEventService.Unregistrations:
+		assert.Equal(t, reg, unregistered, "expected the transaction status registration to be removed")
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for the transaction status registration to be removed")
+	}
+}
+
+// blockingProcessor does not respond until the request context is done
+type blockingProcessor struct{}
+
+func (p *blockingProcessor) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestQueryWithContextCancel(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	ctx, cancel := reqContext.WithCancel(reqContext.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := chClient.QueryWithContext(ctx, Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}},
+		WithProposalProcessor(&blockingProcessor{}), WithTimeout(5*time.Second))
+	assert.True(t, time.Since(start) < 2*time.Second, "expected query to return once cancelled")
+
+	s, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.Canceled.ToInt32(), s.Code, "expected canceled status")
+}
+
+func TestExecuteWithContextDeadline(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := chClient.ExecuteWithContext(ctx, Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}},
+		WithProposalProcessor(&blockingProcessor{}), WithTimeout(5*time.Second))
+
+	s, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.Timeout.ToInt32(), s.Code, "expected timeout status")
+}
+
+func TestEndorsementPhaseTimeout(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	start := time.Now()
+	_, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}},
+		WithProposalProcessor(&blockingProcessor{}), WithTimeout(5*time.Second), WithPhaseTimeout(EndorsementPhase, 100*time.Millisecond))
+	assert.True(t, time.Since(start) < 2*time.Second, "expected endorsement phase to time out")
+	assert.NotNil(t, err, "expected error")
+	assert.Equal(t, reqContext.DeadlineExceeded, errors.Cause(err), "expected endorsement deadline to be exceeded")
+}
+
+func TestCommitPhaseTimeout(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("value")
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = fcmocks.NewMockEventService()
+
+	start := time.Now()
+	_, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}},
+		WithTimeout(5*time.Second), WithPhaseTimeout(CommitPhase, 100*time.Millisecond))
+	assert.True(t, time.Since(start) < 2*time.Second, "expected commit phase to time out")
+	assert.NotNil(t, err, "expected error")
+	assert.Contains(t, err.Error(), "didn't receive block event")
+}
+
 func TestExecuteTxWithRetries(t *testing.T) {
 	testStatus := status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "test", nil)
 	testResp := []byte("test")
@@ -652,6 +965,28 @@ func createAndSendTestTransactionProposal(sender fab.ProposalSender, chrequest *
 		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "creation of transaction proposal failed")
 	}
 
-	tpr, err := sender.SendTransactionProposal(tpreq, targets)
+	tpr, err := sender.SendTransactionProposal(reqContext.Background(), tpreq, targets)
 	return tpr, tpreq.TxnID, err
 }
+
+func TestResponseChaincodeAction(t *testing.T) {
+	response := Response{}
+	_, err := response.ChaincodeAction()
+	assert.NotNil(t, err, "expected error for response without proposal responses")
+
+	ccAction := &pb.ChaincodeAction{
+		Events:   protos_utils.MarshalOrPanic(&pb.ChaincodeEvent{ChaincodeId: "testCC", EventName: "test"}),
+		Response: &pb.Response{Status: 200, Payload: []byte("value")},
+	}
+	payload := protos_utils.MarshalOrPanic(&pb.ProposalResponsePayload{Extension: protos_utils.MarshalOrPanic(ccAction)})
+	response.Responses = []*fab.TransactionProposalResponse{
+		{ProposalResponse: &pb.ProposalResponse{Payload: payload}},
+	}
+
+	action, err := response.ChaincodeAction()
+	if err != nil {
+		t.Fatalf("Failed to decode chaincode action: %s", err)
+	}
+	assert.Equal(t, []byte("value"), action.Response.Payload)
+	assert.Equal(t, "test", action.Event.EventName)
+}
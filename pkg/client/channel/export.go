@@ -0,0 +1,312 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// exportVersion is the version of the exported proposal and endorsements format
+const exportVersion = 1
+
+// ExportedProposal is a signed transaction proposal in a portable format. It is handed to
+// organisations whose peers cannot be reached directly, which endorse it with their own
+// SDK instance (see EndorseExportedProposal).
+type ExportedProposal struct {
+	Version        int               `json:"version"`
+	ChannelID      string            `json:"channelId"`
+	TransactionID  fab.TransactionID `json:"txId"`
+	ChaincodeID    string            `json:"chaincodeId"`
+	SignedProposal []byte            `json:"signedProposal"`
+}
+
+// ExportedEndorsements are the responses of an organisation's peers to an exported proposal
+type ExportedEndorsements struct {
+	Version       int                    `json:"version"`
+	ChannelID     string                 `json:"channelId"`
+	TransactionID fab.TransactionID      `json:"txId"`
+	Endorsements  []*ExportedEndorsement `json:"endorsements"`
+}
+
+// ExportedEndorsement is the marshalled ProposalResponse of a single endorser
+type ExportedEndorsement struct {
+	Endorser         string `json:"endorser"`
+	ProposalResponse []byte `json:"proposalResponse"`
+}
+
+// Bytes returns the exported proposal in its portable (JSON) format
+func (p *ExportedProposal) Bytes() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// ParseExportedProposal parses an exported proposal from its portable format
+func ParseExportedProposal(b []byte) (*ExportedProposal, error) {
+	p := &ExportedProposal{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of exported proposal failed")
+	}
+	if p.Version != exportVersion {
+		return nil, errors.Errorf("unsupported exported proposal version: %d", p.Version)
+	}
+	return p, nil
+}
+
+// Bytes returns the exported endorsements in their portable (JSON) format
+func (e *ExportedEndorsements) Bytes() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// ParseExportedEndorsements parses exported endorsements from their portable format
+func ParseExportedEndorsements(b []byte) (*ExportedEndorsements, error) {
+	e := &ExportedEndorsements{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of exported endorsements failed")
+	}
+	if e.Version != exportVersion {
+		return nil, errors.Errorf("unsupported exported endorsements version: %d", e.Version)
+	}
+	return e, nil
+}
+
+// ExportProposal creates a proposal for the request, signed by the identity of the client,
+// and exports it so that it can be endorsed by other organisations
+func (cc *Client) ExportProposal(request Request) (*ExportedProposal, error) {
+	txh, err := cc.transactor.CreateTransactionHeader()
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating transaction header failed")
+	}
+
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{
+		ChaincodeID:  request.ChaincodeID,
+		Fcn:          request.Fcn,
+		Args:         request.Args,
+		TransientMap: request.TransientMap,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating transaction proposal failed")
+	}
+
+	signedProposal, err := cc.transactor.SignTransactionProposal(proposal)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing transaction proposal failed")
+	}
+
+	signedProposalBytes, err := proto.Marshal(signedProposal)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of signed proposal failed")
+	}
+
+	return &ExportedProposal{
+		Version:        exportVersion,
+		ChannelID:      cc.channel.Name(),
+		TransactionID:  proposal.TxnID,
+		ChaincodeID:    request.ChaincodeID,
+		SignedProposal: signedProposalBytes,
+	}, nil
+}
+
+// EndorseExportedProposal sends an exported proposal to the peers of this client and exports
+// their endorsements so that they can be returned to the proposal's creator. The endorsers are
+// selected as for Execute unless they are given with the WithProposalProcessor option.
+func (cc *Client) EndorseExportedProposal(proposal *ExportedProposal, options ...Option) (*ExportedEndorsements, error) {
+	signedProposal, tp, request, err := cc.importProposal(proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := NewProposalProcessorHandler(
+		&signedProposalEndorsementHandler{
+			proposal:       tp,
+			signedProposal: signedProposal,
+			next:           NewEndorsementValidationHandler(),
+		},
+	)
+
+	response, err := cc.InvokeHandler(handler, request, cc.addDefaultTimeout(core.Execute, options...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	endorsements := &ExportedEndorsements{
+		Version:       exportVersion,
+		ChannelID:     proposal.ChannelID,
+		TransactionID: tp.TxnID,
+	}
+	for _, r := range response.Responses {
+		proposalResponse, err := proto.Marshal(r.ProposalResponse)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal of proposal response failed")
+		}
+		endorsements.Endorsements = append(endorsements.Endorsements, &ExportedEndorsement{
+			Endorser:         r.Endorser,
+			ProposalResponse: proposalResponse,
+		})
+	}
+	return endorsements, nil
+}
+
+// ExecuteWithEndorsements merges the endorsements of an exported proposal that were returned
+// by the organisations, validates them and sends the transaction to the orderer. It waits until
+// the transaction is committed. Retries are not supported since a retry would submit the same
+// transaction again, which can only be rejected as a duplicate, and a new transaction requires
+// a new proposal to be endorsed by every organisation.
+func (cc *Client) ExecuteWithEndorsements(proposal *ExportedProposal, endorsements []*ExportedEndorsements, options ...Option) (Response, error) {
+	_, tp, request, err := cc.importProposal(proposal)
+	if err != nil {
+		return Response{}, err
+	}
+
+	responses, err := mergeEndorsements(tp.TxnID, endorsements)
+	if err != nil {
+		return Response{}, err
+	}
+
+	handler := &importedEndorsementHandler{
+		proposal:  tp,
+		responses: responses,
+		next:      NewEndorsementValidationHandler(NewSignatureValidationHandler(NewCommitHandler())),
+	}
+
+	options = append(cc.addDefaultTimeout(core.Execute, options...), withoutRetry())
+	return cc.InvokeHandler(handler, request, options...)
+}
+
+// importProposal decodes an exported proposal and checks that it belongs to the channel
+func (cc *Client) importProposal(exported *ExportedProposal) (*pb.SignedProposal, *fab.TransactionProposal, Request, error) {
+	if exported == nil {
+		return nil, nil, Request{}, errors.New("proposal is required")
+	}
+
+	signedProposal := &pb.SignedProposal{}
+	if err := proto.Unmarshal(exported.SignedProposal, signedProposal); err != nil {
+		return nil, nil, Request{}, errors.Wrap(err, "unmarshal of signed proposal failed")
+	}
+	proposal := &pb.Proposal{}
+	if err := proto.Unmarshal(signedProposal.ProposalBytes, proposal); err != nil {
+		return nil, nil, Request{}, errors.Wrap(err, "unmarshal of proposal failed")
+	}
+
+	header, err := protos_utils.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, nil, Request{}, errors.Wrap(err, "unmarshal of proposal header failed")
+	}
+	channelHeader, err := protos_utils.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return nil, nil, Request{}, errors.Wrap(err, "unmarshal of channel header failed")
+	}
+	if channelHeader.ChannelId != cc.channel.Name() {
+		return nil, nil, Request{}, errors.Errorf("proposal is for channel [%s], not [%s]", channelHeader.ChannelId, cc.channel.Name())
+	}
+	if fab.TransactionID(channelHeader.TxId) != exported.TransactionID {
+		return nil, nil, Request{}, errors.Errorf("transaction ID [%s] does not match the proposal [%s]", exported.TransactionID, channelHeader.TxId)
+	}
+
+	request, err := requestFromProposal(proposal)
+	if err != nil {
+		return nil, nil, Request{}, err
+	}
+
+	tp := &fab.TransactionProposal{TxnID: fab.TransactionID(channelHeader.TxId), Proposal: proposal}
+	return signedProposal, tp, request, nil
+}
+
+// requestFromProposal returns the chaincode invocation of the proposal
+func requestFromProposal(proposal *pb.Proposal) (Request, error) {
+	ccProposalPayload, err := protos_utils.GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return Request{}, errors.Wrap(err, "unmarshal of chaincode proposal payload failed")
+	}
+	cis := &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(ccProposalPayload.Input, cis); err != nil {
+		return Request{}, errors.Wrap(err, "unmarshal of chaincode invocation spec failed")
+	}
+	spec := cis.ChaincodeSpec
+	if spec == nil || spec.ChaincodeId == nil || spec.Input == nil || len(spec.Input.Args) == 0 {
+		return Request{}, errors.New("proposal does not contain a chaincode invocation")
+	}
+
+	return Request{
+		ChaincodeID: spec.ChaincodeId.Name,
+		Fcn:         string(spec.Input.Args[0]),
+		Args:        spec.Input.Args[1:],
+	}, nil
+}
+
+// mergeEndorsements decodes the endorsements returned by each organisation. An endorser
+// which appears more than once is only included once.
+func mergeEndorsements(txnID fab.TransactionID, endorsements []*ExportedEndorsements) ([]*fab.TransactionProposalResponse, error) {
+	var responses []*fab.TransactionProposalResponse
+	endorsers := make(map[string]bool)
+	for _, e := range endorsements {
+		if e.TransactionID != txnID {
+			return nil, errors.Errorf("endorsements are for transaction [%s], not [%s]", e.TransactionID, txnID)
+		}
+		for _, endorsement := range e.Endorsements {
+			if endorsers[endorsement.Endorser] {
+				logger.Debugf("ignoring duplicate endorsement from [%s]", endorsement.Endorser)
+				continue
+			}
+			endorsers[endorsement.Endorser] = true
+
+			proposalResponse := &pb.ProposalResponse{}
+			if err := proto.Unmarshal(endorsement.ProposalResponse, proposalResponse); err != nil {
+				return nil, errors.Wrapf(err, "unmarshal of proposal response from [%s] failed", endorsement.Endorser)
+			}
+			responses = append(responses, &fab.TransactionProposalResponse{
+				Endorser:         endorsement.Endorser,
+				Status:           proposalResponse.GetResponse().GetStatus(),
+				ProposalResponse: proposalResponse,
+			})
+		}
+	}
+	return responses, nil
+}
+
+// withoutRetry disables all retries of the request
+func withoutRetry() Option {
+	return func(opts *Opts) error {
+		opts.Retry = retry.Opts{}
+		return nil
+	}
+}
+
+// importedEndorsementHandler sets endorsements which were collected outside of the client on the request
+type importedEndorsementHandler struct {
+	proposal  *fab.TransactionProposal
+	responses []*fab.TransactionProposalResponse
+	next      Handler
+}
+
+//Handle for setting the imported endorsements
+func (h *importedEndorsementHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	requestContext.Response.Proposal = h.proposal
+	requestContext.Response.TransactionID = h.proposal.TxnID
+
+	if len(h.responses) == 0 {
+		requestContext.Error = status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no endorsements were provided", nil)
+		return
+	}
+
+	requestContext.Response.Responses = h.responses
+	requestContext.Response.Payload = h.responses[0].ProposalResponse.GetResponse().Payload
+
+	//Delegate to next step if any
+	if h.next != nil {
+		h.next.Handle(requestContext, clientContext)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestExportedProposalEndorsement(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("value")
+	testPeer2 := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+	testPeer2.Payload = []byte("value")
+
+	// Our client only reaches Peer1, the other organisation's client only reaches Peer2
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+	otherClient := setupChannelClient([]fab.Peer{testPeer2}, t)
+
+	request := Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+	exported, err := chClient.ExportProposal(request)
+	if err != nil {
+		t.Fatalf("Failed to export proposal: %s", err)
+	}
+	assert.Equal(t, "testChannel", exported.ChannelID)
+	assert.Equal(t, "test", exported.ChaincodeID)
+
+	// Hand the proposal over in its portable format
+	proposalBytes, err := exported.Bytes()
+	if err != nil {
+		t.Fatalf("Failed to marshal exported proposal: %s", err)
+	}
+	imported, err := ParseExportedProposal(proposalBytes)
+	if err != nil {
+		t.Fatalf("Failed to parse exported proposal: %s", err)
+	}
+
+	otherEndorsements, err := otherClient.EndorseExportedProposal(imported)
+	if err != nil {
+		t.Fatalf("Failed to endorse exported proposal: %s", err)
+	}
+	if !assert.Len(t, otherEndorsements.Endorsements, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "http://peer2.com", otherEndorsements.Endorsements[0].Endorser)
+	assert.Equal(t, 1, testPeer2.ProcessProposalCalls)
+
+	endorsementBytes, err := otherEndorsements.Bytes()
+	if err != nil {
+		t.Fatalf("Failed to marshal exported endorsements: %s", err)
+	}
+	otherEndorsements, err = ParseExportedEndorsements(endorsementBytes)
+	if err != nil {
+		t.Fatalf("Failed to parse exported endorsements: %s", err)
+	}
+
+	ownEndorsements, err := chClient.EndorseExportedProposal(exported)
+	if err != nil {
+		t.Fatalf("Failed to endorse exported proposal: %s", err)
+	}
+
+	errch := make(chan error, 1)
+	go func() {
+		select {
+		case reg := <-mockEventService.TxStatusRegistrations:
+			reg.Eventch <- &fab.TxStatusEvent{TxID: reg.TxID, TxValidationCode: pb.TxValidationCode_VALID}
+			errch <- nil
+		case <-time.After(time.Second * 5):
+			errch <- errors.New("Timed out waiting for registration for TxStatus event")
+		}
+	}()
+
+	// Commit retries are ignored since they would need a new proposal
+	retryOpts := retry.Opts{Commit: retry.CommitOpts{Attempts: 2}}
+	response, err := chClient.ExecuteWithEndorsements(exported, []*ExportedEndorsements{ownEndorsements, otherEndorsements, ownEndorsements}, WithRetry(retryOpts))
+	if err != nil {
+		t.Fatalf("Failed to execute with endorsements: %s", err)
+	}
+	if err := <-errch; err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, exported.TransactionID, response.TransactionID)
+	assert.Equal(t, pb.TxValidationCode_VALID, response.TxValidationCode)
+	assert.Equal(t, []byte("value"), response.Payload)
+	assert.Len(t, response.Responses, 2, "expected duplicate endorsements to be merged")
+}
+
+func TestExecuteWithEndorsementsNoRetry(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	request := Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+	exported, err := chClient.ExportProposal(request)
+	if err != nil {
+		t.Fatalf("Failed to export proposal: %s", err)
+	}
+	endorsements, err := chClient.EndorseExportedProposal(exported)
+	if err != nil {
+		t.Fatalf("Failed to endorse exported proposal: %s", err)
+	}
+
+	errch := make(chan error, 1)
+	go func() {
+		select {
+		case reg := <-mockEventService.TxStatusRegistrations:
+			reg.Eventch <- &fab.TxStatusEvent{TxID: reg.TxID, TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT}
+			errch <- nil
+		case <-time.After(time.Second * 5):
+			errch <- errors.New("Timed out waiting for registration for TxStatus event")
+		}
+	}()
+
+	// The retryable read conflict would only resubmit the same transaction
+	retryOpts := retry.DefaultOpts
+	retryOpts.RetryableCodes = retry.ChannelClientRetryableCodes
+	retryOpts.Commit = retry.CommitOpts{Attempts: 2}
+	_, err = chClient.ExecuteWithEndorsements(exported, []*ExportedEndorsements{endorsements}, WithRetry(retryOpts), WithTimeout(2*time.Second))
+	if err := <-errch; err != nil {
+		t.Fatal(err)
+	}
+	statusError, ok := status.FromError(err)
+	if assert.True(t, ok, "Expected status error got %+v", err) {
+		assert.Equal(t, status.EventServerStatus, statusError.Group)
+		assert.EqualValues(t, pb.TxValidationCode_MVCC_READ_CONFLICT, statusError.Code)
+	}
+	assert.Len(t, mockEventService.TxStatusRegistrations, 0, "expected the transaction not to be submitted again")
+}
+
+func TestExportedProposalErrors(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	_, err := chClient.ExportProposal(Request{Fcn: "invoke"})
+	assert.NotNil(t, err, "expected error for missing chaincode ID")
+
+	_, err = ParseExportedProposal([]byte(`{"version": 99}`))
+	assert.NotNil(t, err, "expected error for unsupported version")
+
+	exported, err := chClient.ExportProposal(Request{ChaincodeID: "test", Fcn: "invoke"})
+	if err != nil {
+		t.Fatalf("Failed to export proposal: %s", err)
+	}
+
+	tampered := *exported
+	tampered.TransactionID = "othertxid"
+	_, err = chClient.EndorseExportedProposal(&tampered)
+	assert.NotNil(t, err, "expected error for transaction ID that does not match the proposal")
+
+	otherTx := &ExportedEndorsements{Version: exportVersion, TransactionID: "othertxid"}
+	_, err = chClient.ExecuteWithEndorsements(exported, []*ExportedEndorsements{otherTx})
+	assert.NotNil(t, err, "expected error for endorsements of another transaction")
+
+	_, err = chClient.ExecuteWithEndorsements(exported, nil)
+	assert.NotNil(t, err, "expected error for missing endorsements")
+}
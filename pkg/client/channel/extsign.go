@@ -0,0 +1,202 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	reqContext "context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// UnsignedProposal is a chaincode invocation proposal which is signed outside of the SDK,
+// e.g. by a hardware security module or a remote signing service. The signature is
+// computed over Bytes with the key of the proposal's creator.
+type UnsignedProposal struct {
+	*txn.UnsignedProposal
+	Request Request
+}
+
+// UnsignedTransaction is a transaction, built from the endorsements of a signed proposal,
+// which is signed outside of the SDK. The signature is computed over Bytes with the key
+// of the proposal's creator.
+type UnsignedTransaction struct {
+	*txn.UnsignedTransaction
+	Response Response
+}
+
+// CreateUnsignedProposal creates a proposal for the request without signing it. creator is
+// the serialized identity (msp.SerializedIdentity) of the signer; if it is nil then the
+// identity of the client is used.
+func (cc *Client) CreateUnsignedProposal(request Request, creator []byte) (*UnsignedProposal, error) {
+	if request.ChaincodeID == "" || request.Fcn == "" {
+		return nil, errors.New("ChaincodeID and Fcn are required")
+	}
+
+	var txh fab.TransactionHeader
+	var err error
+	if creator == nil {
+		txh, err = cc.transactor.CreateTransactionHeader()
+	} else {
+		txh, err = txn.NewHeaderWithCreator(cc.context.CryptoSuite(), creator, cc.channel.Name())
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating transaction header failed")
+	}
+
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{
+		ChaincodeID:  request.ChaincodeID,
+		Fcn:          request.Fcn,
+		Args:         request.Args,
+		TransientMap: request.TransientMap,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating transaction proposal failed")
+	}
+
+	unsignedProposal, err := txn.NewUnsignedProposal(proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnsignedProposal{UnsignedProposal: unsignedProposal, Request: request}, nil
+}
+
+// SendSignedProposal sends the proposal, together with its signature, to the endorsers and
+// validates the endorsements. The endorsers are selected as for Execute unless they are
+// given with the WithProposalProcessor option.
+func (cc *Client) SendSignedProposal(proposal *UnsignedProposal, signature []byte, options ...Option) (Response, error) {
+	if proposal == nil {
+		return Response{}, errors.New("proposal is required")
+	}
+	if len(signature) == 0 {
+		return Response{}, errors.New("signature is required")
+	}
+
+	handler := NewProposalProcessorHandler(
+		&signedProposalEndorsementHandler{
+			proposal:       proposal.TransactionProposal,
+			signedProposal: proposal.Sign(signature),
+			next:           NewEndorsementValidationHandler(NewSignatureValidationHandler()),
+		},
+	)
+
+	return cc.InvokeHandler(handler, proposal.Request, cc.addDefaultTimeout(core.Execute, options...)...)
+}
+
+// CreateUnsignedTransaction creates the transaction for the endorsements returned by
+// SendSignedProposal without signing it.
+func (cc *Client) CreateUnsignedTransaction(response Response) (*UnsignedTransaction, error) {
+	if response.Proposal == nil {
+		return nil, errors.New("response does not contain a proposal")
+	}
+
+	tx, err := cc.transactor.CreateTransaction(fab.TransactionRequest{
+		Proposal:          response.Proposal,
+		ProposalResponses: response.Responses,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "CreateTransaction failed")
+	}
+
+	unsignedTx, err := txn.NewUnsignedTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnsignedTransaction{UnsignedTransaction: unsignedTx, Response: response}, nil
+}
+
+// SendSignedTransaction sends the transaction, together with its signature, to the orderer.
+// It returns as soon as the transaction has been accepted by the orderer; the commit status
+// is delivered in the background through the returned handle.
+func (cc *Client) SendSignedTransaction(tx *UnsignedTransaction, signature []byte, options ...Option) (*TxHandle, error) {
+	if tx == nil {
+		return nil, errors.New("transaction is required")
+	}
+	if len(signature) == 0 {
+		return nil, errors.New("signature is required")
+	}
+
+	txnOpts, err := cc.prepareOptsFromOptions(cc.addDefaultTimeout(core.Execute, options...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	reqCtx, cancel := reqContext.WithTimeout(reqContext.Background(), txnOpts.Timeout)
+	defer cancel()
+	requestContext := &RequestContext{Opts: txnOpts, Ctx: reqCtx}
+
+	ctx, cancel := requestContext.PhaseContext(OrderingPhase)
+	defer cancel()
+
+	txStatus, err := registerAndSendTransaction(cc.eventService, tx.TransactionID(), asyncCommitTimeout(txnOpts), func() error {
+		transactionResponse, err := cc.transactor.SendSignedTransaction(ctx, tx.Sign(signature))
+		if err != nil {
+			return errors.WithMessage(err, "SendSignedTransaction failed")
+		}
+		if transactionResponse.Err != nil {
+			logger.Debugf("orderer %s failed (%s)", transactionResponse.Orderer, transactionResponse.Err.Error())
+			return errors.Wrap(transactionResponse.Err, "orderer failed")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TxHandle{
+		TransactionID: tx.TransactionID(),
+		Payload:       tx.Response.Payload,
+		Proposal:      tx.Proposal,
+		Responses:     tx.Response.Responses,
+		status:        txStatus,
+	}, nil
+}
+
+// signedProposalEndorsementHandler sends a proposal which has already been signed to the endorsers
+type signedProposalEndorsementHandler struct {
+	proposal       *fab.TransactionProposal
+	signedProposal *pb.SignedProposal
+	next           Handler
+}
+
+//Handle for endorsing the signed proposal
+func (e *signedProposalEndorsementHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+
+	if len(requestContext.Opts.ProposalProcessors) == 0 {
+		requestContext.Error = status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "targets were not provided", nil)
+		return
+	}
+
+	ctx, cancel := requestContext.PhaseContext(EndorsementPhase)
+	defer cancel()
+
+	transactionProposalResponses, err := clientContext.Transactor.SendSignedTransactionProposal(ctx, e.signedProposal, requestContext.Opts.ProposalProcessors)
+
+	requestContext.Response.Proposal = e.proposal
+	requestContext.Response.TransactionID = e.proposal.TxnID
+
+	if err != nil {
+		requestContext.Error = err
+		return
+	}
+
+	requestContext.Response.Responses = transactionProposalResponses
+	if len(transactionProposalResponses) > 0 {
+		requestContext.Response.Payload = transactionProposalResponses[0].ProposalResponse.GetResponse().Payload
+	}
+
+	//Delegate to next step if any
+	if e.next != nil {
+		e.next.Handle(requestContext, clientContext)
+	}
+}
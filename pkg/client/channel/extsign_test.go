@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	reqContext "context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// recordingProcessor records the signed proposals sent to the peer
+type recordingProcessor struct {
+	*fcmocks.MockPeer
+	proposals []*pb.SignedProposal
+}
+
+func (p *recordingProcessor) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	p.proposals = append(p.proposals, request.SignedProposal)
+	return p.MockPeer.ProcessTransactionProposal(ctx, request)
+}
+
+func TestExternalSigning(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("value")
+	processor := &recordingProcessor{MockPeer: testPeer1}
+	broadcasts := make(chan *fab.SignedEnvelope, 1)
+
+	chClient := setupChannelClientWithNodes([]fab.Peer{testPeer1}, []fab.Orderer{fcmocks.NewMockOrderer("", broadcasts)}, t)
+	chClient.eventService = mockEventService
+
+	creator, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "Org2MSP", IdBytes: []byte("external")})
+	if err != nil {
+		t.Fatalf("Failed to marshal creator: %s", err)
+	}
+
+	request := Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+	proposal, err := chClient.CreateUnsignedProposal(request, creator)
+	if err != nil {
+		t.Fatalf("Failed to create unsigned proposal: %s", err)
+	}
+	assert.NotEmpty(t, proposal.TxnID)
+	assert.NotEmpty(t, proposal.Bytes)
+
+	response, err := chClient.SendSignedProposal(proposal, []byte("proposal signature"), WithProposalProcessor(processor))
+	if err != nil {
+		t.Fatalf("Failed to send signed proposal: %s", err)
+	}
+	assert.Equal(t, proposal.TxnID, response.TransactionID)
+	assert.Equal(t, []byte("value"), response.Payload)
+	if !assert.Len(t, processor.proposals, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, proposal.Bytes, processor.proposals[0].ProposalBytes)
+	assert.Equal(t, []byte("proposal signature"), processor.proposals[0].Signature)
+
+	tx, err := chClient.CreateUnsignedTransaction(response)
+	if err != nil {
+		t.Fatalf("Failed to create unsigned transaction: %s", err)
+	}
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(tx.Bytes, payload); err != nil {
+		t.Fatalf("Failed to unmarshal transaction payload: %s", err)
+	}
+	signatureHeader := &common.SignatureHeader{}
+	if err := proto.Unmarshal(payload.Header.SignatureHeader, signatureHeader); err != nil {
+		t.Fatalf("Failed to unmarshal signature header: %s", err)
+	}
+	assert.Equal(t, creator, signatureHeader.Creator, "expected transaction to be created by the external signer")
+
+	handle, err := chClient.SendSignedTransaction(tx, []byte("tx signature"))
+	if err != nil {
+		t.Fatalf("Failed to send signed transaction: %s", err)
+	}
+	assert.Equal(t, proposal.TxnID, handle.TransactionID)
+	assert.Equal(t, []byte("value"), handle.Payload)
+
+	select {
+	case envelope := <-broadcasts:
+		assert.Equal(t, tx.Bytes, envelope.Payload)
+		assert.Equal(t, []byte("tx signature"), envelope.Signature)
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for the transaction to be broadcast")
+	}
+
+	select {
+	case reg := <-mockEventService.TxStatusRegistrations:
+		assert.Equal(t, string(handle.TransactionID), reg.TxID)
+		reg.Eventch <- &fab.TxStatusEvent{TxID: reg.TxID, TxValidationCode: pb.TxValidationCode_VALID}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for registration for TxStatus event")
+	}
+
+	code, err := handle.Wait(time.Second * 5)
+	assert.Nil(t, err)
+	assert.Equal(t, pb.TxValidationCode_VALID, code)
+}
+
+func TestExternalSigningErrors(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	_, err := chClient.CreateUnsignedProposal(Request{ChaincodeID: "test"}, nil)
+	assert.NotNil(t, err, "expected error for missing function")
+
+	proposal, err := chClient.CreateUnsignedProposal(Request{ChaincodeID: "test", Fcn: "invoke"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create unsigned proposal: %s", err)
+	}
+	_, err = chClient.SendSignedProposal(proposal, nil)
+	assert.NotNil(t, err, "expected error for missing signature")
+
+	_, err = chClient.CreateUnsignedTransaction(Response{})
+	assert.NotNil(t, err, "expected error for missing proposal")
+
+	_, err = chClient.SendSignedTransaction(nil, []byte("signature"))
+	assert.NotNil(t, err, "expected error for missing transaction")
+}
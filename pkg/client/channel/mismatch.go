@@ -0,0 +1,303 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+// DiffType is the kind of difference between endorsement groups
+type DiffType string
+
+const (
+	// PayloadDiff the chaincode response payloads differ
+	PayloadDiff DiffType = "payload"
+	// ReadDiff the version of a key that was read differs
+	ReadDiff DiffType = "read"
+	// WriteDiff the value written to a key differs
+	WriteDiff DiffType = "write"
+	// DeleteDiff a key was deleted by some endorsers only
+	DeleteDiff DiffType = "delete"
+	// EventDiff the chaincode events differ
+	EventDiff DiffType = "event"
+)
+
+// absentValue is reported for a group that did not read, write or delete a key
+const absentValue = "<absent>"
+
+// EndorserInfo identifies an endorser
+type EndorserInfo struct {
+	URL   string
+	MSPID string
+}
+
+// EndorsementGroup is a set of endorsers that returned identical responses
+type EndorsementGroup struct {
+	Endorsers []EndorserInfo
+	// Payload is the chaincode response payload
+	Payload []byte
+	// Action is the decoded chaincode action or nil if it could not be decoded
+	Action *rwset.ChaincodeAction
+}
+
+// EndorsementDiff is a difference between endorsement groups. Values holds
+// the value of each group, in the same order as EndorsementMismatch.Groups.
+type EndorsementDiff struct {
+	Type      DiffType
+	Namespace string
+	Key       string
+	Values    []string
+}
+
+// EndorsementMismatch describes endorsements that do not match. It is the detail of
+// the EndorsementMismatch status returned by the channel client.
+type EndorsementMismatch struct {
+	Groups []*EndorsementGroup
+	Diffs  []*EndorsementDiff
+}
+
+// ReadVersionsDiffer returns true if the endorsers read different versions of the same key,
+// which usually means that a peer has not yet committed the latest blocks. If only the payload,
+// writes or events differ then the chaincode is likely non-deterministic.
+func (m *EndorsementMismatch) ReadVersionsDiffer() bool {
+	for _, d := range m.Diffs {
+		if d.Type == ReadDiff {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a summary of the groups and differences
+func (m *EndorsementMismatch) String() string {
+	groups := make([]string, len(m.Groups))
+	for i, g := range m.Groups {
+		endorsers := make([]string, len(g.Endorsers))
+		for j, e := range g.Endorsers {
+			endorsers[j] = fmt.Sprintf("%s (%s)", e.URL, e.MSPID)
+		}
+		groups[i] = fmt.Sprintf("group %d: [%s]", i+1, strings.Join(endorsers, ", "))
+	}
+
+	diffs := make([]string, len(m.Diffs))
+	for i, d := range m.Diffs {
+		if d.Type == PayloadDiff || d.Type == EventDiff {
+			diffs[i] = fmt.Sprintf("%s [%s]", d.Type, strings.Join(d.Values, " | "))
+		} else {
+			diffs[i] = fmt.Sprintf("%s %s/%s [%s]", d.Type, d.Namespace, d.Key, strings.Join(d.Values, " | "))
+		}
+	}
+
+	return fmt.Sprintf("%s; differences: %s", strings.Join(groups, ", "), strings.Join(diffs, ", "))
+}
+
+// newEndorsementMismatchStatus returns an EndorsementMismatch status if the chaincode response
+// payloads differ, or nil if all of them match. Only the response payloads decide whether the
+// endorsements match; the read-write sets are decoded to describe the mismatch in the message
+// and the details of the status.
+func newEndorsementMismatchStatus(responses []*fab.TransactionProposalResponse) *status.Status {
+	if len(responses) == 0 {
+		return nil
+	}
+	payload := responses[0].ProposalResponse.GetResponse().GetPayload()
+	match := true
+	for _, r := range responses[1:] {
+		if !bytes.Equal(payload, r.ProposalResponse.GetResponse().GetPayload()) {
+			match = false
+			break
+		}
+	}
+	if match {
+		return nil
+	}
+
+	mismatch := newEndorsementMismatch(responses)
+	logger.Debugf("endorsement mismatch: %s", mismatch)
+
+	return status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(),
+		fmt.Sprintf("ProposalResponsePayloads do not match: %s", mismatch), []interface{}{mismatch})
+}
+
+func newEndorsementMismatch(responses []*fab.TransactionProposalResponse) *EndorsementMismatch {
+	mismatch := &EndorsementMismatch{}
+
+	var groupPayloads [][]byte
+	for _, r := range responses {
+		endorser := EndorserInfo{URL: r.Endorser, MSPID: endorserMSPID(r)}
+		payload := r.ProposalResponse.GetResponse().GetPayload()
+
+		found := false
+		for i, g := range mismatch.Groups {
+			if bytes.Equal(g.Payload, payload) && bytes.Equal(groupPayloads[i], r.ProposalResponse.GetPayload()) {
+				g.Endorsers = append(g.Endorsers, endorser)
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		group := &EndorsementGroup{Endorsers: []EndorserInfo{endorser}, Payload: payload}
+		action, err := rwset.FromProposalResponse(r.ProposalResponse)
+		if err != nil {
+			logger.Debugf("unable to decode chaincode action from endorser [%s]: %s", r.Endorser, err)
+		} else {
+			group.Action = action
+		}
+		mismatch.Groups = append(mismatch.Groups, group)
+		groupPayloads = append(groupPayloads, r.ProposalResponse.GetPayload())
+	}
+
+	if len(mismatch.Groups) > 1 {
+		mismatch.Diffs = diffGroups(mismatch.Groups)
+	}
+	return mismatch
+}
+
+func endorserMSPID(r *fab.TransactionProposalResponse) string {
+	endorsement := r.ProposalResponse.GetEndorsement()
+	if endorsement == nil {
+		return ""
+	}
+	serializedIdentity := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(endorsement.Endorser, serializedIdentity); err != nil {
+		return ""
+	}
+	return serializedIdentity.Mspid
+}
+
+type nsKey struct {
+	namespace string
+	key       string
+}
+
+// groupValues holds the values of a single group that are compared across groups
+type groupValues struct {
+	reads   map[nsKey]string
+	writes  map[nsKey]string
+	deletes map[nsKey]string
+	event   string
+}
+
+func newGroupValues(g *EndorsementGroup) *groupValues {
+	values := &groupValues{
+		reads:   make(map[nsKey]string),
+		writes:  make(map[nsKey]string),
+		deletes: make(map[nsKey]string),
+		event:   absentValue,
+	}
+	if g.Action == nil {
+		return values
+	}
+
+	if g.Action.Event != nil {
+		values.event = fmt.Sprintf("%s:%s", g.Action.Event.EventName, g.Action.Event.Payload)
+	}
+
+	if g.Action.RWSet == nil {
+		return values
+	}
+
+	for _, ns := range g.Action.RWSet.NsRWSets {
+		for _, r := range ns.Reads {
+			version := "nil"
+			if r.Version != nil {
+				version = fmt.Sprintf("%d:%d", r.Version.BlockNum, r.Version.TxNum)
+			}
+			values.reads[nsKey{ns.Namespace, r.Key}] = version
+		}
+		for _, w := range ns.Writes {
+			values.writes[nsKey{ns.Namespace, w.Key}] = string(w.Value)
+		}
+		for _, d := range ns.Deletes {
+			values.deletes[nsKey{ns.Namespace, d.Key}] = "deleted"
+		}
+	}
+	return values
+}
+
+func diffGroups(groups []*EndorsementGroup) []*EndorsementDiff {
+	values := make([]*groupValues, len(groups))
+	payloads := make([]string, len(groups))
+	events := make([]string, len(groups))
+	for i, g := range groups {
+		values[i] = newGroupValues(g)
+		payloads[i] = string(g.Payload)
+		events[i] = values[i].event
+	}
+
+	var diffs []*EndorsementDiff
+	if !allEqual(payloads) {
+		diffs = append(diffs, &EndorsementDiff{Type: PayloadDiff, Values: payloads})
+	}
+
+	diffs = append(diffs, diffKeys(ReadDiff, values, func(v *groupValues) map[nsKey]string { return v.reads })...)
+	diffs = append(diffs, diffKeys(WriteDiff, values, func(v *groupValues) map[nsKey]string { return v.writes })...)
+	diffs = append(diffs, diffKeys(DeleteDiff, values, func(v *groupValues) map[nsKey]string { return v.deletes })...)
+
+	if !allEqual(events) {
+		diffs = append(diffs, &EndorsementDiff{Type: EventDiff, Values: events})
+	}
+
+	return diffs
+}
+
+func diffKeys(diffType DiffType, values []*groupValues, get func(*groupValues) map[nsKey]string) []*EndorsementDiff {
+	keySet := make(map[nsKey]bool)
+	for _, v := range values {
+		for k := range get(v) {
+			keySet[k] = true
+		}
+	}
+
+	keys := make([]nsKey, 0, len(keySet))
+	for k := range keySet {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].namespace != keys[j].namespace {
+			return keys[i].namespace < keys[j].namespace
+		}
+		return keys[i].key < keys[j].key
+	})
+
+	var diffs []*EndorsementDiff
+	for _, k := range keys {
+		vals := make([]string, len(values))
+		for i, v := range values {
+			val, ok := get(v)[k]
+			if !ok {
+				val = absentValue
+			}
+			vals[i] = val
+		}
+		if !allEqual(vals) {
+			diffs = append(diffs, &EndorsementDiff{Type: diffType, Namespace: k.namespace, Key: k.key, Values: vals})
+		}
+	}
+	return diffs
+}
+
+func allEqual(values []string) bool {
+	for _, v := range values[1:] {
+		if v != values[0] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+func TestEndorsementMismatchStaleRead(t *testing.T) {
+	responses := []*fab.TransactionProposalResponse{
+		newMismatchTestResponse("peer1", "Org1MSP", 5, "100"),
+		newMismatchTestResponse("peer2", "Org2MSP", 5, "100"),
+		newMismatchTestResponse("peer3", "Org2MSP", 4, "90"),
+	}
+
+	s := newEndorsementMismatchStatus(responses)
+	if s == nil {
+		t.Fatal("Expected endorsement mismatch")
+	}
+	assert.EqualValues(t, status.EndorsementMismatch.ToInt32(), s.Code)
+	assert.Equal(t, status.EndorserClientStatus, s.Group)
+
+	mismatch := s.Details[0].(*EndorsementMismatch)
+	assert.Equal(t, "ProposalResponsePayloads do not match: "+mismatch.String(), s.Message)
+	assert.True(t, strings.Contains(mismatch.String(), "group 1: [peer1 (Org1MSP), peer2 (Org2MSP)], group 2: [peer3 (Org2MSP)]"), mismatch.String())
+	if !assert.Len(t, mismatch.Groups, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, []EndorserInfo{{URL: "peer1", MSPID: "Org1MSP"}, {URL: "peer2", MSPID: "Org2MSP"}}, mismatch.Groups[0].Endorsers)
+	assert.Equal(t, []EndorserInfo{{URL: "peer3", MSPID: "Org2MSP"}}, mismatch.Groups[1].Endorsers)
+
+	if !assert.Len(t, mismatch.Diffs, 3) {
+		t.FailNow()
+	}
+	assert.Equal(t, &EndorsementDiff{Type: ReadDiff, Namespace: "testCC", Key: "a", Values: []string{"5:0", "4:0"}}, mismatch.Diffs[1])
+	assert.True(t, mismatch.ReadVersionsDiffer())
+}
+
+func TestEndorsementMismatchNonDeterministic(t *testing.T) {
+	responses := []*fab.TransactionProposalResponse{
+		newMismatchTestResponse("peer1", "Org1MSP", 5, "100"),
+		newMismatchTestResponse("peer2", "Org2MSP", 5, "200"),
+	}
+
+	s := newEndorsementMismatchStatus(responses)
+	if s == nil {
+		t.Fatal("Expected endorsement mismatch")
+	}
+
+	mismatch := s.Details[0].(*EndorsementMismatch)
+	if !assert.Len(t, mismatch.Diffs, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, &EndorsementDiff{Type: PayloadDiff, Values: []string{"100", "200"}}, mismatch.Diffs[0])
+	assert.Equal(t, &EndorsementDiff{Type: WriteDiff, Namespace: "testCC", Key: "b", Values: []string{"100", "200"}}, mismatch.Diffs[1])
+	assert.False(t, mismatch.ReadVersionsDiffer())
+}
+
+func TestEndorsementMismatchNone(t *testing.T) {
+	responses := []*fab.TransactionProposalResponse{
+		newMismatchTestResponse("peer1", "Org1MSP", 5, "100"),
+		newMismatchTestResponse("peer2", "Org2MSP", 5, "100"),
+	}
+	assert.Nil(t, newEndorsementMismatchStatus(responses))
+
+	// only the response payloads decide whether the endorsements match
+	responses = append(responses, newMismatchTestResponse("peer3", "Org2MSP", 4, "100"))
+	assert.Nil(t, newEndorsementMismatchStatus(responses))
+}
+
+func newMismatchTestResponse(url string, mspID string, readBlock uint64, value string) *fab.TransactionProposalResponse {
+	kvRWSet := &kvrwset.KVRWSet{
+		Reads:  []*kvrwset.KVRead{{Key: "a", Version: &kvrwset.Version{BlockNum: readBlock}}},
+		Writes: []*kvrwset.KVWrite{{Key: "b", Value: []byte(value)}},
+	}
+	txRWSet := &rwset.TxReadWriteSet{
+		NsRwset: []*rwset.NsReadWriteSet{{Namespace: "testCC", Rwset: protos_utils.MarshalOrPanic(kvRWSet)}},
+	}
+	response := &pb.Response{Status: 200, Payload: []byte(value)}
+	ccAction := &pb.ChaincodeAction{Results: protos_utils.MarshalOrPanic(txRWSet), Response: response}
+	payload := protos_utils.MarshalOrPanic(&pb.ProposalResponsePayload{Extension: protos_utils.MarshalOrPanic(ccAction)})
+	endorser := protos_utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID})
+
+	return &fab.TransactionProposalResponse{
+		Endorser: url,
+		Status:   200,
+		ProposalResponse: &pb.ProposalResponse{
+			Response:    response,
+			Payload:     payload,
+			Endorsement: &pb.Endorsement{Endorser: endorser},
+		},
+	}
+}
@@ -0,0 +1,337 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	fabmsp "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+const (
+	ccDataProviderSCC      = "lscc"
+	ccDataProviderFunction = "getccdata"
+)
+
+// PolicyProvider retrieves the endorsement policy for the given chaincode ID
+type PolicyProvider interface {
+	GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error)
+}
+
+//NewEndorsementPolicyHandler returns a handler that checks the endorsements against the chaincode's
+//endorsement policy. If policyProvider is nil then the policy is queried from lscc on the endorsing peers
+//and cached by the handler per chaincode version, so that the policy of an upgraded chaincode is queried
+//again. The policy is not cached if the endorsements do not report the chaincode version.
+func NewEndorsementPolicyHandler(policyProvider PolicyProvider, next ...Handler) *EndorsementPolicyHandler {
+	return &EndorsementPolicyHandler{
+		next:           getNext(next),
+		policyProvider: policyProvider,
+		policies:       make(map[string]*common.SignaturePolicyEnvelope),
+	}
+}
+
+//EndorsementPolicyHandler checks that the endorsements satisfy the chaincode's endorsement policy,
+//so that a transaction that would be rejected at commit is not sent to the orderer
+type EndorsementPolicyHandler struct {
+	next           Handler
+	policyProvider PolicyProvider
+	policies       map[string]*common.SignaturePolicyEnvelope
+	lock           sync.RWMutex
+}
+
+//Handle for checking the endorsement policy
+func (h *EndorsementPolicyHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+
+	policy, err := h.chaincodePolicy(requestContext, clientContext)
+	if err != nil {
+		requestContext.Error = errors.WithMessage(err, "failed to retrieve endorsement policy")
+		return
+	}
+
+	err = checkEndorsementPolicy(policy, requestContext.Response.Responses, clientContext)
+	if err != nil {
+		requestContext.Error = errors.WithMessage(err, "endorsement policy check failed")
+		return
+	}
+
+	// Delegate to next step if any
+	if h.next != nil {
+		h.next.Handle(requestContext, clientContext)
+	}
+}
+
+func (h *EndorsementPolicyHandler) chaincodePolicy(requestContext *RequestContext, clientContext *ClientContext) (*common.SignaturePolicyEnvelope, error) {
+	ccID := requestContext.Request.ChaincodeID
+
+	if h.policyProvider != nil {
+		return h.policyProvider.GetChaincodePolicy(ccID)
+	}
+
+	version := endorsedChaincodeVersion(requestContext.Response.Responses)
+	if version == "" {
+		return queryChaincodePolicy(requestContext, clientContext)
+	}
+	key := ccID + ":" + version
+
+	h.lock.RLock()
+	policy, ok := h.policies[key]
+	h.lock.RUnlock()
+	if ok {
+		return policy, nil
+	}
+
+	policy, err := queryChaincodePolicy(requestContext, clientContext)
+	if err != nil {
+		return nil, err
+	}
+
+	h.lock.Lock()
+	h.policies[key] = policy
+	h.lock.Unlock()
+
+	return policy, nil
+}
+
+// endorsedChaincodeVersion returns the chaincode version reported in the chaincode action of the
+// endorsements, or an empty string if no endorsement reports it
+func endorsedChaincodeVersion(responses []*fab.TransactionProposalResponse) string {
+	for _, r := range responses {
+		prp, err := protos_utils.GetProposalResponsePayload(r.ProposalResponse.GetPayload())
+		if err != nil {
+			continue
+		}
+		ccAction, err := protos_utils.GetChaincodeAction(prp.Extension)
+		if err != nil {
+			continue
+		}
+		if version := ccAction.GetChaincodeId().GetVersion(); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// queryChaincodePolicy retrieves the chaincode data from lscc on the peers that endorsed the request
+func queryChaincodePolicy(requestContext *RequestContext, clientContext *ClientContext) (*common.SignaturePolicyEnvelope, error) {
+	if clientContext.Channel == nil {
+		return nil, errors.New("channel is required to query the chaincode policy")
+	}
+
+	ccID := requestContext.Request.ChaincodeID
+	request := ChaincodeDataRequest(clientContext.Channel.Name(), ccID)
+
+	ctx, cancel := requestContext.PhaseContext(EndorsementPhase)
+	defer cancel()
+
+	responses, _, err := createAndSendTransactionProposal(ctx, clientContext.Transactor, &request, requestContext.Opts.ProposalProcessors)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error querying chaincode data for chaincode [%s]", ccID))
+	}
+
+	for _, r := range responses {
+		if r.ProposalResponse.GetResponse().Status != int32(common.Status_SUCCESS) {
+			continue
+		}
+
+		return ChaincodePolicyFromData(r.ProposalResponse.GetResponse().Payload)
+	}
+
+	return nil, errors.Errorf("no peer returned chaincode data for chaincode [%s]", ccID)
+}
+
+// ChaincodeDataRequest returns the request which queries lscc for the chaincode data, including the
+// endorsement policy, of the chaincode on the channel
+func ChaincodeDataRequest(channelID string, chaincodeID string) Request {
+	return Request{
+		ChaincodeID: ccDataProviderSCC,
+		Fcn:         ccDataProviderFunction,
+		Args:        [][]byte{[]byte(channelID), []byte(chaincodeID)},
+	}
+}
+
+// ChaincodePolicyFromData returns the endorsement policy of the chaincode data in the payload of
+// the response to a ChaincodeDataRequest
+func ChaincodePolicyFromData(payload []byte) (*common.SignaturePolicyEnvelope, error) {
+	ccData := &ccprovider.ChaincodeData{}
+	if err := proto.Unmarshal(payload, ccData); err != nil {
+		return nil, errors.WithMessage(err, "error unmarshalling chaincode data")
+	}
+
+	policy := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(ccData.Policy, policy); err != nil {
+		return nil, errors.WithMessage(err, "error unmarshalling SignaturePolicyEnvelope")
+	}
+	return policy, nil
+}
+
+// checkEndorsementPolicy evaluates the endorser identities against the policy. If the policy
+// is not satisfied then the returned status lists the principals that no endorsement satisfied.
+func checkEndorsementPolicy(policy *common.SignaturePolicyEnvelope, responses []*fab.TransactionProposalResponse, clientContext *ClientContext) error {
+	if policy == nil || policy.Rule == nil {
+		return errors.New("endorsement policy is empty")
+	}
+
+	endorsers := make([]*policyEndorser, len(responses))
+	for i, r := range responses {
+		endorser, err := newPolicyEndorser(r, clientContext)
+		if err != nil {
+			return err
+		}
+		endorsers[i] = endorser
+	}
+
+	eval := &policyEvaluator{identities: policy.Identities, endorsers: endorsers}
+	satisfied, err := eval.evaluate(policy.Rule, make([]bool, len(endorsers)))
+	if err != nil {
+		return err
+	}
+	if satisfied {
+		return nil
+	}
+
+	details := make([]interface{}, len(eval.unsatisfied))
+	for i, p := range eval.unsatisfied {
+		details[i] = p
+	}
+	return status.New(status.EndorserClientStatus, status.EndorsementPolicyNotSatisfied.ToInt32(),
+		fmt.Sprintf("endorsements do not satisfy the endorsement policy, unsatisfied principals: [%s]", strings.Join(eval.unsatisfied, ", ")), details)
+}
+
+type policyEndorser struct {
+	url      string
+	mspID    string
+	identity fabmsp.Identity
+}
+
+func newPolicyEndorser(r *fab.TransactionProposalResponse, clientContext *ClientContext) (*policyEndorser, error) {
+	endorsement := r.ProposalResponse.GetEndorsement()
+	if endorsement == nil {
+		return nil, errors.Errorf("Missing endorsement in proposal response from %s", r.Endorser)
+	}
+
+	mspID, identity, err := deserializeEndorser(endorsement.Endorser, clientContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return &policyEndorser{url: r.Endorser, mspID: mspID, identity: identity}, nil
+}
+
+func (e *policyEndorser) satisfies(principal *msp.MSPPrincipal) bool {
+	mspID, err := principalMSPID(principal)
+	if err == nil && mspID != "" && mspID != e.mspID {
+		return false
+	}
+	return e.identity.SatisfiesPrincipal(principal) == nil
+}
+
+// policyEvaluator evaluates a signature policy in the same way as the committing peer,
+// where each endorsement may be used to satisfy at most one principal
+type policyEvaluator struct {
+	identities  []*msp.MSPPrincipal
+	endorsers   []*policyEndorser
+	unsatisfied []string
+}
+
+func (e *policyEvaluator) evaluate(policy *common.SignaturePolicy, used []bool) (bool, error) {
+	switch t := policy.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(e.identities) {
+			return false, errors.Errorf("identity index out of range, requested %d, but identities length is %d", t.SignedBy, len(e.identities))
+		}
+		principal := e.identities[t.SignedBy]
+		for i, endorser := range e.endorsers {
+			if used[i] {
+				continue
+			}
+			if endorser.satisfies(principal) {
+				logger.Debugf("principal [%s] satisfied by endorser [%s]", principalString(principal), endorser.url)
+				used[i] = true
+				return true, nil
+			}
+		}
+		e.unsatisfied = append(e.unsatisfied, principalString(principal))
+		return false, nil
+
+	case *common.SignaturePolicy_NOutOf_:
+		verified := int32(0)
+		_used := make([]bool, len(used))
+		for _, rule := range t.NOutOf.Rules {
+			copy(_used, used)
+			ok, err := e.evaluate(rule, _used)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				verified++
+				copy(used, _used)
+			}
+		}
+		return verified >= t.NOutOf.N, nil
+
+	default:
+		return false, errors.Errorf("unsupported signature policy type: %T", t)
+	}
+}
+
+func principalMSPID(principal *msp.MSPPrincipal) (string, error) {
+	switch principal.PrincipalClassification {
+	case msp.MSPPrincipal_ROLE:
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return "", errors.WithMessage(err, "unable to unmarshal MSPRole")
+		}
+		return role.MspIdentifier, nil
+	case msp.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &msp.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return "", errors.WithMessage(err, "unable to unmarshal OrganizationUnit")
+		}
+		return ou.MspIdentifier, nil
+	case msp.MSPPrincipal_IDENTITY:
+		identity := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, identity); err != nil {
+			return "", errors.WithMessage(err, "unable to unmarshal SerializedIdentity")
+		}
+		return identity.Mspid, nil
+	default:
+		return "", errors.Errorf("unknown principal classification: %s", principal.PrincipalClassification)
+	}
+}
+
+// principalString returns a readable form of the principal, e.g. Org1MSP.member
+func principalString(principal *msp.MSPPrincipal) string {
+	switch principal.PrincipalClassification {
+	case msp.MSPPrincipal_ROLE:
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err == nil {
+			return fmt.Sprintf("%s.%s", role.MspIdentifier, strings.ToLower(role.Role.String()))
+		}
+	case msp.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &msp.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err == nil {
+			return fmt.Sprintf("%s.OU(%s)", ou.MspIdentifier, ou.OrganizationalUnitIdentifier)
+		}
+	case msp.MSPPrincipal_IDENTITY:
+		identity := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, identity); err == nil {
+			return fmt.Sprintf("%s.identity", identity.Mspid)
+		}
+	}
+	return principal.PrincipalClassification.String()
+}
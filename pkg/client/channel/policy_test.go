@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	fabmsp "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+type mockPolicyProvider struct {
+	policy *common.SignaturePolicyEnvelope
+}
+
+func (p *mockPolicyProvider) GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error) {
+	return p.policy, nil
+}
+
+func TestEndorsementPolicyHandlerSatisfied(t *testing.T) {
+	policy := newTestPolicy(2, t, "Org1MSP", "Org2MSP")
+
+	peer1 := newTestPolicyPeer("Peer1", "Org1MSP", nil, t)
+	peer2 := newTestPolicyPeer("Peer2", "Org2MSP", nil, t)
+
+	requestContext, clientContext := prepareEndorsementPolicyContexts([]fab.Peer{peer1, peer2}, t)
+
+	handler := NewEndorsementPolicyHandler(&mockPolicyProvider{policy: policy})
+	handler.Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+}
+
+func TestEndorsementPolicyHandlerNotSatisfied(t *testing.T) {
+	policy := newTestPolicy(2, t, "Org1MSP", "Org2MSP")
+
+	// Both endorsements come from Org1, so only one of them can be counted
+	peer1 := newTestPolicyPeer("Peer1", "Org1MSP", nil, t)
+	peer2 := newTestPolicyPeer("Peer2", "Org1MSP", nil, t)
+
+	requestContext, clientContext := prepareEndorsementPolicyContexts([]fab.Peer{peer1, peer2}, t)
+
+	handler := NewEndorsementPolicyHandler(&mockPolicyProvider{policy: policy})
+	handler.Handle(requestContext, clientContext)
+	verifyExpectedError(requestContext, "unsatisfied principals: [Org2MSP.member]", t)
+
+	s, ok := status.FromError(requestContext.Error)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.EndorsementPolicyNotSatisfied.ToInt32(), s.Code)
+	assert.Equal(t, []interface{}{"Org2MSP.member"}, s.Details)
+}
+
+func TestEndorsementPolicyHandlerOneOf(t *testing.T) {
+	policy := newTestPolicy(1, t, "Org2MSP", "Org3MSP")
+
+	peer1 := newTestPolicyPeer("Peer1", "Org1MSP", nil, t)
+	requestContext, clientContext := prepareEndorsementPolicyContexts([]fab.Peer{peer1}, t)
+
+	handler := NewEndorsementPolicyHandler(&mockPolicyProvider{policy: policy})
+	handler.Handle(requestContext, clientContext)
+	verifyExpectedError(requestContext, "unsatisfied principals: [Org2MSP.member, Org3MSP.member]", t)
+}
+
+func TestEndorsementPolicyHandlerQueryPolicy(t *testing.T) {
+	policyBytes, err := proto.Marshal(newTestPolicy(1, t, "Org1MSP"))
+	if err != nil {
+		t.Fatalf("Failed to marshal policy: %s", err)
+	}
+	ccData, err := proto.Marshal(&ccprovider.ChaincodeData{Name: "testCC", Policy: policyBytes})
+	if err != nil {
+		t.Fatalf("Failed to marshal chaincode data: %s", err)
+	}
+
+	peer1 := newTestPolicyPeer("Peer1", "Org1MSP", ccData, t)
+	requestContext, clientContext := prepareEndorsementPolicyContexts([]fab.Peer{peer1}, t)
+
+	setEndorsedChaincodeVersion(requestContext, "v1", t)
+
+	handler := NewEndorsementPolicyHandler(nil)
+	handler.Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+	assert.Equal(t, 2, peer1.ProcessProposalCalls)
+
+	// The policy is cached by the handler for the chaincode version
+	handler.Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+	assert.Equal(t, 2, peer1.ProcessProposalCalls)
+
+	// The policy of an upgraded chaincode is queried again
+	setEndorsedChaincodeVersion(requestContext, "v2", t)
+	handler.Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+	assert.Equal(t, 3, peer1.ProcessProposalCalls)
+
+	// The policy is not cached if the endorsements do not report the chaincode version
+	setEndorsedChaincodeVersion(requestContext, "", t)
+	handler.Handle(requestContext, clientContext)
+	handler.Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+	assert.Equal(t, 5, peer1.ProcessProposalCalls)
+}
+
+// setEndorsedChaincodeVersion sets the chaincode version reported in the chaincode action of the endorsements
+func setEndorsedChaincodeVersion(requestContext *RequestContext, version string, t *testing.T) {
+	ccAction, err := proto.Marshal(&pb.ChaincodeAction{ChaincodeId: &pb.ChaincodeID{Name: "testCC", Version: version}})
+	if err != nil {
+		t.Fatalf("Failed to marshal chaincode action: %s", err)
+	}
+	payload, err := proto.Marshal(&pb.ProposalResponsePayload{Extension: ccAction})
+	if err != nil {
+		t.Fatalf("Failed to marshal proposal response payload: %s", err)
+	}
+	for _, r := range requestContext.Response.Responses {
+		r.ProposalResponse.Payload = payload
+	}
+}
+
+func prepareEndorsementPolicyContexts(peers []fab.Peer, t *testing.T) (*RequestContext, *ClientContext) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+	requestContext := prepareRequestContext(request, Opts{}, t)
+
+	msps := make(map[string]fabmsp.MSP)
+	msps["Org1MSP"] = fcmocks.NewMockMSP(nil)
+	msps["Org2MSP"] = fcmocks.NewMockMSP(nil)
+	clientContext := setupContextForSignatureValidation(fcmocks.NewMockMSPManager(msps), peers, t)
+
+	// Endorse the request so that the handler has proposal responses to check
+	NewProposalProcessorHandler(NewEndorsementHandler()).Handle(requestContext, clientContext)
+	if requestContext.Error != nil {
+		t.Fatalf("Failed to endorse request: %s", requestContext.Error)
+	}
+
+	return requestContext, clientContext
+}
+
+func newTestPolicyPeer(name string, mspID string, payload []byte, t *testing.T) *fcmocks.MockPeer {
+	endorser, err := proto.Marshal(&msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(certPem)})
+	if err != nil {
+		t.Fatalf("Failed to marshal endorser: %s", err)
+	}
+	return &fcmocks.MockPeer{MockName: name, MockURL: "http://" + name + ".com", MockMSP: mspID, Status: 200, Payload: payload, Endorser: endorser}
+}
+
+// newTestPolicy returns an n-out-of policy over the member roles of the given MSPs
+func newTestPolicy(n int32, t *testing.T, mspIDs ...string) *common.SignaturePolicyEnvelope {
+	var rules []*common.SignaturePolicy
+	var identities []*msp.MSPPrincipal
+	for i, mspID := range mspIDs {
+		role, err := proto.Marshal(&msp.MSPRole{MspIdentifier: mspID, Role: msp.MSPRole_MEMBER})
+		if err != nil {
+			t.Fatalf("Failed to marshal role: %s", err)
+		}
+		identities = append(identities, &msp.MSPPrincipal{PrincipalClassification: msp.MSPPrincipal_ROLE, Principal: role})
+		rules = append(rules, &common.SignaturePolicy{Type: &common.SignaturePolicy_SignedBy{SignedBy: int32(i)}})
+	}
+
+	return &common.SignaturePolicyEnvelope{
+		Version:    0,
+		Rule:       &common.SignaturePolicy{Type: &common.SignaturePolicy_NOutOf_{NOutOf: &common.SignaturePolicy_NOutOf{N: n, Rules: rules}}},
+		Identities: identities,
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	fabmsp "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
@@ -67,32 +68,9 @@ func verifyProposalResponse(res *pb.ProposalResponse, ctx *ClientContext) error
 		return errors.Errorf("Missing endorsement in proposal response")
 	}
 
-	serializedIdentity := &msp.SerializedIdentity{}
-	if err := proto.Unmarshal(res.GetEndorsement().Endorser, serializedIdentity); err != nil {
-		return errors.WithMessage(err, "Unmarshal endorser error")
-	}
-
-	// TODO ctx.Channel is temporary and needs to be replaced with an MSP interface from channel service.
-	if ctx.Channel.MSPManager() == nil {
-		return errors.Errorf("Channel %s msp manager is nil", ctx.Channel.Name())
-	}
-
-	msps, err := ctx.Channel.MSPManager().GetMSPs()
-	if err != nil {
-		return errors.WithMessage(err, "GetMSPs return error:%v")
-	}
-	if len(msps) == 0 {
-		return errors.Errorf("Channel %s msps is empty", ctx.Channel.Name())
-	}
-
-	msp := msps[serializedIdentity.Mspid]
-	if msp == nil {
-		return errors.Errorf("MSP %s not found", serializedIdentity.Mspid)
-	}
-
-	creator, err := msp.DeserializeIdentity(res.GetEndorsement().Endorser)
+	_, creator, err := deserializeEndorser(res.GetEndorsement().Endorser, ctx)
 	if err != nil {
-		return errors.WithMessage(err, "Failed to deserialize creator identity")
+		return err
 	}
 
 	// ensure that creator is a valid certificate
@@ -112,3 +90,40 @@ func verifyProposalResponse(res *pb.ProposalResponse, ctx *ClientContext) error
 
 	return nil
 }
+
+// deserializeEndorser returns the MSP ID and the identity of the endorser, which is deserialized
+// by the MSP of the endorser on the channel
+func deserializeEndorser(endorser []byte, ctx *ClientContext) (string, fabmsp.Identity, error) {
+	serializedIdentity := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(endorser, serializedIdentity); err != nil {
+		return "", nil, errors.WithMessage(err, "Unmarshal endorser error")
+	}
+
+	// TODO ctx.Channel is temporary and needs to be replaced with an MSP interface from channel service.
+	if ctx.Channel == nil {
+		return "", nil, errors.New("channel is required to deserialize the endorser")
+	}
+	if ctx.Channel.MSPManager() == nil {
+		return "", nil, errors.Errorf("Channel %s msp manager is nil", ctx.Channel.Name())
+	}
+
+	msps, err := ctx.Channel.MSPManager().GetMSPs()
+	if err != nil {
+		return "", nil, errors.WithMessage(err, "GetMSPs return error:%v")
+	}
+	if len(msps) == 0 {
+		return "", nil, errors.Errorf("Channel %s msps is empty", ctx.Channel.Name())
+	}
+
+	mspImpl := msps[serializedIdentity.Mspid]
+	if mspImpl == nil {
+		return "", nil, errors.Errorf("MSP %s not found", serializedIdentity.Mspid)
+	}
+
+	identity, err := mspImpl.DeserializeIdentity(endorser)
+	if err != nil {
+		return "", nil, errors.WithMessage(err, "Failed to deserialize creator identity")
+	}
+
+	return serializedIdentity.Mspid, identity, nil
+}
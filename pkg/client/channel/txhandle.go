@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// TxHandle is returned by ExecuteAsync once the transaction has been accepted by the orderer.
+// It holds the endorsement results and provides access to the commit status, which
+// is delivered in the background.
+type TxHandle struct {
+	TransactionID fab.TransactionID
+	Payload       []byte
+	Proposal      *fab.TransactionProposal
+	Responses     []*fab.TransactionProposalResponse
+
+	status *txStatus
+}
+
+// Done returns a channel that is closed once the transaction has been committed
+// (or rejected) by the peers
+func (h *TxHandle) Done() <-chan struct{} {
+	return h.status.done
+}
+
+// Status returns the final validation code of the transaction. It must only be called
+// after the channel returned by Done has been closed.
+func (h *TxHandle) Status() (pb.TxValidationCode, error) {
+	return h.status.code, h.status.err
+}
+
+// Wait blocks until the transaction has been committed or the given timeout expires
+// and returns the final validation code of the transaction. The commit status is still
+// awaited in the background after a timeout; call Close to stop waiting for it.
+func (h *TxHandle) Wait(timeout time.Duration) (pb.TxValidationCode, error) {
+	select {
+	case <-h.status.done:
+		return h.Status()
+	case <-time.After(timeout):
+		return pb.TxValidationCode_INVALID_OTHER_REASON, status.New(status.ClientStatus, status.Timeout.ToInt32(),
+			"timed out waiting for transaction status", []interface{}{h.TransactionID})
+	}
+}
+
+// Close stops waiting for the commit status of the transaction and releases its event
+// registration. The status of a transaction which has not been committed yet is set to
+// a canceled error.
+func (h *TxHandle) Close() {
+	h.status.set(pb.TxValidationCode_INVALID_OTHER_REASON, status.New(status.ClientStatus, status.Canceled.ToInt32(),
+		"stopped waiting for transaction status", []interface{}{h.TransactionID}))
+}
+
+// Response returns the transaction response. It must only be called after the
+// channel returned by Done has been closed.
+func (h *TxHandle) Response() Response {
+	return Response{
+		Payload:          h.Payload,
+		TransactionID:    h.TransactionID,
+		TxValidationCode: h.status.code,
+		Proposal:         h.Proposal,
+		Responses:        h.Responses,
+	}
+}
+
+// txStatus is completed by the event hub callback when the transaction is committed
+type txStatus struct {
+	once sync.Once
+	done chan struct{}
+	code pb.TxValidationCode
+	err  error
+}
+
+func newTxStatus() *txStatus {
+	return &txStatus{done: make(chan struct{})}
+}
+
+func (s *txStatus) set(code pb.TxValidationCode, err error) {
+	s.once.Do(func() {
+		s.code = code
+		s.err = err
+		close(s.done)
+	})
+}
@@ -7,16 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"time"
 
-	"bytes"
-
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 )
 
@@ -33,8 +33,11 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 		return
 	}
 
+	ctx, cancel := requestContext.PhaseContext(EndorsementPhase)
+	defer cancel()
+
 	// Endorse Tx
-	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(clientContext.Transactor, &requestContext.Request, requestContext.Opts.ProposalProcessors)
+	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(ctx, clientContext.Transactor, &requestContext.Request, requestContext.Opts.ProposalProcessors)
 
 	requestContext.Response.Proposal = proposal
 	requestContext.Response.TransactionID = proposal.TxnID // TODO: still needed?
@@ -110,20 +113,14 @@ func (f *EndorsementValidationHandler) Handle(requestContext *RequestContext, cl
 }
 
 func (f *EndorsementValidationHandler) validate(txProposalResponse []*fab.TransactionProposalResponse) error {
-	var a1 []byte
-	for n, r := range txProposalResponse {
+	for _, r := range txProposalResponse {
 		if r.ProposalResponse.GetResponse().Status != int32(common.Status_SUCCESS) {
 			return status.NewFromProposalResponse(r.ProposalResponse, r.Endorser)
 		}
-		if n == 0 {
-			a1 = r.ProposalResponse.GetResponse().Payload
-			continue
-		}
+	}
 
-		if bytes.Compare(a1, r.ProposalResponse.GetResponse().Payload) != 0 {
-			return status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(),
-				"ProposalResponsePayloads do not match", nil)
-		}
+	if s := newEndorsementMismatchStatus(txProposalResponse); s != nil {
+		return s
 	}
 
 	return nil
@@ -137,34 +134,30 @@ type CommitTxHandler struct {
 //Handle handles commit tx
 func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
 
-	//Connect to Event hub if not yet connected
-	if clientContext.EventHub.IsConnected() == false {
-		err := clientContext.EventHub.Connect()
-		if err != nil {
-			requestContext.Error = err
-			return
-		}
-	}
-
-	txnID := requestContext.Response.TransactionID
-
-	//Register Tx event
-	statusNotifier := txn.RegisterStatus(txnID, clientContext.EventHub)
-	_, err := createAndSendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
-	if err != nil {
-		requestContext.Error = errors.Wrap(err, "CreateAndSendTransaction failed")
+	txStatus := submitTransaction(requestContext, clientContext, 0)
+	if txStatus == nil {
 		return
 	}
 
+	ctx, cancel := requestContext.PhaseContext(CommitPhase)
+	defer cancel()
+
 	select {
-	case result := <-statusNotifier:
-		requestContext.Response.TxValidationCode = result.Code
+	case <-txStatus.done:
+		requestContext.Response.TxValidationCode = txStatus.code
 
-		if result.Error != nil {
-			requestContext.Error = result.Error
+		if txStatus.err != nil {
+			requestContext.Error = txStatus.err
+			return
+		}
+	case <-ctx.Done():
+		// Release the event registration
+		txStatus.set(pb.TxValidationCode_INVALID_OTHER_REASON, ctx.Err())
+		if ctx.Err() == reqContext.Canceled {
+			requestContext.Error = status.New(status.ClientStatus, status.Canceled.ToInt32(),
+				"request canceled while waiting for block event", nil)
 			return
 		}
-	case <-time.After(requestContext.Opts.Timeout):
 		requestContext.Error = errors.New("Execute didn't receive block event")
 		return
 	}
@@ -175,6 +168,112 @@ func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *
 	}
 }
 
+//SubmitTxHandler for sending transactions to the orderer without waiting for them to be committed
+type SubmitTxHandler struct {
+	next Handler
+}
+
+//Handle handles submit tx
+func (c *SubmitTxHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+
+	txStatus := submitTransaction(requestContext, clientContext, asyncCommitTimeout(requestContext.Opts))
+	if txStatus == nil {
+		return
+	}
+	requestContext.Response.txStatus = txStatus
+
+	//Delegate to next step if any
+	if c.next != nil {
+		c.next.Handle(requestContext, clientContext)
+	}
+}
+
+// asyncCommitTimeout returns how long the commit status of a transaction is awaited in the
+// background: the commit phase timeout if one was set, otherwise the request timeout.
+func asyncCommitTimeout(opts Opts) time.Duration {
+	if timeout, ok := opts.PhaseTimeouts[CommitPhase]; ok && timeout > 0 {
+		return timeout
+	}
+	return opts.Timeout
+}
+
+// submitTransaction registers for the commit status of the transaction and sends it to the orderer.
+// The status is awaited for at most commitTimeout, or until it is completed elsewhere if zero.
+// On failure the error is set on the request context and nil is returned.
+func submitTransaction(requestContext *RequestContext, clientContext *ClientContext, commitTimeout time.Duration) *txStatus {
+	ctx, cancel := requestContext.PhaseContext(OrderingPhase)
+	defer cancel()
+
+	txStatus, err := registerAndSendTransaction(clientContext.EventService, requestContext.Response.TransactionID, commitTimeout, func() error {
+		_, err := createAndSendTransaction(ctx, clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
+		if err != nil {
+			return errors.Wrap(err, "CreateAndSendTransaction failed")
+		}
+		return nil
+	})
+	if err != nil {
+		requestContext.Error = err
+		return nil
+	}
+
+	return txStatus
+}
+
+// registerAndSendTransaction registers for the commit status of the transaction before sending
+// it with the given function, so that the status cannot be missed. If commitTimeout is not zero
+// the status is set to a timeout error when it is not received in time.
+func registerAndSendTransaction(eventService fab.EventService, txnID fab.TransactionID, commitTimeout time.Duration, send func() error) (*txStatus, error) {
+	//Register Tx event
+	reg, statusNotifier, err := eventService.RegisterTxStatusEvent(string(txnID))
+	if err != nil {
+		return nil, errors.WithMessage(err, "error registering for TxStatus event")
+	}
+
+	txStatus := newTxStatus()
+	go waitForTxStatus(eventService, reg, statusNotifier, txStatus, commitTimeout)
+
+	if err := send(); err != nil {
+		// Release the event registration
+		txStatus.set(pb.TxValidationCode_INVALID_OTHER_REASON, err)
+		return nil, err
+	}
+
+	return txStatus, nil
+}
+
+// waitForTxStatus completes the transaction status once it is delivered by the event service
+// and then removes the registration. It also returns if the status is completed elsewhere,
+// e.g. if the transaction could not be sent or the caller stopped waiting for the commit,
+// or once the commit timeout, if not zero, expires.
+func waitForTxStatus(eventService fab.EventService, reg fab.Registration, statusNotifier <-chan *fab.TxStatusEvent, txStatus *txStatus, commitTimeout time.Duration) {
+	defer eventService.Unregister(reg)
+
+	var expired <-chan time.Time
+	if commitTimeout > 0 {
+		timer := time.NewTimer(commitTimeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case event, ok := <-statusNotifier:
+		if !ok {
+			txStatus.set(pb.TxValidationCode_INVALID_OTHER_REASON, errors.New("event registration was closed before the transaction status was received"))
+			return
+		}
+		logger.Debugf("Received code(%s) for txid(%s)\n", event.TxValidationCode, event.TxID)
+		if event.TxValidationCode != pb.TxValidationCode_VALID {
+			txStatus.set(event.TxValidationCode, status.New(status.EventServerStatus, int32(event.TxValidationCode), "received invalid transaction", nil))
+			return
+		}
+		txStatus.set(event.TxValidationCode, nil)
+	case <-expired:
+		txStatus.set(pb.TxValidationCode_INVALID_OTHER_REASON, status.New(status.ClientStatus, status.Timeout.ToInt32(),
+			"timed out waiting for transaction status", nil))
+	case <-txStatus.done:
+	}
+}
+
 //NewQueryHandler returns query handler with EndorseTxHandler & EndorsementValidationHandler Chained
 func NewQueryHandler(next ...Handler) Handler {
 	return NewProposalProcessorHandler(
@@ -197,6 +296,17 @@ func NewExecuteHandler(next ...Handler) Handler {
 	)
 }
 
+//NewExecuteAsyncHandler returns a handler with EndorseTxHandler, EndorsementValidationHandler & SubmitTxHandler Chained
+func NewExecuteAsyncHandler(next ...Handler) Handler {
+	return NewProposalProcessorHandler(
+		NewEndorsementHandler(
+			NewEndorsementValidationHandler(
+				NewSignatureValidationHandler(NewSubmitHandler(next...)),
+			),
+		),
+	)
+}
+
 //NewProposalProcessorHandler returns a handler that selects proposal processors
 func NewProposalProcessorHandler(next ...Handler) *ProposalProcessorHandler {
 	return &ProposalProcessorHandler{next: getNext(next)}
@@ -217,6 +327,11 @@ func NewCommitHandler(next ...Handler) *CommitTxHandler {
 	return &CommitTxHandler{next: getNext(next)}
 }
 
+//NewSubmitHandler returns a handler that sends transaction proposal responses to the orderer
+func NewSubmitHandler(next ...Handler) *SubmitTxHandler {
+	return &SubmitTxHandler{next: getNext(next)}
+}
+
 func getNext(next []Handler) Handler {
 	if len(next) > 0 {
 		return next[0]
@@ -224,7 +339,7 @@ func getNext(next []Handler) Handler {
 	return nil
 }
 
-func createAndSendTransaction(sender fab.Sender, proposal *fab.TransactionProposal, resps []*fab.TransactionProposalResponse) (*fab.TransactionResponse, error) {
+func createAndSendTransaction(ctx reqContext.Context, sender fab.Sender, proposal *fab.TransactionProposal, resps []*fab.TransactionProposalResponse) (*fab.TransactionResponse, error) {
 
 	txnRequest := fab.TransactionRequest{
 		Proposal:          proposal,
@@ -236,7 +351,7 @@ func createAndSendTransaction(sender fab.Sender, proposal *fab.TransactionPropos
 		return nil, errors.WithMessage(err, "CreateTransaction failed")
 	}
 
-	transactionResponse, err := sender.SendTransaction(tx)
+	transactionResponse, err := sender.SendTransaction(ctx, tx)
 	if err != nil {
 		return nil, errors.WithMessage(err, "SendTransaction failed")
 
@@ -249,7 +364,7 @@ func createAndSendTransaction(sender fab.Sender, proposal *fab.TransactionPropos
 	return transactionResponse, nil
 }
 
-func createAndSendTransactionProposal(transactor fab.Transactor, chrequest *Request, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, *fab.TransactionProposal, error) {
+func createAndSendTransactionProposal(ctx reqContext.Context, transactor fab.Transactor, chrequest *Request, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, *fab.TransactionProposal, error) {
 	request := fab.ChaincodeInvokeRequest{
 		ChaincodeID:  chrequest.ChaincodeID,
 		Fcn:          chrequest.Fcn,
@@ -264,6 +379,6 @@ func createAndSendTransactionProposal(transactor fab.Transactor, chrequest *Requ
 		return nil, nil, errors.WithMessage(err, "creating transaction proposal failed")
 	}
 
-	transactionProposalResponses, err := transactor.SendTransactionProposal(proposal, targets)
+	transactionProposalResponses, err := transactor.SendTransactionProposal(ctx, proposal, targets)
 	return transactionProposalResponses, proposal, err
 }
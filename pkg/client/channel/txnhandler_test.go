@@ -21,6 +21,7 @@ import (
 
 	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
 const (
@@ -67,16 +68,18 @@ func TestExecuteTxHandlerSuccess(t *testing.T) {
 
 	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{mockPeer1, mockPeer2}, t)
 
-	//Prepare mock eventhub
-	mockEventHub := fcmocks.NewMockEventHub()
-	clientContext.EventHub = mockEventHub
+	//Prepare mock event service
+	mockEventService := fcmocks.NewMockEventService()
+	clientContext.EventService = mockEventService
 
+	errch := make(chan error, 1)
 	go func() {
 		select {
-		case callback := <-mockEventHub.RegisteredTxCallbacks:
-			callback("txid", 0, nil)
+		case reg := <-mockEventService.TxStatusRegistrations:
+			reg.Eventch <- &fab.TxStatusEvent{TxID: reg.TxID, TxValidationCode: pb.TxValidationCode_VALID}
+			errch <- nil
 		case <-time.After(requestContext.Opts.Timeout):
-			t.Fatal("Execute handler : time out not expected")
+			errch <- errors.New("Execute handler : time out not expected")
 		}
 	}()
 
@@ -84,6 +87,9 @@ func TestExecuteTxHandlerSuccess(t *testing.T) {
 	executeHandler := NewExecuteHandler()
 	//Perform action through handler
 	executeHandler.Handle(requestContext, clientContext)
+	if err := <-errch; err != nil {
+		t.Fatal(err)
+	}
 	assert.Nil(t, requestContext.Error)
 }
 
@@ -9,6 +9,7 @@ package staticdiscovery
 import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/pkg/errors"
@@ -20,7 +21,8 @@ import (
 
 // DiscoveryProvider implements discovery provider
 type DiscoveryProvider struct {
-	config core.Config
+	config    core.Config
+	connector *comm.CachingConnector
 }
 
 // discoveryService implements discovery service
@@ -34,6 +36,11 @@ func New(config core.Config) (*DiscoveryProvider, error) {
 	return &DiscoveryProvider{config: config}, nil
 }
 
+// InitConnector sets the connection cache which is used by the discovered peers
+func (dp *DiscoveryProvider) InitConnector(connector *comm.CachingConnector) {
+	dp.connector = connector
+}
+
 // NewDiscoveryService return discovery service for specific channel
 func (dp *DiscoveryProvider) NewDiscoveryService(channelID string) (fab.DiscoveryService, error) {
 
@@ -49,7 +56,7 @@ func (dp *DiscoveryProvider) NewDiscoveryService(channelID string) (fab.Discover
 
 		for _, p := range chPeers {
 
-			newPeer, err := peer.New(dp.config, peer.FromPeerConfig(&p.NetworkPeer))
+			newPeer, err := peer.New(dp.config, peer.FromPeerConfig(&p.NetworkPeer), peer.WithConnector(dp.connector))
 			if err != nil || newPeer == nil {
 				return nil, errors.WithMessage(err, "NewPeer failed")
 			}
@@ -65,7 +72,7 @@ func (dp *DiscoveryProvider) NewDiscoveryService(channelID string) (fab.Discover
 		}
 
 		for _, p := range netPeers {
-			newPeer, err := peer.New(dp.config, peer.FromPeerConfig(&p))
+			newPeer, err := peer.New(dp.config, peer.FromPeerConfig(&p), peer.WithConnector(dp.connector))
 			if err != nil {
 				return nil, errors.WithMessage(err, "NewPeerFromConfig failed")
 			}
@@ -7,9 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package mocks
 
 import (
+	reqContext "context"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 )
 
@@ -31,8 +34,21 @@ func (t *MockTransactor) CreateTransactionHeader() (fab.TransactionHeader, error
 }
 
 // SendTransactionProposal sends a TransactionProposal to the target peers.
-func (t *MockTransactor) SendTransactionProposal(proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
-	return txn.SendProposal(t.Ctx, proposal, targets)
+func (t *MockTransactor) SendTransactionProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	return txn.SendProposal(reqCtx, t.Ctx, proposal, targets)
+}
+
+// SignTransactionProposal signs a TransactionProposal with the identity of the current context.
+func (t *MockTransactor) SignTransactionProposal(proposal *fab.TransactionProposal) (*pb.SignedProposal, error) {
+	if proposal == nil {
+		return nil, errors.New("proposal is required")
+	}
+	return txn.SignProposal(t.Ctx, proposal.Proposal)
+}
+
+// SendSignedTransactionProposal sends a TransactionProposal, which has been signed outside of the SDK, to the target peers.
+func (t *MockTransactor) SendSignedTransactionProposal(reqCtx reqContext.Context, proposal *pb.SignedProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	return txn.SendSignedProposal(reqCtx, proposal, targets)
 }
 
 // CreateTransaction create a transaction with proposal response.
@@ -41,6 +57,11 @@ func (t *MockTransactor) CreateTransaction(request fab.TransactionRequest) (*fab
 }
 
 // SendTransaction send a transaction to the chain’s orderer service (one or more orderer endpoints) for consensus and committing to the ledger.
-func (t *MockTransactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
-	return txn.Send(t.Ctx, tx, t.Orderers)
+func (t *MockTransactor) SendTransaction(reqCtx reqContext.Context, tx *fab.Transaction) (*fab.TransactionResponse, error) {
+	return txn.Send(reqCtx, t.Ctx, tx, t.Orderers)
+}
+
+// SendSignedTransaction sends a transaction envelope, which has been signed outside of the SDK, to the chain's orderer service.
+func (t *MockTransactor) SendSignedTransaction(reqCtx reqContext.Context, envelope *fab.SignedEnvelope) (*fab.TransactionResponse, error) {
+	return txn.SendEnvelope(reqCtx, envelope, t.Orderers)
 }
@@ -12,25 +12,19 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	peerImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
 	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
-	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 )
 
 var logger = logging.NewLogger("fabric_sdk_go")
 
-const (
-	ccDataProviderSCC      = "lscc"
-	ccDataProviderfunction = "getccdata"
-)
-
 // CCPolicyProvider retrieves policy for the given chaincode ID
 type CCPolicyProvider interface {
 	GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error)
@@ -54,15 +48,16 @@ func newCCPolicyProvider(sdk *fabsdk.FabricSDK, channelID string, userName strin
 		return nil, errors.WithMessage(err, "unable to read configuration for channel peers")
 	}
 
-	return &ccPolicyProvider{config: sdk.Config(), client: client, channelID: channelID, targetPeers: targetPeers, ccDataMap: make(map[string]*ccprovider.ChaincodeData)}, nil
+	return &ccPolicyProvider{config: sdk.Config(), connector: comm.ContextConnector(sdk.FabricProvider()), client: client, channelID: channelID, targetPeers: targetPeers, policies: make(map[string]*common.SignaturePolicyEnvelope)}, nil
 }
 
 type ccPolicyProvider struct {
 	config      core.Config
+	connector   *comm.CachingConnector
 	client      *fabsdk.ClientContext
 	channelID   string
 	targetPeers []core.ChannelPeer
-	ccDataMap   map[string]*ccprovider.ChaincodeData // TODO: Add expiry and configurable timeout for map entries
+	policies    map[string]*common.SignaturePolicyEnvelope // TODO: Add expiry and configurable timeout for map entries
 	mutex       sync.RWMutex
 }
 
@@ -71,46 +66,34 @@ func (dp *ccPolicyProvider) GetChaincodePolicy(chaincodeID string) (*common.Sign
 		return nil, errors.New("Must provide chaincode ID")
 	}
 
-	key := newResolverKey(dp.channelID, chaincodeID)
-	var ccData *ccprovider.ChaincodeData
+	key := newResolverKey(dp.channelID, chaincodeID).String()
 
 	dp.mutex.RLock()
-	ccData = dp.ccDataMap[chaincodeID]
+	policy := dp.policies[key]
 	dp.mutex.RUnlock()
-	if ccData != nil {
-		return unmarshalPolicy(ccData.Policy)
+	if policy != nil {
+		return policy, nil
 	}
 
 	dp.mutex.Lock()
 	defer dp.mutex.Unlock()
 
-	response, err := dp.queryChaincode(ccDataProviderSCC, ccDataProviderfunction, [][]byte{[]byte(dp.channelID), []byte(chaincodeID)})
+	response, err := dp.queryChaincode(channel.ChaincodeDataRequest(dp.channelID, chaincodeID))
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("error querying chaincode data for chaincode [%s] on channel [%s]", chaincodeID, dp.channelID))
 	}
 
-	ccData = &ccprovider.ChaincodeData{}
-	err = proto.Unmarshal(response, ccData)
+	policy, err = channel.ChaincodePolicyFromData(response)
 	if err != nil {
-		return nil, errors.WithMessage(err, "Error unmarshalling chaincode data")
+		return nil, err
 	}
 
-	dp.ccDataMap[key.String()] = ccData
+	dp.policies[key] = policy
 
-	return unmarshalPolicy(ccData.Policy)
+	return policy, nil
 }
 
-func unmarshalPolicy(policy []byte) (*common.SignaturePolicyEnvelope, error) {
-
-	sigPolicyEnv := &common.SignaturePolicyEnvelope{}
-	if err := proto.Unmarshal(policy, sigPolicyEnv); err != nil {
-		return nil, errors.WithMessage(err, "error unmarshalling SignaturePolicyEnvelope")
-	}
-
-	return sigPolicyEnv, nil
-}
-
-func (dp *ccPolicyProvider) queryChaincode(ccID string, ccFcn string, ccArgs [][]byte) ([]byte, error) {
+func (dp *ccPolicyProvider) queryChaincode(request channel.Request) ([]byte, error) {
 	logger.Debugf("queryChaincode channelID:%s", dp.channelID)
 
 	var queryErrors []string
@@ -123,19 +106,13 @@ func (dp *ccPolicyProvider) queryChaincode(ccID string, ccFcn string, ccArgs [][
 
 	for _, p := range dp.targetPeers {
 
-		peer, err := peerImpl.New(dp.config, peerImpl.FromPeerConfig(&p.NetworkPeer))
+		peer, err := peerImpl.New(dp.config, peerImpl.FromPeerConfig(&p.NetworkPeer), peerImpl.WithConnector(dp.connector))
 		if err != nil {
 			queryErrors = append(queryErrors, err.Error())
 			continue
 		}
 
 		// Send query to channel peer
-		request := channel.Request{
-			ChaincodeID: ccID,
-			Fcn:         ccFcn,
-			Args:        ccArgs,
-		}
-
 		resp, err := client.Query(request, channel.WithProposalProcessor(peer))
 		if err != nil {
 			queryErrors = append(queryErrors, err.Error())
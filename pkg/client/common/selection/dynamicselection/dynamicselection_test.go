@@ -242,7 +242,11 @@ func newMockCCDataProvider(channelID string) *mockCCDataProvider {
 }
 
 func (p *mockCCDataProvider) GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error) {
-	return unmarshalPolicy(p.ccData[newResolverKey(p.channelID, chaincodeID).String()].Policy)
+	policy := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(p.ccData[newResolverKey(p.channelID, chaincodeID).String()].Policy, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
 }
 
 func (p *mockCCDataProvider) add(chaincodeID string, policy *ccprovider.ChaincodeData) *mockCCDataProvider {
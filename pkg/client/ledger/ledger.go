@@ -0,0 +1,367 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ledger enables queries of the ledger of a channel on a Fabric network.
+//
+// Each query is sent to several peers of the channel. The result is only returned if enough
+// peers agree on it; peers which disagree or whose ledger is behind are reported with the result.
+package ledger
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+// Client enables ledger queries on a channel.
+//
+// An application that queries multiple channels should create a separate instance of the
+// ledger client for each channel.
+type Client struct {
+	channelID string
+	discovery fab.DiscoveryService
+	ledger    fab.ChannelLedger
+	filter    fab.TargetFilter
+}
+
+// Context holds the services needed to create a Client.
+type Context struct {
+	ChannelID        string
+	DiscoveryService fab.DiscoveryService
+	ChannelService   fab.ChannelService
+}
+
+// Opts contains options for ledger queries
+type Opts struct {
+	Targets      []fab.Peer       // target peers
+	TargetFilter fab.TargetFilter // target filter
+	MinTargets   int              // minimum number of targets which have to return matching responses (1 if not set)
+	MaxTargets   int              // maximum number of targets which are queried (all if not set)
+}
+
+// RequestOption func for each Opts argument
+type RequestOption func(opts *Opts) error
+
+// ClientOption describes a functional parameter for the New constructor
+type ClientOption func(*Client) error
+
+// WithDefaultTargetFilter sets the filter which is applied to the discovered peers if a request
+// does not have its own filter
+func WithDefaultTargetFilter(filter fab.TargetFilter) ClientOption {
+	return func(c *Client) error {
+		c.filter = filter
+		return nil
+	}
+}
+
+// InfoResponse contains the blockchain info which the target peers agree on
+type InfoResponse struct {
+	Info *common.BlockchainInfo
+	Report
+}
+
+// BlockResponse contains the block which the target peers agree on
+type BlockResponse struct {
+	Block *common.Block
+	Report
+}
+
+// TransactionResponse contains the processed transaction which the target peers agree on
+type TransactionResponse struct {
+	Transaction *pb.ProcessedTransaction
+	Report
+}
+
+// ConfigResponse contains the channel configuration which the target peers agree on
+type ConfigResponse struct {
+	Config *configtx.Config
+	Report
+}
+
+// New returns a ledger Client instance.
+func New(ctx Context, opts ...ClientOption) (*Client, error) {
+	if ctx.ChannelID == "" {
+		return nil, errors.New("must provide channel ID")
+	}
+
+	ledger, err := ctx.ChannelService.Ledger()
+	if err != nil {
+		return nil, errors.WithMessage(err, "ledger client creation failed")
+	}
+
+	client := &Client{
+		channelID: ctx.ChannelID,
+		discovery: ctx.DiscoveryService,
+		ledger:    ledger,
+	}
+	for _, opt := range opts {
+		if err := opt(client); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// QueryInfo queries the height and the current block hash of the ledger. The peers with the
+// highest ledger which is returned by the required number of targets are in agreement; peers
+// with a lower ledger are reported as behind.
+func (c *Client) QueryInfo(options ...RequestOption) (*InfoResponse, error) {
+	opts, targets, err := c.prepareRequest(options)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := queryTargets(targets, func(target fab.ProposalProcessor) (proto.Message, error) {
+		infos, err := c.ledger.QueryInfo([]fab.ProposalProcessor{target})
+		if err != nil {
+			return nil, err
+		}
+		if len(infos) == 0 {
+			return nil, errors.New("no response")
+		}
+		return infos[0], nil
+	})
+
+	groups := groupResponses(responses)
+	var result []*peerResponse
+	for _, group := range groupsByHeight(groups) {
+		if len(group) >= opts.MinTargets {
+			result = group
+			break
+		}
+	}
+	if result == nil {
+		return nil, newVerificationError(opts.MinTargets, responses, largestGroup(groups))
+	}
+
+	info := result[0].msg.(*common.BlockchainInfo)
+	for _, r := range responses {
+		if r.err == nil && r.msg.(*common.BlockchainInfo).Height < info.Height {
+			r.behind = true
+		}
+	}
+	return &InfoResponse{Info: info, Report: c.report("QueryInfo", responses, result)}, nil
+}
+
+// QueryBlock queries the block with the given number. Targets which fail to return the block
+// because their ledger is not high enough are reported as behind.
+func (c *Client) QueryBlock(blockNumber uint64, options ...RequestOption) (*BlockResponse, error) {
+	opts, targets, err := c.prepareRequest(options)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := queryTargets(targets, func(target fab.ProposalProcessor) (proto.Message, error) {
+		blocks, err := c.ledger.QueryBlock(int(blockNumber), []fab.ProposalProcessor{target})
+		if err != nil {
+			return nil, err
+		}
+		if len(blocks) == 0 {
+			return nil, errors.New("no response")
+		}
+		return blocks[0], nil
+	})
+	c.markBehind(responses, blockNumber)
+
+	result, err := c.verify(opts, responses)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockResponse{Block: result[0].msg.(*common.Block), Report: c.report("QueryBlock", responses, result)}, nil
+}
+
+// QueryBlockByHash queries the block with the given hash.
+func (c *Client) QueryBlockByHash(blockHash []byte, options ...RequestOption) (*BlockResponse, error) {
+	if len(blockHash) == 0 {
+		return nil, errors.New("must provide block hash")
+	}
+
+	opts, targets, err := c.prepareRequest(options)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := queryTargets(targets, func(target fab.ProposalProcessor) (proto.Message, error) {
+		blocks, err := c.ledger.QueryBlockByHash(blockHash, []fab.ProposalProcessor{target})
+		if err != nil {
+			return nil, err
+		}
+		if len(blocks) == 0 {
+			return nil, errors.New("no response")
+		}
+		return blocks[0], nil
+	})
+
+	result, err := c.verify(opts, responses)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockResponse{Block: result[0].msg.(*common.Block), Report: c.report("QueryBlockByHash", responses, result)}, nil
+}
+
+// QueryTransaction queries the processed transaction with the given ID.
+func (c *Client) QueryTransaction(transactionID fab.TransactionID, options ...RequestOption) (*TransactionResponse, error) {
+	if transactionID == "" {
+		return nil, errors.New("must provide transaction ID")
+	}
+
+	opts, targets, err := c.prepareRequest(options)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := queryTargets(targets, func(target fab.ProposalProcessor) (proto.Message, error) {
+		txs, err := c.ledger.QueryTransaction(transactionID, []fab.ProposalProcessor{target})
+		if err != nil {
+			return nil, err
+		}
+		if len(txs) == 0 {
+			return nil, errors.New("no response")
+		}
+		return txs[0], nil
+	})
+
+	result, err := c.verify(opts, responses)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionResponse{Transaction: result[0].msg.(*pb.ProcessedTransaction), Report: c.report("QueryTransaction", responses, result)}, nil
+}
+
+// QueryConfig queries the current configuration of the channel.
+func (c *Client) QueryConfig(options ...RequestOption) (*ConfigResponse, error) {
+	opts, targets, err := c.prepareRequest(options)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := queryTargets(targets, func(target fab.ProposalProcessor) (proto.Message, error) {
+		return c.ledger.QueryConfigBlock([]fab.ProposalProcessor{target}, 1)
+	})
+
+	result, err := c.verify(opts, responses)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := configtx.FromConfigEnvelope(c.channelID, result[0].msg.(*common.ConfigEnvelope))
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigResponse{Config: config, Report: c.report("QueryConfig", responses, result)}, nil
+}
+
+// prepareRequest applies the request options and determines the targets of the query
+func (c *Client) prepareRequest(options []RequestOption) (Opts, []fab.Peer, error) {
+	opts := Opts{}
+	for _, option := range options {
+		if err := option(&opts); err != nil {
+			return opts, nil, errors.WithMessage(err, "failed to read opts")
+		}
+	}
+	if opts.MinTargets <= 0 {
+		opts.MinTargets = 1
+	}
+
+	targets := opts.Targets
+	if len(targets) == 0 {
+		peers, err := c.discovery.GetPeers()
+		if err != nil {
+			return opts, nil, errors.WithMessage(err, "failed to discover peers")
+		}
+		targets = peers
+	}
+
+	filter := opts.TargetFilter
+	if filter == nil {
+		filter = c.filter
+	}
+	if filter != nil {
+		var filtered []fab.Peer
+		for _, target := range targets {
+			if filter.Accept(target) {
+				filtered = append(filtered, target)
+			}
+		}
+		targets = filtered
+	}
+
+	if opts.MaxTargets > 0 && len(targets) > opts.MaxTargets {
+		targets = targets[:opts.MaxTargets]
+	}
+
+	if len(targets) == 0 {
+		return opts, nil, errors.New("No targets available")
+	}
+	if len(targets) < opts.MinTargets {
+		return opts, nil, errors.Errorf("%d matching responses required but only %d targets available", opts.MinTargets, len(targets))
+	}
+	return opts, targets, nil
+}
+
+// verify returns the largest group of matching responses if it has the required size
+func (c *Client) verify(opts Opts, responses []*peerResponse) ([]*peerResponse, error) {
+	result := largestGroup(groupResponses(responses))
+	if len(result) < opts.MinTargets {
+		return nil, newVerificationError(opts.MinTargets, responses, result)
+	}
+	return result, nil
+}
+
+// markBehind marks the failed targets whose ledger does not contain the block as behind
+func (c *Client) markBehind(responses []*peerResponse, blockNumber uint64) {
+	var wg sync.WaitGroup
+	for _, r := range responses {
+		if r.err == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(r *peerResponse) {
+			defer wg.Done()
+			infos, err := c.ledger.QueryInfo([]fab.ProposalProcessor{r.peer})
+			if err == nil && len(infos) > 0 && infos[0].Height <= blockNumber {
+				r.behind = true
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
+func (c *Client) report(query string, responses []*peerResponse, result []*peerResponse) Report {
+	report := newReport(responses, result)
+	if len(report.Disagreeing) > 0 {
+		logger.Warnf("%s on channel %s: peers %v disagree with peers %v", query, c.channelID, report.Disagreeing, report.Matching)
+	}
+	if len(report.Behind) > 0 {
+		logger.Warnf("%s on channel %s: peers %v are behind", query, c.channelID, report.Behind)
+	}
+	return report
+}
+
+// queryTargets sends the query to each target concurrently
+func queryTargets(targets []fab.Peer, query func(target fab.ProposalProcessor) (proto.Message, error)) []*peerResponse {
+	responses := make([]*peerResponse, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		responses[i] = &peerResponse{peer: target}
+		wg.Add(1)
+		go func(r *peerResponse) {
+			defer wg.Done()
+			r.msg, r.err = query(r.peer)
+		}(responses[i])
+	}
+	wg.Wait()
+	return responses
+}
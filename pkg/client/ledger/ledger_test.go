@@ -0,0 +1,268 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const testChannel = "mychannel"
+
+var (
+	peer1 = fcmocks.NewMockPeer("peer1", "peer1.example.com:7051")
+	peer2 = fcmocks.NewMockPeer("peer2", "peer2.example.com:7051")
+	peer3 = fcmocks.NewMockPeer("peer3", "peer3.example.com:7051")
+	peer4 = fcmocks.NewMockPeer("peer4", "peer4.example.com:7051")
+	peer5 = fcmocks.NewMockPeer("peer5", "peer5.example.com:7051")
+)
+
+// mockLedger returns the responses of each peer by URL, or an error if there is none
+type mockLedger struct {
+	infos   map[string]*common.BlockchainInfo
+	blocks  map[string]*common.Block
+	txs     map[string]*pb.ProcessedTransaction
+	configs map[string]*common.ConfigEnvelope
+}
+
+func targetURL(targets []fab.ProposalProcessor) string {
+	return targets[0].(fab.Peer).URL()
+}
+
+func (l *mockLedger) QueryInfo(targets []fab.ProposalProcessor) ([]*common.BlockchainInfo, error) {
+	if info, ok := l.infos[targetURL(targets)]; ok {
+		return []*common.BlockchainInfo{info}, nil
+	}
+	return nil, errors.New("query failed")
+}
+
+func (l *mockLedger) QueryBlock(blockNumber int, targets []fab.ProposalProcessor) ([]*common.Block, error) {
+	if block, ok := l.blocks[targetURL(targets)]; ok && block.Header.Number == uint64(blockNumber) {
+		return []*common.Block{block}, nil
+	}
+	return nil, errors.New("block not found")
+}
+
+func (l *mockLedger) QueryBlockByHash(blockHash []byte, targets []fab.ProposalProcessor) ([]*common.Block, error) {
+	if block, ok := l.blocks[targetURL(targets)]; ok && string(block.Header.DataHash) == string(blockHash) {
+		return []*common.Block{block}, nil
+	}
+	return nil, errors.New("block not found")
+}
+
+func (l *mockLedger) QueryTransaction(transactionID fab.TransactionID, targets []fab.ProposalProcessor) ([]*pb.ProcessedTransaction, error) {
+	if tx, ok := l.txs[targetURL(targets)]; ok {
+		return []*pb.ProcessedTransaction{tx}, nil
+	}
+	return nil, errors.New("transaction not found")
+}
+
+func (l *mockLedger) QueryInstantiatedChaincodes(targets []fab.ProposalProcessor) ([]*pb.ChaincodeQueryResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (l *mockLedger) QueryConfigBlock(targets []fab.ProposalProcessor, minResponses int) (*common.ConfigEnvelope, error) {
+	if config, ok := l.configs[targetURL(targets)]; ok {
+		return config, nil
+	}
+	return nil, errors.New("config block not found")
+}
+
+func setupClient(t *testing.T, ledger fab.ChannelLedger, opts ...ClientOption) *Client {
+	ctx := fcmocks.NewMockContext(fcmocks.NewMockUser("test"))
+	chProvider, err := fcmocks.NewMockChannelProvider(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create channel provider: %s", err)
+	}
+	chProvider.SetLedger(ledger)
+	chService, err := chProvider.ChannelService(ctx, testChannel)
+	if err != nil {
+		t.Fatalf("Failed to create channel service: %s", err)
+	}
+
+	discovery := &mocks.MockStaticDiscoveryService{Peers: []fab.Peer{peer1, peer2, peer3, peer4, peer5}}
+	client, err := New(Context{ChannelID: testChannel, DiscoveryService: discovery, ChannelService: chService}, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create ledger client: %s", err)
+	}
+	return client
+}
+
+func TestQueryInfo(t *testing.T) {
+	current := &common.BlockchainInfo{Height: 10, CurrentBlockHash: []byte("hash10")}
+	client := setupClient(t, &mockLedger{infos: map[string]*common.BlockchainInfo{
+		peer1.URL(): current,
+		peer2.URL(): current,
+		peer3.URL(): {Height: 9, CurrentBlockHash: []byte("hash9")},
+		peer4.URL(): {Height: 10, CurrentBlockHash: []byte("fork")},
+	}})
+
+	resp, err := client.QueryInfo()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, current, resp.Info)
+	assert.Equal(t, []string{peer1.URL(), peer2.URL()}, resp.Matching)
+	assert.Equal(t, []string{peer3.URL()}, resp.Behind)
+	assert.Equal(t, []string{peer4.URL()}, resp.Disagreeing)
+	assert.Len(t, resp.Failed, 1)
+	assert.Contains(t, resp.Failed, peer5.URL())
+
+	_, err = client.QueryInfo(WithMinTargets(3))
+	verr, ok := errors.Cause(err).(*VerificationError)
+	if assert.True(t, ok, "expected verification error but got %v", err) {
+		assert.Equal(t, 3, verr.MinTargets)
+		assert.Equal(t, []string{peer1.URL(), peer2.URL()}, verr.Matching)
+	}
+}
+
+func TestQueryInfoHighestCorroborated(t *testing.T) {
+	client := setupClient(t, &mockLedger{infos: map[string]*common.BlockchainInfo{
+		peer1.URL(): {Height: 11, CurrentBlockHash: []byte("hash11")},
+		peer2.URL(): {Height: 10, CurrentBlockHash: []byte("hash10")},
+		peer3.URL(): {Height: 10, CurrentBlockHash: []byte("hash10")},
+	}})
+
+	resp, err := client.QueryInfo(WithTargets(peer1, peer2, peer3))
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(11), resp.Info.Height)
+		assert.Equal(t, []string{peer2.URL(), peer3.URL()}, resp.Behind)
+	}
+
+	resp, err = client.QueryInfo(WithTargets(peer1, peer2, peer3), WithMinTargets(2))
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(10), resp.Info.Height)
+		assert.Equal(t, []string{peer1.URL()}, resp.Disagreeing)
+		assert.Empty(t, resp.Behind)
+	}
+}
+
+func TestQueryBlock(t *testing.T) {
+	block := &common.Block{Header: &common.BlockHeader{Number: 5, DataHash: []byte("data")}}
+	client := setupClient(t, &mockLedger{
+		blocks: map[string]*common.Block{
+			peer1.URL(): block,
+			peer2.URL(): block,
+			peer4.URL(): {Header: &common.BlockHeader{Number: 5, DataHash: []byte("other")}},
+		},
+		infos: map[string]*common.BlockchainInfo{
+			peer3.URL(): {Height: 5},
+			peer5.URL(): {Height: 10},
+		},
+	})
+
+	resp, err := client.QueryBlock(5, WithMinTargets(2))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, block, resp.Block)
+	assert.Equal(t, []string{peer1.URL(), peer2.URL()}, resp.Matching)
+	assert.Equal(t, []string{peer3.URL()}, resp.Behind)
+	assert.Equal(t, []string{peer4.URL()}, resp.Disagreeing)
+	assert.Contains(t, resp.Failed, peer5.URL())
+
+	_, err = client.QueryBlock(5, WithMinTargets(3))
+	assert.IsType(t, &VerificationError{}, errors.Cause(err))
+}
+
+func TestQueryBlockByHash(t *testing.T) {
+	block := &common.Block{Header: &common.BlockHeader{Number: 5, DataHash: []byte("data")}}
+	client := setupClient(t, &mockLedger{blocks: map[string]*common.Block{peer1.URL(): block, peer2.URL(): block}})
+
+	resp, err := client.QueryBlockByHash([]byte("data"), WithMinTargets(2))
+	if assert.NoError(t, err) {
+		assert.Equal(t, block, resp.Block)
+	}
+
+	_, err = client.QueryBlockByHash([]byte("unknown"))
+	assert.Error(t, err)
+
+	_, err = client.QueryBlockByHash(nil)
+	assert.Error(t, err)
+}
+
+func TestQueryTransaction(t *testing.T) {
+	tx := &pb.ProcessedTransaction{ValidationCode: int32(pb.TxValidationCode_VALID)}
+	client := setupClient(t, &mockLedger{txs: map[string]*pb.ProcessedTransaction{
+		peer1.URL(): tx,
+		peer2.URL(): tx,
+		peer3.URL(): {ValidationCode: int32(pb.TxValidationCode_MVCC_READ_CONFLICT)},
+	}})
+
+	resp, err := client.QueryTransaction("txid", WithMinTargets(2))
+	if assert.NoError(t, err) {
+		assert.Equal(t, tx, resp.Transaction)
+		assert.Equal(t, []string{peer3.URL()}, resp.Disagreeing)
+	}
+
+	_, err = client.QueryTransaction("")
+	assert.Error(t, err)
+}
+
+func TestQueryConfig(t *testing.T) {
+	config := &common.ConfigEnvelope{Config: &common.Config{Sequence: 3, ChannelGroup: &common.ConfigGroup{}}}
+	client := setupClient(t, &mockLedger{configs: map[string]*common.ConfigEnvelope{peer1.URL(): config, peer2.URL(): config}})
+
+	resp, err := client.QueryConfig(WithTargets(peer1, peer2), WithMinTargets(2))
+	if assert.NoError(t, err) {
+		assert.Equal(t, testChannel, resp.Config.ChannelID())
+		assert.Equal(t, uint64(3), resp.Config.Config().Sequence)
+	}
+
+	_, err = client.QueryConfig(WithTargets(peer3))
+	assert.Error(t, err)
+}
+
+func TestTargets(t *testing.T) {
+	info := &common.BlockchainInfo{Height: 1}
+	ledger := &mockLedger{infos: map[string]*common.BlockchainInfo{peer1.URL(): info, peer2.URL(): info, peer3.URL(): info}}
+
+	client := setupClient(t, ledger)
+	resp, err := client.QueryInfo(WithMaxTargets(2), WithMinTargets(2))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{peer1.URL(), peer2.URL()}, resp.Matching)
+		assert.Empty(t, resp.Failed)
+	}
+
+	resp, err = client.QueryInfo(WithTargetFilter(&urlFilter{peer2.URL()}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{peer2.URL()}, resp.Matching)
+	}
+
+	client = setupClient(t, ledger, WithDefaultTargetFilter(&urlFilter{peer3.URL()}))
+	resp, err = client.QueryInfo()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{peer3.URL()}, resp.Matching)
+	}
+
+	_, err = client.QueryInfo(WithTargetFilter(&urlFilter{"unknown"}))
+	assert.Error(t, err)
+
+	_, err = client.QueryInfo(WithTargets(peer1), WithMinTargets(2))
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Context{DiscoveryService: &mocks.MockStaticDiscoveryService{}})
+	assert.Error(t, err)
+}
+
+type urlFilter struct {
+	url string
+}
+
+func (f *urlFilter) Accept(peer fab.Peer) bool {
+	return peer.URL() == f.url
+}
@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+)
+
+// WithTargets encapsulates fab.Peer targets to ledger RequestOption
+func WithTargets(targets ...fab.Peer) RequestOption {
+	return func(opts *Opts) error {
+		opts.Targets = targets
+		return nil
+	}
+}
+
+// WithTargetFilter encapsulates fab.TargetFilter to ledger RequestOption
+func WithTargetFilter(targetFilter fab.TargetFilter) RequestOption {
+	return func(opts *Opts) error {
+		opts.TargetFilter = targetFilter
+		return nil
+	}
+}
+
+// WithMinTargets requires the given number of targets to return matching responses
+func WithMinTargets(minTargets int) RequestOption {
+	return func(opts *Opts) error {
+		opts.MinTargets = minTargets
+		return nil
+	}
+}
+
+// WithMaxTargets limits the number of targets which are queried
+func WithMaxTargets(maxTargets int) RequestOption {
+	return func(opts *Opts) error {
+		opts.MaxTargets = maxTargets
+		return nil
+	}
+}
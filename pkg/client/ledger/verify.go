@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// Report describes how the responses of the target peers compare to the result of a query.
+// Peers are identified by their URL.
+type Report struct {
+	// Peers which returned the result
+	Matching []string
+	// Peers which returned a different result
+	Disagreeing []string
+	// Peers whose ledger is not high enough to contain the result
+	Behind []string
+	// Peers which failed to respond
+	Failed map[string]error
+}
+
+// VerificationError is returned if fewer than the required number of targets return
+// matching responses
+type VerificationError struct {
+	// Required number of matching responses
+	MinTargets int
+	// Report of the largest group of matching responses
+	Report
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("%d matching responses required but got %d (disagreeing: %v, behind: %v, failed: %v)",
+		e.MinTargets, len(e.Matching), e.Disagreeing, e.Behind, e.Failed)
+}
+
+type peerResponse struct {
+	peer   fab.Peer
+	msg    proto.Message
+	err    error
+	behind bool
+}
+
+func newVerificationError(minTargets int, responses []*peerResponse, largest []*peerResponse) error {
+	return &VerificationError{MinTargets: minTargets, Report: newReport(responses, largest)}
+}
+
+func newReport(responses []*peerResponse, result []*peerResponse) Report {
+	matching := make(map[*peerResponse]bool, len(result))
+	for _, r := range result {
+		matching[r] = true
+	}
+
+	report := Report{Failed: make(map[string]error)}
+	for _, r := range responses {
+		url := r.peer.URL()
+		switch {
+		case matching[r]:
+			report.Matching = append(report.Matching, url)
+		case r.behind:
+			report.Behind = append(report.Behind, url)
+		case r.err != nil:
+			report.Failed[url] = r.err
+		default:
+			report.Disagreeing = append(report.Disagreeing, url)
+		}
+	}
+	return report
+}
+
+// groupResponses partitions the successful responses into groups of equal responses, in the
+// order of their first response
+func groupResponses(responses []*peerResponse) [][]*peerResponse {
+	var groups [][]*peerResponse
+	for _, r := range responses {
+		if r.err != nil {
+			continue
+		}
+		found := false
+		for i, group := range groups {
+			if proto.Equal(group[0].msg, r.msg) {
+				groups[i] = append(group, r)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, []*peerResponse{r})
+		}
+	}
+	return groups
+}
+
+// largestGroup returns the largest group, or the first of several groups of the same size
+func largestGroup(groups [][]*peerResponse) []*peerResponse {
+	var largest []*peerResponse
+	for _, group := range groups {
+		if len(group) > len(largest) {
+			largest = group
+		}
+	}
+	return largest
+}
+
+// groupsByHeight orders groups of blockchain info responses by descending ledger height, and
+// groups of the same height by descending size
+func groupsByHeight(groups [][]*peerResponse) [][]*peerResponse {
+	sorted := append([][]*peerResponse{}, groups...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		hi := sorted[i][0].msg.(*common.BlockchainInfo).Height
+		hj := sorted[j][0].msg.(*common.BlockchainInfo).Height
+		if hi != hj {
+			return hi > hj
+		}
+		return len(sorted[i]) > len(sorted[j])
+	})
+	return sorted
+}
@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replicator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	newDirMode  = 0700
+	newFileMode = 0600
+)
+
+// FileSink is a sink which writes each block as a JSON file to a directory. It is intended for
+// tests and for inspecting the replicated changes.
+type FileSink struct {
+	dir string
+}
+
+// NewFileSink returns a sink which writes to the given directory, which is created if needed
+func NewFileSink(dir string) (*FileSink, error) {
+	if dir == "" {
+		return nil, errors.New("directory is required")
+	}
+	if err := os.MkdirAll(dir, newDirMode); err != nil {
+		return nil, errors.Wrapf(err, "creating directory %s failed", dir)
+	}
+	return &FileSink{dir: dir}, nil
+}
+
+// Apply writes the block to its file. The file is replaced atomically, so a block which is
+// applied again overwrites the previous file.
+func (s *FileSink) Apply(block *Block) error {
+	blockBytes, err := json.MarshalIndent(block, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal of block failed")
+	}
+
+	file := s.path(block.Number)
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, blockBytes, newFileMode); err != nil {
+		return errors.Wrapf(err, "writing block %d failed", block.Number)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return errors.Wrapf(err, "writing block %d failed", block.Number)
+	}
+	return nil
+}
+
+// Load reads the block with the given number
+func (s *FileSink) Load(number uint64) (*Block, error) {
+	blockBytes, err := ioutil.ReadFile(s.path(number))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading block %d failed", number)
+	}
+	block := &Block{}
+	if err := json.Unmarshal(blockBytes, block); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal of block %d failed", number)
+	}
+	return block, nil
+}
+
+// path returns the file of the block. Block numbers are zero padded so that the files sort in
+// block order.
+func (s *FileSink) path(number uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d.json", number))
+}
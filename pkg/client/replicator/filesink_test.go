@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replicator
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filesink")
+	if err != nil {
+		t.Fatalf("error from ioutil.TempDir %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sink, err := NewFileSink(dir)
+	if err != nil {
+		t.Fatalf("failed to create sink: %s", err)
+	}
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	chain := newTestChain()
+	r := newTestReplicator(t, sink, store)
+	for _, block := range chain.blocks[:2] {
+		assert.NoError(t, r.Process(block))
+	}
+
+	block, err := sink.Load(1)
+	if err != nil {
+		t.Fatalf("failed to load block: %s", err)
+	}
+	assert.Equal(t, testChannelID, block.ChannelID)
+	assert.Equal(t, uint64(1), block.Number)
+	assert.Equal(t, r.Checkpoint().BlockHash, block.Hash)
+	if !assert.Len(t, block.Transactions, 2) {
+		t.FailNow()
+	}
+
+	tx := block.Transactions[0]
+	assert.Equal(t, "tx1", tx.TxID)
+	assert.Equal(t, pb.TxValidationCode_VALID, tx.ValidationCode)
+	assert.Equal(t, []*Write{{Namespace: testCCID, Key: "a", Value: []byte("10")}}, tx.Writes)
+	assert.Equal(t, []*Delete{{Namespace: testCCID, Key: "c"}}, tx.Deletes)
+	if assert.Len(t, tx.Events, 1) {
+		assert.Equal(t, "moved", tx.Events[0].EventName)
+	}
+
+	invalid := block.Transactions[1]
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, invalid.ValidationCode)
+	assert.Empty(t, invalid.Writes)
+	assert.Empty(t, invalid.Events)
+
+	_, err = sink.Load(2)
+	assert.Error(t, err)
+
+	_, err = NewFileSink("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replicator
+
+import (
+	"sync"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// MemorySink is a sink which keeps the replicated state in memory. It is intended for tests.
+type MemorySink struct {
+	mutex    sync.RWMutex
+	state    map[string]map[string][]byte
+	events   []*pb.ChaincodeEvent
+	txStatus map[string]pb.TxValidationCode
+	blocks   []uint64
+}
+
+// NewMemorySink returns a new, empty memory sink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{
+		state:    make(map[string]map[string][]byte),
+		txStatus: make(map[string]pb.TxValidationCode),
+	}
+}
+
+// Apply applies the writes and deletes of the block to the state and records its events and
+// transaction statuses. A block which was already applied is ignored.
+func (s *MemorySink) Apply(block *Block) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.blocks) > 0 && block.Number <= s.blocks[len(s.blocks)-1] {
+		return nil
+	}
+
+	for _, tx := range block.Transactions {
+		s.txStatus[tx.TxID] = tx.ValidationCode
+		for _, write := range tx.Writes {
+			ns, ok := s.state[write.Namespace]
+			if !ok {
+				ns = make(map[string][]byte)
+				s.state[write.Namespace] = ns
+			}
+			ns[write.Key] = write.Value
+		}
+		for _, del := range tx.Deletes {
+			delete(s.state[del.Namespace], del.Key)
+		}
+		s.events = append(s.events, tx.Events...)
+	}
+	s.blocks = append(s.blocks, block.Number)
+	return nil
+}
+
+// Get returns the value of the key in the namespace
+func (s *MemorySink) Get(namespace string, key string) ([]byte, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	value, ok := s.state[namespace][key]
+	return value, ok
+}
+
+// Keys returns the number of keys in the namespace
+func (s *MemorySink) Keys(namespace string) int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.state[namespace])
+}
+
+// Events returns the chaincode events in the order in which they were committed
+func (s *MemorySink) Events() []*pb.ChaincodeEvent {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]*pb.ChaincodeEvent{}, s.events...)
+}
+
+// TxStatus returns the validation code of the transaction
+func (s *MemorySink) TxStatus(txID string) (pb.TxValidationCode, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	code, ok := s.txStatus[txID]
+	return code, ok
+}
+
+// Blocks returns the numbers of the applied blocks
+func (s *MemorySink) Blocks() []uint64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]uint64{}, s.blocks...)
+}
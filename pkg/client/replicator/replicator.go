@@ -0,0 +1,369 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package replicator replicates the blocks of a channel to an off-chain store.
+//
+// The replicator listens to the block events of a channel and passes the writes, deletes,
+// chaincode events and transaction statuses of each block to a sink. After a block is applied by
+// the sink its number and hash are stored as checkpoint, so that the replication resumes after
+// the last applied block when the replicator is restarted. Blocks which do not continue the
+// checkpoint are rejected: missing blocks are reported as GapError unless a catch-up source is
+// configured and a block which does not link to the hash of the checkpoint is reported as
+// ReorgError.
+package replicator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/blockiterator"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/blockverifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+// Context holds the services needed to create a Replicator.
+type Context struct {
+	ChannelID    string
+	EventService fab.EventService
+}
+
+// Checkpoint identifies the last block which was applied by the sink
+type Checkpoint struct {
+	BlockNumber uint64 `json:"block_number"`
+	BlockHash   []byte `json:"block_hash"`
+}
+
+// GapError is returned if a block is received which does not follow the checkpoint and the
+// missing blocks cannot be retrieved
+type GapError struct {
+	Expected uint64
+	Received uint64
+}
+
+func (e *GapError) Error() string {
+	return fmt.Sprintf("expected block %d but received block %d", e.Expected, e.Received)
+}
+
+// ReorgError is returned if a block does not match the hash chain of the replicated blocks,
+// which means that the chain was reorganised or the blocks come from a different ledger
+type ReorgError struct {
+	Number       uint64
+	ExpectedHash []byte
+	ReceivedHash []byte
+}
+
+func (e *ReorgError) Error() string {
+	return fmt.Sprintf("block %d does not match the replicated chain: expected hash %x but received %x", e.Number, e.ExpectedHash, e.ReceivedHash)
+}
+
+// Replicator replicates the blocks of a channel to a sink.
+type Replicator struct {
+	channelID     string
+	eventService  fab.EventService
+	sink          Sink
+	store         api.KVStore
+	checkpointKey string
+	startBlock    *uint64
+	source        blockiterator.Source
+	sourceOpts    []blockiterator.Option
+
+	mutex      sync.Mutex
+	checkpoint *Checkpoint
+	reg        fab.Registration
+	stop       chan struct{}
+	stopOnce   sync.Once
+	done       chan struct{}
+	errs       chan error
+}
+
+// Option describes a functional parameter for the New constructor
+type Option func(*Replicator) error
+
+// WithCheckpointKey sets the key of the checkpoint in the store. The default key is derived from
+// the channel ID, so a different key is needed for replicating a channel to several sinks with the
+// same store.
+func WithCheckpointKey(key string) Option {
+	return func(r *Replicator) error {
+		if key == "" {
+			return errors.New("checkpoint key is required")
+		}
+		r.checkpointKey = key
+		return nil
+	}
+}
+
+// WithStartBlock sets the block from which the replication starts if there is no checkpoint. By
+// default the replication starts with the first block which is received.
+func WithStartBlock(number uint64) Option {
+	return func(r *Replicator) error {
+		r.startBlock = &number
+		return nil
+	}
+}
+
+// WithCatchUp sets the source from which blocks are retrieved which were missed, for example
+// while the replicator was stopped. The source must deliver the blocks committed by a peer, see
+// blockiterator.FromPeer. Blocks delivered by an orderer carry no transaction filter, so the
+// replication fails on them since invalid transactions cannot be told apart from valid ones.
+func WithCatchUp(source blockiterator.Source, opts ...blockiterator.Option) Option {
+	return func(r *Replicator) error {
+		r.source = source
+		r.sourceOpts = opts
+		return nil
+	}
+}
+
+// New returns a replicator for the channel which applies the blocks to the sink and stores its
+// checkpoint in the store. The replication resumes from an existing checkpoint.
+func New(ctx Context, sink Sink, store api.KVStore, opts ...Option) (*Replicator, error) {
+	if sink == nil || store == nil {
+		return nil, errors.New("sink and store are required")
+	}
+
+	r := &Replicator{
+		channelID:     ctx.ChannelID,
+		eventService:  ctx.EventService,
+		sink:          sink,
+		store:         store,
+		checkpointKey: "replicator/" + ctx.ChannelID,
+	}
+	for _, opt := range opts {
+		if err := opt(r); err != nil {
+			return nil, err
+		}
+	}
+
+	checkpoint, err := r.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	r.checkpoint = checkpoint
+	return r, nil
+}
+
+// Checkpoint returns the checkpoint of the last applied block or nil if no block was applied yet
+func (r *Replicator) Checkpoint() *Checkpoint {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.checkpoint == nil {
+		return nil
+	}
+	checkpoint := *r.checkpoint
+	return &checkpoint
+}
+
+// Start registers for block events and replicates the received blocks until the replicator is
+// stopped or a block cannot be replicated. The error which ended the replication is sent to
+// the channel which is returned.
+func (r *Replicator) Start() (<-chan error, error) {
+	if r.eventService == nil {
+		return nil, errors.New("event service is required")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.stop != nil {
+		return nil, errors.New("replicator was already started")
+	}
+	reg, eventch, err := r.eventService.RegisterBlockEvent()
+	if err != nil {
+		return nil, errors.WithMessage(err, "registration for block events failed")
+	}
+	r.reg = reg
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	r.errs = make(chan error, 1)
+
+	go r.listen(eventch)
+	return r.errs, nil
+}
+
+// Stop unregisters from block events and waits until the block which is being replicated is
+// applied
+func (r *Replicator) Stop() {
+	r.mutex.Lock()
+	stop, done := r.stop, r.done
+	r.mutex.Unlock()
+
+	if stop == nil {
+		return
+	}
+	r.stopOnce.Do(func() {
+		close(stop)
+		r.eventService.Unregister(r.reg)
+	})
+	<-done
+}
+
+func (r *Replicator) listen(eventch <-chan *fab.BlockEvent) {
+	defer close(r.done)
+	for {
+		select {
+		case event, ok := <-eventch:
+			if !ok {
+				select {
+				case <-r.stop:
+				default:
+					r.errs <- errors.New("block event channel was closed")
+				}
+				return
+			}
+			if err := r.Process(event.Block); err != nil {
+				logger.Warnf("Replication of channel %s stopped: %s", r.channelID, err)
+				r.errs <- err
+				go r.Stop()
+				return
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Process replicates the block. Blocks which were already applied are skipped and missing
+// blocks are retrieved from the catch-up source, if configured.
+func (r *Replicator) Process(block *cb.Block) error {
+	if block == nil || block.Header == nil {
+		return errors.New("block header is required")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	number := block.Header.Number
+	next, ok := r.nextBlock()
+	if !ok {
+		next = number
+	}
+
+	if number < next {
+		if r.checkpoint != nil && number == r.checkpoint.BlockNumber {
+			if hash := blockverifier.HeaderHash(block.Header); !bytes.Equal(hash, r.checkpoint.BlockHash) {
+				return &ReorgError{Number: number, ExpectedHash: r.checkpoint.BlockHash, ReceivedHash: hash}
+			}
+		}
+		logger.Debugf("Skipping block %d of channel %s which precedes block %d", number, r.channelID, next)
+		return nil
+	}
+	if number > next {
+		if err := r.catchUp(next, number-1); err != nil {
+			return err
+		}
+	}
+	return r.apply(block)
+}
+
+// nextBlock returns the number of the next block to be applied, if known
+func (r *Replicator) nextBlock() (uint64, bool) {
+	if r.checkpoint != nil {
+		return r.checkpoint.BlockNumber + 1, true
+	}
+	if r.startBlock != nil {
+		return *r.startBlock, true
+	}
+	return 0, false
+}
+
+// catchUp applies the blocks in the range from the catch-up source
+func (r *Replicator) catchUp(from uint64, to uint64) error {
+	if r.source == nil {
+		return &GapError{Expected: from, Received: to + 1}
+	}
+	logger.Debugf("Retrieving blocks %d to %d of channel %s", from, to, r.channelID)
+
+	it, err := blockiterator.New(r.source, from, to, r.sourceOpts...)
+	if err != nil {
+		return errors.WithMessage(err, "creating block iterator failed")
+	}
+	defer it.Close()
+
+	for {
+		block, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("retrieving blocks %d to %d failed", from, to))
+		}
+		if block.Block == nil {
+			return errors.Errorf("catch-up source returned block %d without data", block.Number)
+		}
+		if err := r.apply(block.Block); err != nil {
+			return err
+		}
+	}
+}
+
+// apply passes the changes of the block to the sink and stores the new checkpoint
+func (r *Replicator) apply(block *cb.Block) error {
+	number := block.Header.Number
+	if r.checkpoint != nil {
+		if number != r.checkpoint.BlockNumber+1 {
+			return &GapError{Expected: r.checkpoint.BlockNumber + 1, Received: number}
+		}
+		if !bytes.Equal(block.Header.PreviousHash, r.checkpoint.BlockHash) {
+			return &ReorgError{Number: r.checkpoint.BlockNumber, ExpectedHash: r.checkpoint.BlockHash, ReceivedHash: block.Header.PreviousHash}
+		}
+	}
+
+	b, err := newBlock(r.channelID, block)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("processing of block %d failed", number))
+	}
+	if err := r.sink.Apply(b); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("sink failed to apply block %d", number))
+	}
+
+	checkpoint := &Checkpoint{BlockNumber: number, BlockHash: b.Hash}
+	if err := r.storeCheckpoint(checkpoint); err != nil {
+		return err
+	}
+	r.checkpoint = checkpoint
+	logger.Debugf("Replicated block %d of channel %s", number, r.channelID)
+	return nil
+}
+
+func (r *Replicator) loadCheckpoint() (*Checkpoint, error) {
+	value, err := r.store.Load(r.checkpointKey)
+	if err == api.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "loading checkpoint failed")
+	}
+	checkpointBytes, ok := value.([]byte)
+	if !ok {
+		return nil, errors.Errorf("unexpected checkpoint type %T", value)
+	}
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(checkpointBytes, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of checkpoint failed")
+	}
+	return checkpoint, nil
+}
+
+func (r *Replicator) storeCheckpoint(checkpoint *Checkpoint) error {
+	checkpointBytes, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "marshal of checkpoint failed")
+	}
+	if err := r.store.Store(r.checkpointKey, checkpointBytes); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("storing checkpoint of block %d failed", checkpoint.BlockNumber))
+	}
+	return nil
+}
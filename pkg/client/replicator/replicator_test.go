@@ -0,0 +1,454 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replicator
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/blockiterator"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/blockverifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	lrwset "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+const (
+	testChannelID = "mychannel"
+	testCCID      = "examplecc"
+)
+
+// testTx describes an endorser transaction of a test block
+type testTx struct {
+	txID    string
+	code    pb.TxValidationCode
+	writes  map[string]string
+	deletes []string
+	event   string
+}
+
+// testChain builds a chain of blocks which are linked by their header hashes
+type testChain struct {
+	blocks []*cb.Block
+}
+
+func (c *testChain) add(txs ...testTx) *cb.Block {
+	header := &cb.BlockHeader{Number: uint64(len(c.blocks))}
+	if len(c.blocks) > 0 {
+		header.PreviousHash = blockverifier.HeaderHash(c.blocks[len(c.blocks)-1].Header)
+	}
+
+	txFilter := ledgerutil.NewTxValidationFlags(len(txs))
+	data := &cb.BlockData{}
+	for i, tx := range txs {
+		txFilter[i] = uint8(tx.code)
+		data.Data = append(data.Data, utils.MarshalOrPanic(newEndorserTxEnvelope(tx)))
+	}
+	header.DataHash = blockverifier.DataHash(data)
+
+	metadata := make([][]byte, len(cb.BlockMetadataIndex_name))
+	metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+
+	block := &cb.Block{Header: header, Data: data, Metadata: &cb.BlockMetadata{Metadata: metadata}}
+	c.blocks = append(c.blocks, block)
+	return block
+}
+
+func newEndorserTxEnvelope(tx testTx) *cb.Envelope {
+	kvRWSet := &kvrwset.KVRWSet{}
+	for key, value := range tx.writes {
+		kvRWSet.Writes = append(kvRWSet.Writes, &kvrwset.KVWrite{Key: key, Value: []byte(value)})
+	}
+	for _, key := range tx.deletes {
+		kvRWSet.Writes = append(kvRWSet.Writes, &kvrwset.KVWrite{Key: key, IsDelete: true})
+	}
+	txRWSet := &lrwset.TxReadWriteSet{
+		DataModel: lrwset.TxReadWriteSet_KV,
+		NsRwset:   []*lrwset.NsReadWriteSet{{Namespace: testCCID, Rwset: utils.MarshalOrPanic(kvRWSet)}},
+	}
+	ccAction := &pb.ChaincodeAction{
+		Results:     utils.MarshalOrPanic(txRWSet),
+		Response:    &pb.Response{Status: 200},
+		ChaincodeId: &pb.ChaincodeID{Name: testCCID, Version: "v1"},
+	}
+	if tx.event != "" {
+		ccAction.Events = utils.MarshalOrPanic(&pb.ChaincodeEvent{ChaincodeId: testCCID, TxId: tx.txID, EventName: tx.event})
+	}
+
+	ccActionPayload := &pb.ChaincodeActionPayload{
+		ChaincodeProposalPayload: utils.MarshalOrPanic(&pb.ChaincodeProposalPayload{}),
+		Action: &pb.ChaincodeEndorsedAction{
+			ProposalResponsePayload: utils.MarshalOrPanic(&pb.ProposalResponsePayload{Extension: utils.MarshalOrPanic(ccAction)}),
+		},
+	}
+	transaction := &pb.Transaction{
+		Actions: []*pb.TransactionAction{{Payload: utils.MarshalOrPanic(ccActionPayload)}},
+	}
+
+	channelHeader := &cb.ChannelHeader{Type: int32(cb.HeaderType_ENDORSER_TRANSACTION), ChannelId: testChannelID, TxId: tx.txID}
+	payload := &cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(channelHeader), SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{})},
+		Data:   utils.MarshalOrPanic(transaction),
+	}
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(payload)}
+}
+
+func newTestChain() *testChain {
+	chain := &testChain{}
+	chain.add(testTx{txID: "tx0", writes: map[string]string{"a": "1", "b": "2", "c": "3"}})
+	chain.add(
+		testTx{txID: "tx1", writes: map[string]string{"a": "10"}, deletes: []string{"c"}, event: "moved"},
+		testTx{txID: "tx2", code: pb.TxValidationCode_MVCC_READ_CONFLICT, writes: map[string]string{"b": "20"}, event: "ignored"},
+	)
+	chain.add(testTx{txID: "tx3", writes: map[string]string{"d": "4"}})
+	chain.add(testTx{txID: "tx4", deletes: []string{"d"}})
+	return chain
+}
+
+func newTestStore(t *testing.T) (*keyvaluestore.FileKeyValueStore, func()) {
+	dir, err := ioutil.TempDir("", "replicator")
+	if err != nil {
+		t.Fatalf("error from ioutil.TempDir %v", err)
+	}
+	store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{Path: dir})
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("failed to create store: %s", err)
+	}
+	return store, func() { os.RemoveAll(dir) }
+}
+
+func newTestReplicator(t *testing.T, sink Sink, store *keyvaluestore.FileKeyValueStore, opts ...Option) *Replicator {
+	r, err := New(Context{ChannelID: testChannelID}, sink, store, opts...)
+	if err != nil {
+		t.Fatalf("failed to create replicator: %s", err)
+	}
+	return r
+}
+
+func TestReplicate(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	chain := newTestChain()
+	sink := NewMemorySink()
+	r := newTestReplicator(t, sink, store)
+	assert.Nil(t, r.Checkpoint())
+
+	for _, block := range chain.blocks[:3] {
+		assert.NoError(t, r.Process(block))
+	}
+
+	assertValue(t, sink, "a", "10")
+	assertValue(t, sink, "b", "2")
+	assertValue(t, sink, "d", "4")
+	_, ok := sink.Get(testCCID, "c")
+	assert.False(t, ok, "deleted key should not exist")
+
+	if assert.Len(t, sink.Events(), 1) {
+		assert.Equal(t, "moved", sink.Events()[0].EventName)
+		assert.Equal(t, "tx1", sink.Events()[0].TxId)
+	}
+	code, ok := sink.TxStatus("tx2")
+	assert.True(t, ok)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, code)
+	assert.Equal(t, []uint64{0, 1, 2}, sink.Blocks())
+
+	checkpoint := r.Checkpoint()
+	if assert.NotNil(t, checkpoint) {
+		assert.Equal(t, uint64(2), checkpoint.BlockNumber)
+		assert.Equal(t, blockverifier.HeaderHash(chain.blocks[2].Header), checkpoint.BlockHash)
+	}
+}
+
+func TestResume(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	chain := newTestChain()
+	r := newTestReplicator(t, NewMemorySink(), store)
+	for _, block := range chain.blocks[:2] {
+		assert.NoError(t, r.Process(block))
+	}
+
+	// a restarted replicator skips the blocks up to the checkpoint
+	sink := NewMemorySink()
+	r = newTestReplicator(t, sink, store)
+	if assert.NotNil(t, r.Checkpoint()) {
+		assert.Equal(t, uint64(1), r.Checkpoint().BlockNumber)
+	}
+	for _, block := range chain.blocks {
+		assert.NoError(t, r.Process(block))
+	}
+	assert.Equal(t, []uint64{2, 3}, sink.Blocks())
+	_, ok := sink.Get(testCCID, "d")
+	assert.False(t, ok, "deleted key should not exist")
+
+	// a separate checkpoint key starts from the beginning
+	sink = NewMemorySink()
+	r = newTestReplicator(t, sink, store, WithCheckpointKey("other"))
+	assert.Nil(t, r.Checkpoint())
+}
+
+func TestStartBlock(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	chain := newTestChain()
+	sink := NewMemorySink()
+	r := newTestReplicator(t, sink, store, WithStartBlock(1))
+	assert.NoError(t, r.Process(chain.blocks[0]))
+	assert.NoError(t, r.Process(chain.blocks[1]))
+	assert.Equal(t, []uint64{1}, sink.Blocks())
+
+	r = newTestReplicator(t, NewMemorySink(), store, WithStartBlock(1), WithCheckpointKey("other"))
+	err := r.Process(chain.blocks[2])
+	assert.Equal(t, &GapError{Expected: 1, Received: 2}, errors.Cause(err))
+}
+
+func TestGap(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	chain := newTestChain()
+	sink := NewMemorySink()
+	r := newTestReplicator(t, sink, store)
+	assert.NoError(t, r.Process(chain.blocks[0]))
+
+	err := r.Process(chain.blocks[2])
+	assert.Equal(t, &GapError{Expected: 1, Received: 2}, errors.Cause(err))
+	assert.Equal(t, uint64(0), r.Checkpoint().BlockNumber)
+	assert.Equal(t, []uint64{0}, sink.Blocks())
+}
+
+func TestReorg(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	chain := newTestChain()
+	r := newTestReplicator(t, NewMemorySink(), store)
+	for _, block := range chain.blocks[:2] {
+		assert.NoError(t, r.Process(block))
+	}
+
+	// a block of a different chain does not link to the checkpoint
+	other := &testChain{}
+	other.add(testTx{txID: "other0"})
+	other.add(testTx{txID: "other1"})
+	other.add(testTx{txID: "other2"})
+
+	err := r.Process(other.blocks[2])
+	reorgErr, ok := errors.Cause(err).(*ReorgError)
+	if assert.True(t, ok, "expected reorg error but got %v", err) {
+		assert.Equal(t, uint64(1), reorgErr.Number)
+		assert.Equal(t, r.Checkpoint().BlockHash, reorgErr.ExpectedHash)
+	}
+
+	// a different block is received for the checkpoint
+	err = r.Process(other.blocks[1])
+	assert.IsType(t, &ReorgError{}, errors.Cause(err))
+
+	// older blocks are skipped
+	assert.NoError(t, r.Process(other.blocks[0]))
+	assert.Equal(t, uint64(1), r.Checkpoint().BlockNumber)
+}
+
+// failingSink fails to apply blocks until it is enabled
+type failingSink struct {
+	*MemorySink
+	fail bool
+}
+
+func (s *failingSink) Apply(block *Block) error {
+	if s.fail {
+		return errors.New("database unavailable")
+	}
+	return s.MemorySink.Apply(block)
+}
+
+func TestSinkFailure(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	chain := newTestChain()
+	sink := &failingSink{MemorySink: NewMemorySink()}
+	r := newTestReplicator(t, sink, store)
+	assert.NoError(t, r.Process(chain.blocks[0]))
+
+	sink.fail = true
+	assert.Error(t, r.Process(chain.blocks[1]))
+	assert.Equal(t, uint64(0), r.Checkpoint().BlockNumber)
+
+	sink.fail = false
+	assert.NoError(t, r.Process(chain.blocks[1]))
+	assert.Equal(t, uint64(1), r.Checkpoint().BlockNumber)
+}
+
+// chainSource delivers the blocks of the chain to the block iterator
+type chainSource struct {
+	chain     *testChain
+	seekInfos []*ab.SeekInfo
+}
+
+type chainStream struct {
+	blocks []*cb.Block
+}
+
+func (s *chainSource) Open(seekInfo *ab.SeekInfo, bufferSize int) (blockiterator.Stream, error) {
+	s.seekInfos = append(s.seekInfos, seekInfo)
+	from := seekInfo.Start.GetSpecified().GetNumber()
+	to := seekInfo.Stop.GetSpecified().GetNumber()
+	return &chainStream{blocks: s.chain.blocks[from : to+1]}, nil
+}
+
+func (s *chainStream) Recv() (*blockiterator.Block, error) {
+	if len(s.blocks) == 0 {
+		return nil, io.EOF
+	}
+	block := s.blocks[0]
+	s.blocks = s.blocks[1:]
+	return &blockiterator.Block{Number: block.Header.Number, Block: block}, nil
+}
+
+func (s *chainStream) Close() {
+}
+
+func TestCatchUp(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	chain := newTestChain()
+	source := &chainSource{chain: chain}
+	sink := NewMemorySink()
+	r := newTestReplicator(t, sink, store, WithCatchUp(source), WithStartBlock(0))
+
+	assert.NoError(t, r.Process(chain.blocks[2]))
+	assert.Equal(t, []uint64{0, 1, 2}, sink.Blocks())
+	if assert.Len(t, source.seekInfos, 1) {
+		assert.Equal(t, uint64(0), source.seekInfos[0].Start.GetSpecified().GetNumber())
+		assert.Equal(t, uint64(1), source.seekInfos[0].Stop.GetSpecified().GetNumber())
+	}
+
+	assert.NoError(t, r.Process(chain.blocks[3]))
+	assert.Len(t, source.seekInfos, 1)
+	assert.Equal(t, uint64(3), r.Checkpoint().BlockNumber)
+}
+
+func TestCatchUpFromOrderer(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	// blocks delivered by an orderer have no transaction filter
+	chain := newTestChain()
+	ordererChain := &testChain{}
+	for _, block := range chain.blocks {
+		ordererBlock := *block
+		ordererBlock.Metadata = &cb.BlockMetadata{Metadata: make([][]byte, len(cb.BlockMetadataIndex_name))}
+		ordererChain.blocks = append(ordererChain.blocks, &ordererBlock)
+	}
+
+	source := &chainSource{chain: ordererChain}
+	sink := NewMemorySink()
+	r := newTestReplicator(t, sink, store, WithCatchUp(source), WithStartBlock(0))
+	assert.NoError(t, r.Process(chain.blocks[0]))
+
+	// block 1 contains the invalid transaction tx2, which must not be applied as valid
+	err := r.Process(chain.blocks[2])
+	assert.Error(t, err)
+	assert.Equal(t, []uint64{0}, sink.Blocks())
+	assert.Equal(t, uint64(0), r.Checkpoint().BlockNumber)
+	_, ok := sink.TxStatus("tx2")
+	assert.False(t, ok)
+	assertValue(t, sink, "b", "2")
+}
+
+// blockEventService delivers the blocks which are sent to its channel
+type blockEventService struct {
+	fab.EventService
+	eventch chan *fab.BlockEvent
+}
+
+func (s *blockEventService) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	return "reg", s.eventch, nil
+}
+
+func (s *blockEventService) Unregister(reg fab.Registration) {
+}
+
+func TestStart(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	chain := newTestChain()
+	eventService := &blockEventService{eventch: make(chan *fab.BlockEvent)}
+	sink := NewMemorySink()
+	r, err := New(Context{ChannelID: testChannelID, EventService: eventService}, sink, store)
+	if err != nil {
+		t.Fatalf("failed to create replicator: %s", err)
+	}
+
+	errs, err := r.Start()
+	if err != nil {
+		t.Fatalf("failed to start replicator: %s", err)
+	}
+	_, err = r.Start()
+	assert.Error(t, err)
+
+	eventService.eventch <- &fab.BlockEvent{Block: chain.blocks[0]}
+	eventService.eventch <- &fab.BlockEvent{Block: chain.blocks[1]}
+	eventService.eventch <- &fab.BlockEvent{Block: chain.blocks[3]}
+
+	select {
+	case err := <-errs:
+		assert.Equal(t, &GapError{Expected: 2, Received: 3}, errors.Cause(err))
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for replication error")
+	}
+	r.Stop()
+	assert.Equal(t, []uint64{0, 1}, sink.Blocks())
+	assert.Equal(t, uint64(1), r.Checkpoint().BlockNumber)
+}
+
+func TestNew(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	_, err := New(Context{ChannelID: testChannelID}, nil, store)
+	assert.Error(t, err)
+
+	_, err = New(Context{ChannelID: testChannelID}, NewMemorySink(), store, WithCheckpointKey(""))
+	assert.Error(t, err)
+
+	if err := store.Store("replicator/"+testChannelID, []byte("invalid")); err != nil {
+		t.Fatalf("failed to store checkpoint: %s", err)
+	}
+	_, err = New(Context{ChannelID: testChannelID}, NewMemorySink(), store)
+	assert.Error(t, err)
+
+	r := newTestReplicator(t, NewMemorySink(), store, WithCheckpointKey("other"))
+	_, err = r.Start()
+	assert.Error(t, err, "expected error without event service")
+}
+
+func assertValue(t *testing.T, sink *MemorySink, key string, expected string) {
+	value, ok := sink.Get(testCCID, key)
+	if assert.True(t, ok, "key %s not found", key) {
+		assert.Equal(t, expected, string(value))
+	}
+}
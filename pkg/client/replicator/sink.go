@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replicator
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/blockdecoder"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/blockverifier"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Sink receives the changes of each replicated block, for example to write them to a database.
+//
+// The checkpoint of the replicator is stored after the sink has applied a block. If the
+// replicator is stopped in between, the block is applied again after a restart, so sinks should
+// apply blocks idempotently.
+type Sink interface {
+	// Apply applies the changes of the block. An error stops the replication at this block.
+	Apply(block *Block) error
+}
+
+// Block contains the changes of a block
+type Block struct {
+	ChannelID    string `json:"channel_id"`
+	Number       uint64 `json:"number"`
+	Hash         []byte `json:"hash"`
+	PreviousHash []byte `json:"previous_hash"`
+	// Transactions of the block in the order in which they were committed
+	Transactions []*Transaction `json:"transactions"`
+}
+
+// Transaction contains the status of a transaction and, if the transaction is valid, the keys it
+// wrote and deleted and the chaincode events it emitted
+type Transaction struct {
+	TxID           string               `json:"tx_id"`
+	Type           cb.HeaderType        `json:"type"`
+	ValidationCode pb.TxValidationCode  `json:"validation_code"`
+	Writes         []*Write             `json:"writes,omitempty"`
+	Deletes        []*Delete            `json:"deletes,omitempty"`
+	Events         []*pb.ChaincodeEvent `json:"events,omitempty"`
+}
+
+// Write is the value written to a key of a chaincode namespace
+type Write struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Value     []byte `json:"value"`
+}
+
+// Delete is the deletion of a key of a chaincode namespace
+type Delete struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+}
+
+// newBlock extracts the changes of the block. Writes, deletes and events of invalid
+// transactions are not included since they were not committed to the ledger. Blocks which were
+// not validated by a peer, such as blocks delivered by an orderer, are rejected since the
+// committed changes are not known.
+func newBlock(channelID string, block *cb.Block) (*Block, error) {
+	decoded, err := blockdecoder.Decode(block)
+	if err != nil {
+		return nil, errors.WithMessage(err, "decoding of block failed")
+	}
+
+	b := &Block{
+		ChannelID:    channelID,
+		Number:       decoded.Number,
+		Hash:         blockverifier.HeaderHash(block.Header),
+		PreviousHash: decoded.PreviousHash,
+	}
+	for _, tx := range decoded.Transactions {
+		if !tx.Validated {
+			return nil, errors.Errorf("block %d has no transaction filter, only blocks committed by a peer can be replicated", decoded.Number)
+		}
+		t := &Transaction{TxID: tx.TxID, Type: tx.Type, ValidationCode: tx.ValidationCode}
+		if tx.ValidationCode == pb.TxValidationCode_VALID {
+			for _, action := range tx.Actions {
+				addAction(t, action)
+			}
+		}
+		b.Transactions = append(b.Transactions, t)
+	}
+	return b, nil
+}
+
+func addAction(tx *Transaction, action *blockdecoder.Action) {
+	if action.Event != nil && action.Event.EventName != "" {
+		tx.Events = append(tx.Events, action.Event)
+	}
+	if action.RWSet == nil {
+		return
+	}
+	for _, ns := range action.RWSet.NsRWSets {
+		for _, write := range ns.Writes {
+			tx.Writes = append(tx.Writes, &Write{Namespace: ns.Namespace, Key: write.Key, Value: write.Value})
+		}
+		for _, del := range ns.Deletes {
+			tx.Deletes = append(tx.Deletes, &Delete{Namespace: ns.Namespace, Key: del.Key})
+		}
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/urlutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// AnchorPeersStatus compares the anchor peers of the client's organisation on a channel with
+// the peers of the organisation in the network configuration
+type AnchorPeersStatus struct {
+	// Name of the organisation in the channel configuration
+	Org string
+	// Anchor peers currently set on the channel
+	Current []*pb.AnchorPeer
+	// Anchor peers derived from the network configuration
+	Configured []*pb.AnchorPeer
+	// Configured anchor peers which are not set on the channel
+	Added []*pb.AnchorPeer
+	// Anchor peers set on the channel which are not configured
+	Removed []*pb.AnchorPeer
+}
+
+// UpToDate returns true if the channel's anchor peers match the configured anchor peers
+func (s *AnchorPeersStatus) UpToDate() bool {
+	return len(s.Added) == 0 && len(s.Removed) == 0
+}
+
+// QueryAnchorPeers returns the anchor peers of all organisations of the channel
+func (rc *Client) QueryAnchorPeers(channelID string, options ...RequestOption) ([]*fab.OrgAnchorPeer, error) {
+	config, err := rc.QueryChannelConfig(channelID, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var orgAnchorPeers []*fab.OrgAnchorPeer
+	for _, org := range config.ApplicationOrgs() {
+		anchorPeers, err := config.AnchorPeers(org)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("reading anchor peers of organisation [%s] failed", org))
+		}
+		for _, anchorPeer := range anchorPeers {
+			orgAnchorPeers = append(orgAnchorPeers, &fab.OrgAnchorPeer{Org: org, Host: anchorPeer.Host, Port: anchorPeer.Port})
+		}
+	}
+	return orgAnchorPeers, nil
+}
+
+// ConfiguredAnchorPeers returns the peers of the client's organisation in the network
+// configuration as anchor peers
+func (rc *Client) ConfiguredAnchorPeers() ([]*pb.AnchorPeer, error) {
+	networkPeers, err := rc.provider.Config().NetworkPeers()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load network peers")
+	}
+
+	var anchorPeers []*pb.AnchorPeer
+	for _, networkPeer := range networkPeers {
+		if networkPeer.MspID != rc.identity.MspID() {
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(urlutil.ToAddress(networkPeer.URL))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid peer URL [%s]", networkPeer.URL)
+		}
+		portNumber, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid port of peer URL [%s]", networkPeer.URL)
+		}
+		anchorPeers = append(anchorPeers, &pb.AnchorPeer{Host: host, Port: int32(portNumber)})
+	}
+
+	if len(anchorPeers) == 0 {
+		return nil, errors.Errorf("no peers of organisation [%s] are configured", rc.identity.MspID())
+	}
+	sortAnchorPeers(anchorPeers)
+	return anchorPeers, nil
+}
+
+// CompareAnchorPeers compares the anchor peers of the client's organisation on the channel with
+// the peers of the organisation in the network configuration
+func (rc *Client) CompareAnchorPeers(channelID string, options ...RequestOption) (*AnchorPeersStatus, error) {
+	config, err := rc.QueryChannelConfig(channelID, options...)
+	if err != nil {
+		return nil, err
+	}
+	return rc.anchorPeersStatus(config)
+}
+
+// UpdateAnchorPeers sets the anchor peers of the client's organisation on the channel to the peers
+// of the organisation in the network configuration. The update is signed by the identity of the
+// client, which has to be an admin of the organisation. No update is submitted if the anchor peers
+// are up to date. The returned status describes the anchor peers before the update.
+func (rc *Client) UpdateAnchorPeers(channelID string, options ...RequestOption) (*AnchorPeersStatus, error) {
+	original, err := rc.QueryChannelConfig(channelID, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := rc.anchorPeersStatus(original)
+	if err != nil {
+		return nil, err
+	}
+	if status.UpToDate() {
+		logger.Debugf("anchor peers of organisation [%s] on channel [%s] are up to date", status.Org, channelID)
+		return status, nil
+	}
+
+	updated := original.Clone()
+	if err := updated.SetAnchorPeers(status.Org, status.Configured); err != nil {
+		return nil, err
+	}
+	configUpdate, err := rc.CreateConfigUpdate(original, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	req := UpdateChannelConfigRequest{ChannelID: channelID, ConfigUpdate: configUpdate}
+	if err := rc.UpdateChannelConfig(req, options...); err != nil {
+		return nil, errors.WithMessage(err, "anchor peers update failed")
+	}
+	return status, nil
+}
+
+func (rc *Client) anchorPeersStatus(config *configtx.Config) (*AnchorPeersStatus, error) {
+	org, err := config.ApplicationOrgByMSPID(rc.identity.MspID())
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := config.AnchorPeers(org)
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading anchor peers failed")
+	}
+	sortAnchorPeers(current)
+
+	configured, err := rc.ConfiguredAnchorPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnchorPeersStatus{
+		Org:        org,
+		Current:    current,
+		Configured: configured,
+		Added:      subtractAnchorPeers(configured, current),
+		Removed:    subtractAnchorPeers(current, configured),
+	}, nil
+}
+
+// subtractAnchorPeers returns the anchor peers of a which are not in b
+func subtractAnchorPeers(a, b []*pb.AnchorPeer) []*pb.AnchorPeer {
+	var difference []*pb.AnchorPeer
+	for _, anchorPeer := range a {
+		found := false
+		for _, other := range b {
+			if anchorPeer.Host == other.Host && anchorPeer.Port == other.Port {
+				found = true
+				break
+			}
+		}
+		if !found {
+			difference = append(difference, anchorPeer)
+		}
+	}
+	return difference
+}
+
+func sortAnchorPeers(anchorPeers []*pb.AnchorPeer) {
+	sort.Slice(anchorPeers, func(i, j int) bool {
+		if anchorPeers[i].Host != anchorPeers[j].Host {
+			return anchorPeers[i].Host < anchorPeers[j].Host
+		}
+		return anchorPeers[i].Port < anchorPeers[j].Port
+	})
+}
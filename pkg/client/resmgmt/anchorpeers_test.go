@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestUpdateAnchorPeers(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(&mockConfigLedger{configEnvelope: newTestConfigEnvelope(t)})
+
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer.SetMSPID("Org1MSP")
+
+	configured, err := rc.ConfiguredAnchorPeers()
+	if err != nil {
+		t.Fatalf("Failed to get configured anchor peers: %s", err)
+	}
+	if !assert.NotEmpty(t, configured) {
+		t.FailNow()
+	}
+	assert.Equal(t, "peer0.org1.example.com", configured[0].Host)
+	assert.Equal(t, int32(7051), configured[0].Port)
+
+	status, err := rc.CompareAnchorPeers("mychannel", WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to compare anchor peers: %s", err)
+	}
+	assert.Equal(t, "Org1MSP", status.Org)
+	assert.Empty(t, status.Current)
+	assert.Equal(t, configured, status.Added)
+	assert.False(t, status.UpToDate())
+
+	status, err = rc.UpdateAnchorPeers("mychannel", WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to update anchor peers: %s", err)
+	}
+	assert.False(t, status.UpToDate())
+}
+
+func TestUpdateAnchorPeersUpToDate(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	configured, err := rc.ConfiguredAnchorPeers()
+	if err != nil {
+		t.Fatalf("Failed to get configured anchor peers: %s", err)
+	}
+
+	configEnvelope := newTestConfigEnvelope(t)
+	anchorPeers, err := proto.Marshal(&pb.AnchorPeers{AnchorPeers: []*pb.AnchorPeer{{Host: "peer0.org2.example.com", Port: 8051}}})
+	if err != nil {
+		t.Fatalf("Failed to marshal anchor peers: %s", err)
+	}
+	org2 := configEnvelope.Config.ChannelGroup.Groups[configtx.ApplicationGroupKey].Groups["Org2MSP"]
+	org2.Values[channelconfig.AnchorPeersKey] = &common.ConfigValue{Value: anchorPeers, ModPolicy: "Admins"}
+	anchorPeers, err = proto.Marshal(&pb.AnchorPeers{AnchorPeers: configured})
+	if err != nil {
+		t.Fatalf("Failed to marshal anchor peers: %s", err)
+	}
+	org1 := configEnvelope.Config.ChannelGroup.Groups[configtx.ApplicationGroupKey].Groups["Org1MSP"]
+	org1.Values[channelconfig.AnchorPeersKey] = &common.ConfigValue{Value: anchorPeers, ModPolicy: "Admins"}
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(&mockConfigLedger{configEnvelope: configEnvelope})
+
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer.SetMSPID("Org1MSP")
+
+	orgAnchorPeers, err := rc.QueryAnchorPeers("mychannel", WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to query anchor peers: %s", err)
+	}
+	if !assert.Len(t, orgAnchorPeers, len(configured)+1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "Org1MSP", orgAnchorPeers[0].Org)
+	assert.Equal(t, "Org2MSP", orgAnchorPeers[len(orgAnchorPeers)-1].Org)
+	assert.Equal(t, int32(8051), orgAnchorPeers[len(orgAnchorPeers)-1].Port)
+
+	// The update is skipped, so the failing resource is not used
+	rc.resource = fcmocks.NewMockInvalidResource()
+	status, err := rc.UpdateAnchorPeers("mychannel", WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to update anchor peers: %s", err)
+	}
+	assert.True(t, status.UpToDate())
+
+	// An organisation which is not a member of the channel has no anchor peers
+	rc.identity = setupTestContext("user", "Org9MSP")
+	_, err = rc.CompareAnchorPeers("mychannel", WithTargets(peer))
+	assert.NotNil(t, err, "expected error for organisation which is not a member of the channel")
+}
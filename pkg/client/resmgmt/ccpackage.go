@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/cdspackager"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// InstallCCRequestFromPackage returns the request which installs the CDS package, e.g. a package
+// read from a .cds file written by 'peer chaincode package'. Signed packages are installed as is.
+func InstallCCRequestFromPackage(pkg *cdspackager.Package) (InstallCCRequest, error) {
+	if pkg == nil || pkg.ChaincodeID() == nil {
+		return InstallCCRequest{}, errors.New("must provide chaincode package")
+	}
+
+	ccID := pkg.ChaincodeID()
+	return InstallCCRequest{Name: ccID.Name, Path: ccID.Path, Version: ccID.Version, Package: pkg.CCPackage()}, nil
+}
+
+// CreateSignedCCPackage creates a signed CDS package of the chaincode with the instantiation
+// policy. The package is endorsed and signed by the given owner or by the identity of the client
+// if no owner is given. Other owners add their endorsements with SignCCPackage.
+func (rc *Client) CreateSignedCCPackage(req InstallCCRequest, instantiationPolicy *common.SignaturePolicyEnvelope, owner context.IdentityContext) (*cdspackager.Package, error) {
+	if err := checkRequiredInstallCCParams(req); err != nil {
+		return nil, err
+	}
+
+	spec, err := cdspackager.NewDeploymentSpec(req.Name, req.Path, req.Version, req.Package)
+	if err != nil {
+		return nil, err
+	}
+	return cdspackager.NewSignedPackage(spec, instantiationPolicy, rc.ownerContext(owner))
+}
+
+// SignCCPackage adds the endorsement of the given owner to the signed CDS package. The identity
+// of the client is used if no owner is given.
+func (rc *Client) SignCCPackage(pkg *cdspackager.Package, owner context.IdentityContext) error {
+	if pkg == nil {
+		return errors.New("must provide chaincode package")
+	}
+	return pkg.Sign(rc.ownerContext(owner))
+}
+
+func (rc *Client) ownerContext(owner context.IdentityContext) context.Context {
+	if owner == nil {
+		owner = rc.identity
+	}
+	return Context{ProviderContext: rc.provider, IdentityContext: owner}
+}
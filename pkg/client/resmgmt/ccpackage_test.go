@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/cdspackager"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+)
+
+func TestInstallSignedCCPackage(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+	resource := newJoinMockResource()
+	rc.resource = resource
+
+	req := InstallCCRequest{Name: "ID", Path: "path", Version: "v1", Package: &api.CCPackage{Type: 1, Code: []byte("code")}}
+	pkg, err := rc.CreateSignedCCPackage(req, cauthdsl.SignedByAnyMember([]string{"Org1MSP", "Org2MSP"}), nil)
+	if err != nil {
+		t.Fatalf("Failed to create signed package: %s", err)
+	}
+	if err := rc.SignCCPackage(pkg, fcmocks.NewMockUserWithMSPID("user2", "Org2MSP")); err != nil {
+		t.Fatalf("Failed to sign package: %s", err)
+	}
+	assert.Len(t, pkg.Endorsements(), 2)
+
+	// The package is installed from its file
+	data, err := pkg.Bytes()
+	if err != nil {
+		t.Fatalf("Failed to marshal package: %s", err)
+	}
+	pkg, err = cdspackager.Read(data)
+	if err != nil {
+		t.Fatalf("Failed to read package: %s", err)
+	}
+	installReq, err := InstallCCRequestFromPackage(pkg)
+	if err != nil {
+		t.Fatalf("Failed to create install request: %s", err)
+	}
+	assert.Equal(t, "ID", installReq.Name)
+	assert.Equal(t, "path", installReq.Path)
+	assert.Equal(t, "v1", installReq.Version)
+	assert.NotNil(t, installReq.Package.Signed)
+
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	responses, err := rc.InstallCC(installReq, WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to install signed package: %s", err)
+	}
+	assert.Len(t, responses, 1)
+	assert.Equal(t, "v1", resource.installed["http://peer1.com"])
+
+	_, err = InstallCCRequestFromPackage(nil)
+	assert.NotNil(t, err, "expected error for missing package")
+	assert.NotNil(t, rc.SignCCPackage(nil, nil), "expected error for missing package")
+	_, err = rc.CreateSignedCCPackage(InstallCCRequest{Name: "ID"}, cauthdsl.SignedByMspMember("Org1MSP"), nil)
+	assert.NotNil(t, err, "expected error for missing package")
+}
@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+)
+
+// SaveChannelProfileRequest is used to create a channel from a channel profile
+type SaveChannelProfileRequest struct {
+	// Channel Name (ID)
+	ChannelID string
+	// Profile of the channel, e.g. as returned by ChannelProfileFromConfig
+	Profile *configtx.ChannelProfile
+	// Users that sign the channel creation (defaults to the identity of the client)
+	SigningIdentities []context.IdentityContext
+}
+
+// ChannelProfileFromConfig returns the profile of a channel of the consortium whose members are
+// the given organisations of the network configuration. All organisations with peers are members
+// if no organisations are given. The organisations are named by their MSP IDs.
+func (rc *Client) ChannelProfileFromConfig(consortium string, orgNames ...string) (*configtx.ChannelProfile, error) {
+	networkConfig, err := rc.provider.Config().NetworkConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load network config")
+	}
+
+	if len(orgNames) == 0 {
+		for name, orgConfig := range networkConfig.Organizations {
+			if len(orgConfig.Peers) > 0 {
+				orgNames = append(orgNames, name)
+			}
+		}
+		sort.Strings(orgNames)
+	}
+
+	profile := &configtx.ChannelProfile{Consortium: consortium}
+	for _, name := range orgNames {
+		orgConfig, ok := networkConfig.Organizations[strings.ToLower(name)]
+		if !ok {
+			return nil, errors.Errorf("organisation [%s] not found in network config", name)
+		}
+		if orgConfig.MspID == "" {
+			return nil, errors.Errorf("organisation [%s] must provide MSP ID", name)
+		}
+		profile.Orgs = append(profile.Orgs, &configtx.OrgProfile{Name: orgConfig.MspID})
+	}
+	return profile, nil
+}
+
+// CreateChannelCreationConfigUpdate returns the marshalled config update which creates the channel
+// from the profile. The update is equivalent to the channel creation transaction generated by
+// configtxgen and has to be signed by the admins required by the consortium's channel creation policy.
+func (rc *Client) CreateChannelCreationConfigUpdate(channelID string, profile *configtx.ChannelProfile) ([]byte, error) {
+	configUpdate, err := configtx.NewChannelCreationUpdate(channelID, profile)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating channel creation config update failed")
+	}
+
+	configUpdateBytes, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of config update failed")
+	}
+	return configUpdateBytes, nil
+}
+
+// SaveChannelFromProfile creates the channel from the profile without a channel creation
+// transaction generated by configtxgen
+func (rc *Client) SaveChannelFromProfile(req SaveChannelProfileRequest, options ...RequestOption) error {
+	configUpdate, err := rc.CreateChannelCreationConfigUpdate(req.ChannelID, req.Profile)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("***** Saving channel from profile: %s *****\n", req.ChannelID)
+
+	updateReq := UpdateChannelConfigRequest{
+		ChannelID:         req.ChannelID,
+		ConfigUpdate:      configUpdate,
+		SigningIdentities: req.SigningIdentities,
+	}
+	if err := rc.UpdateChannelConfig(updateReq, options...); err != nil {
+		return errors.WithMessage(err, "save channel from profile failed")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestSaveChannelFromProfile(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	profile, err := rc.ChannelProfileFromConfig("SampleConsortium")
+	if err != nil {
+		t.Fatalf("Failed to create channel profile: %s", err)
+	}
+	assert.Equal(t, "SampleConsortium", profile.Consortium)
+	var names []string
+	for _, org := range profile.Orgs {
+		names = append(names, org.Name)
+	}
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, names)
+
+	profile, err = rc.ChannelProfileFromConfig("SampleConsortium", "Org1")
+	if err != nil {
+		t.Fatalf("Failed to create channel profile: %s", err)
+	}
+	assert.Len(t, profile.Orgs, 1)
+
+	configUpdate, err := rc.CreateChannelCreationConfigUpdate("newchannel", profile)
+	if err != nil {
+		t.Fatalf("Failed to create channel creation config update: %s", err)
+	}
+	update := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdate, update); err != nil {
+		t.Fatalf("Failed to unmarshal config update: %s", err)
+	}
+	assert.Equal(t, "newchannel", update.ChannelId)
+	assert.NotNil(t, update.WriteSet.Groups[configtx.ApplicationGroupKey].Groups["Org1MSP"])
+
+	req := SaveChannelProfileRequest{ChannelID: "newchannel", Profile: profile}
+	if err := rc.SaveChannelFromProfile(req, WithOrdererID("orderer.example.com")); err != nil {
+		t.Fatalf("Failed to save channel from profile: %s", err)
+	}
+}
+
+func TestSaveChannelFromProfileErrors(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	_, err := rc.ChannelProfileFromConfig("SampleConsortium", "Org9")
+	assert.NotNil(t, err, "expected error for unknown organisation")
+
+	profile, err := rc.ChannelProfileFromConfig("SampleConsortium", "Org1")
+	if err != nil {
+		t.Fatalf("Failed to create channel profile: %s", err)
+	}
+
+	err = rc.SaveChannelFromProfile(SaveChannelProfileRequest{Profile: profile})
+	assert.NotNil(t, err, "expected error for missing channel ID")
+
+	err = rc.SaveChannelFromProfile(SaveChannelProfileRequest{ChannelID: "newchannel", Profile: &configtx.ChannelProfile{Orgs: profile.Orgs}})
+	assert.NotNil(t, err, "expected error for missing consortium")
+
+	rc.resource = fcmocks.NewMockInvalidResource()
+	err = rc.SaveChannelFromProfile(SaveChannelProfileRequest{ChannelID: "newchannel", Profile: profile}, WithOrdererID("orderer.example.com"))
+	assert.NotNil(t, err, "expected error from orderer")
+}
@@ -0,0 +1,34 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/collections"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// LoadCollectionConfig loads the private data collections of a chaincode from a file in the
+// collections_config.json format of the peer CLI. The member organisations of the collections
+// are validated against the MSPs of the channel. The result may be set as the CollConfig of an
+// instantiate or upgrade request.
+func (rc *Client) LoadCollectionConfig(channelID string, path string, options ...RequestOption) ([]*common.CollectionConfig, error) {
+	config, err := rc.QueryChannelConfig(channelID, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	mspIDs, err := config.ApplicationMSPIDs()
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading MSPs of channel failed")
+	}
+	if len(mspIDs) == 0 {
+		return nil, errors.Errorf("channel [%s] has no member organisations", channelID)
+	}
+	return collections.FromFile(path, mspIDs...)
+}
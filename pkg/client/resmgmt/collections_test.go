@@ -0,0 +1,39 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestLoadCollectionConfig(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+	configEnvelope := newTestConfigEnvelope(t)
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(&mockConfigLedger{configEnvelope: configEnvelope})
+
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer.SetMSPID("Org1MSP")
+
+	collConfig, err := rc.LoadCollectionConfig("mychannel", "testdata/collections_config.json", WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to load collection config: %s", err)
+	}
+	if !assert.Len(t, collConfig, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "collectionMarbles", collConfig[0].GetStaticCollectionConfig().Name)
+
+	// Org2MSP is no longer a member of the channel
+	delete(configEnvelope.Config.ChannelGroup.Groups[configtx.ApplicationGroupKey].Groups, "Org2MSP")
+	_, err = rc.LoadCollectionConfig("mychannel", "testdata/collections_config.json", WithTargets(peer))
+	assert.NotNil(t, err, "expected error for member org which is not a member of the channel")
+}
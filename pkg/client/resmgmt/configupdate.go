@@ -0,0 +1,223 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// UpdateChannelConfigRequest is used to submit a channel configuration update
+type UpdateChannelConfigRequest struct {
+	// Channel Name (ID)
+	ChannelID string
+	// Marshalled ConfigUpdate, e.g. as returned by CreateConfigUpdate
+	ConfigUpdate []byte
+	// Signatures collected from the admins required by the modification policies
+	Signatures []*common.ConfigSignature
+	// Users that sign the configuration update in addition to the collected signatures
+	SigningIdentities []context.IdentityContext
+}
+
+// QueryChannelConfig returns a modifiable copy of the current configuration of the channel.
+// The configuration is queried from the target peers (or the default targets if none are given).
+func (rc *Client) QueryChannelConfig(channelID string, options ...RequestOption) (*configtx.Config, error) {
+	if channelID == "" {
+		return nil, errors.New("must provide channel ID")
+	}
+
+	opts, err := rc.prepareResmgmtOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get opts for query channel config")
+	}
+
+	targets, err := rc.channelTargets(channelID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	channelService, err := rc.channelProvider.ChannelService(rc.identity, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Unable to get channel service")
+	}
+	ledger, err := channelService.Ledger()
+	if err != nil {
+		return nil, errors.WithMessage(err, "get channel ledger failed")
+	}
+
+	configEnvelope, err := ledger.QueryConfigBlock(peersToTxnProcessors(targets), 1)
+	if err != nil {
+		return nil, errors.WithMessage(err, "query config block failed")
+	}
+
+	return configtx.FromConfigEnvelope(channelID, configEnvelope)
+}
+
+// CreateConfigUpdate returns the marshalled ConfigUpdate which changes the original channel
+// configuration into the updated one. The update has to be signed by the admins required by
+// the modification policies of the changed elements before it is submitted.
+func (rc *Client) CreateConfigUpdate(original, updated *configtx.Config) ([]byte, error) {
+	configUpdate, err := configtx.ComputeUpdate(original, updated)
+	if err != nil {
+		return nil, errors.WithMessage(err, "compute config update failed")
+	}
+
+	configUpdateBytes, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of config update failed")
+	}
+	return configUpdateBytes, nil
+}
+
+// SignConfigUpdate signs the configuration update with the given identity. The identity
+// of the client is used if no signer is given.
+func (rc *Client) SignConfigUpdate(configUpdate []byte, signer context.IdentityContext) (*common.ConfigSignature, error) {
+	if len(configUpdate) == 0 {
+		return nil, errors.New("must provide config update")
+	}
+
+	if signer == nil {
+		signer = rc.identity
+	}
+	if signer == nil {
+		return nil, errors.New("must provide signing user")
+	}
+
+	sigCtx := Context{
+		IdentityContext: signer,
+		ProviderContext: rc.provider,
+	}
+	configSignature, err := resource.CreateConfigSignature(&sigCtx, configUpdate)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing configuration update failed")
+	}
+	return configSignature, nil
+}
+
+// UpdateChannelConfig submits the configuration update to the orderer. The identity of the
+// client signs the update if neither signatures nor signing identities are provided.
+func (rc *Client) UpdateChannelConfig(req UpdateChannelConfigRequest, options ...RequestOption) error {
+	if req.ChannelID == "" || len(req.ConfigUpdate) == 0 {
+		return errors.New("must provide channel ID and config update")
+	}
+
+	opts, err := rc.prepareSaveChannelOpts(options...)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("***** Updating channel config: %s *****\n", req.ChannelID)
+
+	configUpdate := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(req.ConfigUpdate, configUpdate); err != nil {
+		return errors.Wrap(err, "unmarshal of config update failed")
+	}
+	if configUpdate.ChannelId != req.ChannelID {
+		return errors.Errorf("config update is for channel [%s] but request is for channel [%s]", configUpdate.ChannelId, req.ChannelID)
+	}
+
+	signers := req.SigningIdentities
+	if len(signers) == 0 && len(req.Signatures) == 0 {
+		signers = []context.IdentityContext{rc.identity}
+	}
+
+	signatures := append([]*common.ConfigSignature{}, req.Signatures...)
+	for _, signer := range signers {
+		signature, err := rc.SignConfigUpdate(req.ConfigUpdate, signer)
+		if err != nil {
+			return err
+		}
+		signatures = append(signatures, signature)
+	}
+
+	orderer, err := rc.requestOrderer(opts)
+	if err != nil {
+		return err
+	}
+
+	request := api.CreateChannelRequest{
+		Name:       req.ChannelID,
+		Orderer:    orderer,
+		Config:     req.ConfigUpdate,
+		Signatures: signatures,
+	}
+
+	if _, err := rc.resource.CreateChannel(request); err != nil {
+		return errors.WithMessage(err, "update channel config failed")
+	}
+	return nil
+}
+
+// SignConfigUpdateBundle adds the signature of the given identity to the signed update bundle.
+// The identity of the client is used if no signer is given.
+func (rc *Client) SignConfigUpdateBundle(bundle *configtx.SignedUpdate, signer context.IdentityContext) error {
+	if bundle == nil {
+		return errors.New("must provide signed update bundle")
+	}
+
+	signature, err := rc.SignConfigUpdate(bundle.ConfigUpdate, signer)
+	if err != nil {
+		return err
+	}
+	return bundle.AddSignature(signature)
+}
+
+// ConfigUpdateBundleStatus returns which organisations have signed the bundle and whether the
+// signatures satisfy the modification policies of the current channel configuration
+func (rc *Client) ConfigUpdateBundleStatus(bundle *configtx.SignedUpdate, options ...RequestOption) (*configtx.SignatureStatus, error) {
+	if bundle == nil {
+		return nil, errors.New("must provide signed update bundle")
+	}
+
+	config, err := rc.QueryChannelConfig(bundle.ChannelID, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := bundle.Evaluate(config)
+	if err != nil {
+		return nil, errors.WithMessage(err, "evaluating signatures of signed update failed")
+	}
+	return status, nil
+}
+
+// SubmitConfigUpdateBundle submits the config update of the bundle with the collected signatures
+// to the orderer. The update is rejected if the signatures do not satisfy the modification
+// policies of the current channel configuration.
+func (rc *Client) SubmitConfigUpdateBundle(bundle *configtx.SignedUpdate, options ...RequestOption) error {
+	status, err := rc.ConfigUpdateBundleStatus(bundle, options...)
+	if err != nil {
+		return err
+	}
+	if !status.Satisfied {
+		var unsatisfied []string
+		for _, policy := range status.Policies {
+			if !policy.Satisfied {
+				unsatisfied = append(unsatisfied, policy.Path)
+			}
+		}
+		return errors.Errorf("signatures of %v do not satisfy policies %v", status.Signers, unsatisfied)
+	}
+
+	signatures, err := bundle.ConfigSignatures()
+	if err != nil {
+		return err
+	}
+
+	req := UpdateChannelConfigRequest{
+		ChannelID:    bundle.ChannelID,
+		ConfigUpdate: bundle.ConfigUpdate,
+		Signatures:   signatures,
+	}
+	return rc.UpdateChannelConfig(req, options...)
+}
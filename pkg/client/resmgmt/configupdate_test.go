@@ -0,0 +1,226 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// mockConfigLedger returns a fixed config block or error
+type mockConfigLedger struct {
+	fab.ChannelLedger
+	configEnvelope *common.ConfigEnvelope
+	err            error
+}
+
+func (l *mockConfigLedger) QueryConfigBlock(targets []fab.ProposalProcessor, minResponses int) (*common.ConfigEnvelope, error) {
+	return l.configEnvelope, l.err
+}
+
+func TestUpdateChannelConfig(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(&mockConfigLedger{configEnvelope: newTestConfigEnvelope(t)})
+
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer.SetMSPID("Org1MSP")
+
+	original, err := rc.QueryChannelConfig("mychannel", WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to query channel config: %s", err)
+	}
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, original.ApplicationOrgs())
+
+	updated := original.Clone()
+	if err := updated.SetBatchSize(&orderer.BatchSize{MaxMessageCount: 50, AbsoluteMaxBytes: 2000, PreferredMaxBytes: 1000}); err != nil {
+		t.Fatalf("Failed to set batch size: %s", err)
+	}
+
+	configUpdate, err := rc.CreateConfigUpdate(original, updated)
+	if err != nil {
+		t.Fatalf("Failed to create config update: %s", err)
+	}
+
+	signature, err := rc.SignConfigUpdate(configUpdate, nil)
+	if err != nil {
+		t.Fatalf("Failed to sign config update: %s", err)
+	}
+	assert.NotEmpty(t, signature.Signature)
+
+	// Signatures collected from other admins are submitted together with the signature of the given identities
+	req := UpdateChannelConfigRequest{
+		ChannelID:         "mychannel",
+		ConfigUpdate:      configUpdate,
+		Signatures:        []*common.ConfigSignature{signature},
+		SigningIdentities: []context.IdentityContext{setupTestContext("admin", "Org2MSP")},
+	}
+	if err := rc.UpdateChannelConfig(req, WithOrdererID("orderer.example.com")); err != nil {
+		t.Fatalf("Failed to update channel config: %s", err)
+	}
+
+	// The identity of the client signs the update by default
+	if err := rc.UpdateChannelConfig(UpdateChannelConfigRequest{ChannelID: "mychannel", ConfigUpdate: configUpdate}); err != nil {
+		t.Fatalf("Failed to update channel config: %s", err)
+	}
+}
+
+func TestUpdateChannelConfigErrors(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	_, err := rc.QueryChannelConfig("")
+	assert.NotNil(t, err, "expected error for missing channel ID")
+
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(&mockConfigLedger{err: errors.New("query failed")})
+	config, err := rc.QueryChannelConfig("mychannel", WithTargets(fcmocks.NewMockPeer("Peer1", "http://peer1.com")))
+	assert.NotNil(t, err, "expected error for failed config query")
+	assert.Nil(t, config)
+
+	_, err = rc.SignConfigUpdate(nil, nil)
+	assert.NotNil(t, err, "expected error for missing config update")
+
+	err = rc.UpdateChannelConfig(UpdateChannelConfigRequest{ChannelID: "mychannel"})
+	assert.NotNil(t, err, "expected error for missing config update")
+
+	configUpdate, err := proto.Marshal(&common.ConfigUpdate{ChannelId: "otherchannel"})
+	if err != nil {
+		t.Fatalf("Failed to marshal config update: %s", err)
+	}
+	err = rc.UpdateChannelConfig(UpdateChannelConfigRequest{ChannelID: "mychannel", ConfigUpdate: configUpdate})
+	assert.NotNil(t, err, "expected error for config update of another channel")
+
+	configUpdate, err = proto.Marshal(&common.ConfigUpdate{ChannelId: "mychannel"})
+	if err != nil {
+		t.Fatalf("Failed to marshal config update: %s", err)
+	}
+	err = rc.UpdateChannelConfig(UpdateChannelConfigRequest{ChannelID: "mychannel", ConfigUpdate: configUpdate}, WithOrdererID("Invalid"))
+	assert.NotNil(t, err, "expected error for invalid orderer ID")
+}
+
+// bundleSigner is an identity with a serialized identity as creator
+type bundleSigner struct {
+	context.IdentityContext
+	mspID string
+}
+
+func (s *bundleSigner) MspID() string {
+	return s.mspID
+}
+
+func (s *bundleSigner) Identity() ([]byte, error) {
+	return proto.Marshal(&mspproto.SerializedIdentity{Mspid: s.mspID, IdBytes: []byte(s.mspID + " cert")})
+}
+
+func TestConfigUpdateBundle(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(&mockConfigLedger{configEnvelope: newPolicyTestConfigEnvelope(t)})
+
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer.SetMSPID("Org1MSP")
+
+	configUpdate, err := rc.CreateRemoveOrgConfigUpdate("mychannel", "Org2MSP", WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to create config update: %s", err)
+	}
+	bundle, err := configtx.NewSignedUpdate(configUpdate, "remove Org2MSP")
+	if err != nil {
+		t.Fatalf("Failed to create signed update: %s", err)
+	}
+
+	// The admin of the first organisation signs and passes the bundle on
+	if err := rc.SignConfigUpdateBundle(bundle, &bundleSigner{IdentityContext: setupTestContext("admin", "Org1MSP"), mspID: "Org1MSP"}); err != nil {
+		t.Fatalf("Failed to sign bundle: %s", err)
+	}
+	b, err := bundle.Bytes()
+	if err != nil {
+		t.Fatalf("Failed to marshal bundle: %s", err)
+	}
+
+	bundle, err = configtx.ParseSignedUpdate(b)
+	if err != nil {
+		t.Fatalf("Failed to parse bundle: %s", err)
+	}
+	status, err := rc.ConfigUpdateBundleStatus(bundle, WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to get bundle status: %s", err)
+	}
+	assert.False(t, status.Satisfied)
+	assert.Equal(t, []string{"Org1MSP"}, status.Signers)
+	assert.NotNil(t, rc.SubmitConfigUpdateBundle(bundle, WithTargets(peer)), "expected error for unsatisfied policy")
+
+	// The admin of the second organisation completes the majority
+	if err := rc.SignConfigUpdateBundle(bundle, &bundleSigner{IdentityContext: setupTestContext("admin", "Org2MSP"), mspID: "Org2MSP"}); err != nil {
+		t.Fatalf("Failed to sign bundle: %s", err)
+	}
+	status, err = rc.ConfigUpdateBundleStatus(bundle, WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to get bundle status: %s", err)
+	}
+	assert.True(t, status.Satisfied)
+	if err := rc.SubmitConfigUpdateBundle(bundle, WithTargets(peer)); err != nil {
+		t.Fatalf("Failed to submit bundle: %s", err)
+	}
+
+	assert.NotNil(t, rc.SignConfigUpdateBundle(nil, nil), "expected error for missing bundle")
+}
+
+// newPolicyTestConfigEnvelope returns a test config in which a majority of the application
+// organisations may modify the application group
+func newPolicyTestConfigEnvelope(t *testing.T) *common.ConfigEnvelope {
+	configEnvelope := newTestConfigEnvelope(t)
+	application := configEnvelope.Config.ChannelGroup.Groups[configtx.ApplicationGroupKey]
+
+	implicitMeta, err := proto.Marshal(&common.ImplicitMetaPolicy{SubPolicy: "Admins", Rule: common.ImplicitMetaPolicy_MAJORITY})
+	if err != nil {
+		t.Fatalf("Failed to marshal policy: %s", err)
+	}
+	application.Policies["Admins"] = &common.ConfigPolicy{ModPolicy: "Admins", Policy: &common.Policy{Type: int32(common.Policy_IMPLICIT_META), Value: implicitMeta}}
+
+	for name, org := range application.Groups {
+		member, err := proto.Marshal(cauthdsl.SignedByMspMember(name))
+		if err != nil {
+			t.Fatalf("Failed to marshal policy: %s", err)
+		}
+		org.Policies["Admins"] = &common.ConfigPolicy{ModPolicy: "Admins", Policy: &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: member}}
+	}
+	return configEnvelope
+}
+
+func newTestConfigEnvelope(t *testing.T) *common.ConfigEnvelope {
+	builder := &fcmocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: fcmocks.MockConfigGroupBuilder{
+			ModPolicy: "Admins",
+			MSPNames:  []string{"Org1MSP", "Org2MSP"},
+		},
+	}
+
+	envelope, err := utils.ExtractEnvelope(builder.Build(), 0)
+	if err != nil {
+		t.Fatalf("Failed to extract envelope: %s", err)
+	}
+	payload, err := utils.ExtractPayload(envelope)
+	if err != nil {
+		t.Fatalf("Failed to extract payload: %s", err)
+	}
+	configEnvelope := &common.ConfigEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configEnvelope); err != nil {
+		t.Fatalf("Failed to unmarshal config envelope: %s", err)
+	}
+	return configEnvelope
+}
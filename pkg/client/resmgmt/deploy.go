@@ -0,0 +1,393 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// DeploymentAction is the action performed by a step of a deployment plan
+type DeploymentAction string
+
+const (
+	// InstallAction installs the chaincode on a peer
+	InstallAction DeploymentAction = "install"
+	// InstantiateAction instantiates the chaincode on a channel
+	InstantiateAction DeploymentAction = "instantiate"
+	// UpgradeAction upgrades the chaincode on a channel
+	UpgradeAction DeploymentAction = "upgrade"
+	// NoAction indicates that the peer or channel is up to date
+	NoAction DeploymentAction = "none"
+)
+
+// DeploymentRequest declares the chaincode which should be installed on the peers of the given
+// organisations and instantiated on the given channels
+type DeploymentRequest struct {
+	Name       string
+	Path       string
+	Version    string
+	Package    *api.CCPackage
+	Args       [][]byte
+	Policy     *common.SignaturePolicyEnvelope
+	CollConfig []*common.CollectionConfig
+	// MSP IDs of the organisations whose peers install the chaincode. The peers must also be
+	// accepted by the target filter of the options or else of the client. Ignored if targets
+	// are given in the options.
+	Orgs []string
+	// Channels on which the chaincode is instantiated or upgraded. The deployment fails if a
+	// higher version is already instantiated on a channel.
+	Channels []string
+}
+
+// DeploymentStep is a step of a deployment plan
+type DeploymentStep struct {
+	Action DeploymentAction
+	// Target peer of an install step
+	Target string
+	// Channel of an instantiate or upgrade step
+	ChannelID string
+	// Version of the chaincode instantiated on the channel before the deployment (if any)
+	CurrentVersion string
+
+	peer fab.Peer
+}
+
+// String returns a description of the step
+func (s *DeploymentStep) String() string {
+	switch s.Action {
+	case InstallAction:
+		return fmt.Sprintf("install on %s", s.Target)
+	case InstantiateAction:
+		return fmt.Sprintf("instantiate on channel %s", s.ChannelID)
+	case UpgradeAction:
+		return fmt.Sprintf("upgrade on channel %s from version %s", s.ChannelID, s.CurrentVersion)
+	}
+	if s.ChannelID != "" {
+		return fmt.Sprintf("already instantiated on channel %s", s.ChannelID)
+	}
+	return fmt.Sprintf("already installed on %s", s.Target)
+}
+
+// DeploymentPlan contains the steps which deploy a chaincode. Installs precede the instantiates
+// and upgrades, and steps which are already applied have no action.
+type DeploymentPlan struct {
+	Request DeploymentRequest
+	Steps   []*DeploymentStep
+}
+
+// UpToDate returns true if no step of the plan has an action
+func (p *DeploymentPlan) UpToDate() bool {
+	for _, step := range p.Steps {
+		if step.Action != NoAction {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a description of the plan, e.g. to print the result of a dry run
+func (p *DeploymentPlan) String() string {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "deployment of chaincode %s:%s\n", p.Request.Name, p.Request.Version)
+	for _, step := range p.Steps {
+		fmt.Fprintf(&buffer, "  %-11s %s\n", step.Action, step)
+	}
+	return buffer.String()
+}
+
+// DeploymentStepResult contains the outcome of a step of a deployment plan
+type DeploymentStepResult struct {
+	Step *DeploymentStep
+	// Skipped is true if the step had no action or was found to be applied already
+	Skipped bool
+	Err     error
+}
+
+// PlanDeployment computes the steps which deploy the chaincode without changing the network.
+// The plan may be printed as a dry run and applied with ApplyDeployment.
+func (rc *Client) PlanDeployment(req DeploymentRequest, options ...RequestOption) (*DeploymentPlan, error) {
+	if err := checkRequiredInstallCCParams(InstallCCRequest{Name: req.Name, Path: req.Path, Version: req.Version, Package: req.Package}); err != nil {
+		return nil, err
+	}
+	if len(req.Channels) > 0 && req.Policy == nil {
+		return nil, errors.New("Chaincode policy is required to instantiate chaincode")
+	}
+
+	opts, err := rc.prepareResmgmtOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get opts for deployment")
+	}
+
+	targets, err := rc.deploymentTargets(req, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &DeploymentPlan{Request: req}
+	installReq := deploymentInstallRequest(req)
+	for _, target := range targets {
+		installed, err := rc.isChaincodeInstalled(installReq, target)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("unable to verify if cc is installed on %s", target.URL()))
+		}
+		step := &DeploymentStep{Action: InstallAction, Target: target.URL(), peer: target}
+		if installed {
+			step.Action = NoAction
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	for _, channelID := range req.Channels {
+		currentVersion, err := rc.instantiatedVersion(channelID, req.Name, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkNotDowngrade(channelID, req, currentVersion); err != nil {
+			return nil, err
+		}
+		step := &DeploymentStep{Action: InstantiateAction, ChannelID: channelID, CurrentVersion: currentVersion}
+		if currentVersion == req.Version {
+			step.Action = NoAction
+		} else if currentVersion != "" {
+			step.Action = UpgradeAction
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan, nil
+}
+
+// ApplyDeployment applies the steps of the plan and returns the result of each step. Installs are
+// applied in parallel; instantiates and upgrades are applied once all installs succeeded. A step
+// is skipped if it is found to be applied already, so a plan may be applied again after a failure.
+func (rc *Client) ApplyDeployment(plan *DeploymentPlan, options ...RequestOption) ([]DeploymentStepResult, error) {
+	if plan == nil {
+		return nil, errors.New("must provide deployment plan")
+	}
+
+	opts, err := rc.prepareResmgmtOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get opts for deployment")
+	}
+
+	results := make([]DeploymentStepResult, len(plan.Steps))
+	var installs []fab.Peer
+	var installSteps []int
+	for i, step := range plan.Steps {
+		results[i] = DeploymentStepResult{Step: step, Skipped: step.Action == NoAction}
+		if step.Action == InstallAction {
+			installs = append(installs, step.peer)
+			installSteps = append(installSteps, i)
+		}
+	}
+
+	installReq := deploymentInstallRequest(plan.Request)
+	forEachTarget(installs, opts.Parallelism, func(i int, target fab.Peer) {
+		response := rc.installTarget(installReq, target)
+		results[installSteps[i]].Skipped = response.AlreadyInstalled
+		results[installSteps[i]].Err = response.Err
+	})
+
+	var errs multi.Errors
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, errors.WithMessage(result.Err, fmt.Sprintf("%s failed", result.Step)))
+		}
+	}
+
+	for i, step := range plan.Steps {
+		if step.Action != InstantiateAction && step.Action != UpgradeAction {
+			continue
+		}
+		if len(errs) > 0 {
+			results[i].Err = errors.New("not applied since previous steps failed")
+			continue
+		}
+		skipped, err := rc.applyChannelStep(plan.Request, step, opts, options...)
+		results[i].Skipped = skipped
+		if err != nil {
+			results[i].Err = err
+			errs = append(errs, errors.WithMessage(err, fmt.Sprintf("%s failed", step)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return results, errors.WithMessage(errs.ToError(), "deployment failed")
+	}
+	return results, nil
+}
+
+// Deploy computes the deployment plan of the chaincode and applies it
+func (rc *Client) Deploy(req DeploymentRequest, options ...RequestOption) ([]DeploymentStepResult, error) {
+	plan, err := rc.PlanDeployment(req, options...)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("applying %s", plan)
+	return rc.ApplyDeployment(plan, options...)
+}
+
+// applyChannelStep instantiates or upgrades the chaincode on the channel unless the channel
+// has been updated to the version of the request in the meantime
+func (rc *Client) applyChannelStep(req DeploymentRequest, step *DeploymentStep, opts Opts, options ...RequestOption) (bool, error) {
+	currentVersion, err := rc.instantiatedVersion(step.ChannelID, req.Name, opts)
+	if err != nil {
+		return false, err
+	}
+	if currentVersion == req.Version {
+		return true, nil
+	}
+	if err := checkNotDowngrade(step.ChannelID, req, currentVersion); err != nil {
+		return false, err
+	}
+
+	ccReq := InstantiateCCRequest{
+		Name:       req.Name,
+		Path:       req.Path,
+		Version:    req.Version,
+		Args:       req.Args,
+		Policy:     req.Policy,
+		CollConfig: req.CollConfig,
+	}
+	if currentVersion == "" {
+		return false, rc.InstantiateCC(step.ChannelID, ccReq, options...)
+	}
+	return false, rc.UpgradeCC(step.ChannelID, UpgradeCCRequest(ccReq), options...)
+}
+
+// instantiatedVersion returns the version of the chaincode instantiated on the channel or an
+// empty string if the chaincode is not instantiated. All targets of the channel are queried
+// since a peer may lag behind an upgrade, and the highest version reported is returned.
+func (rc *Client) instantiatedVersion(channelID string, name string, opts Opts) (string, error) {
+	targets, err := rc.channelTargets(channelID, opts)
+	if err != nil {
+		return "", err
+	}
+
+	channelService, err := rc.channelProvider.ChannelService(rc.identity, channelID)
+	if err != nil {
+		return "", errors.WithMessage(err, "Unable to get channel service")
+	}
+	ledger, err := channelService.Ledger()
+	if err != nil {
+		return "", errors.WithMessage(err, "get channel ledger failed")
+	}
+
+	responses, err := ledger.QueryInstantiatedChaincodes(peersToTxnProcessors(targets))
+	if len(responses) == 0 {
+		if err == nil {
+			err = errors.New("no responses")
+		}
+		return "", errors.WithMessage(err, fmt.Sprintf("query instantiated chaincodes on channel %s failed", channelID))
+	}
+	if err != nil {
+		logger.Warnf("query instantiated chaincodes on channel %s failed on some targets: %s", channelID, err)
+	}
+
+	var version string
+	for _, response := range responses {
+		for _, chaincode := range response.Chaincodes {
+			if chaincode.Name == name && (version == "" || compareVersions(chaincode.Version, version) > 0) {
+				version = chaincode.Version
+			}
+		}
+	}
+	return version, nil
+}
+
+// checkNotDowngrade returns an error if the version of the request is lower than the version
+// instantiated on the channel, since a deployment never downgrades a chaincode
+func checkNotDowngrade(channelID string, req DeploymentRequest, currentVersion string) error {
+	if currentVersion != "" && compareVersions(req.Version, currentVersion) < 0 {
+		return errors.Errorf("chaincode %s version %s is lower than version %s instantiated on channel %s",
+			req.Name, req.Version, currentVersion, channelID)
+	}
+	return nil
+}
+
+// compareVersions compares chaincode versions by their dot separated parts, numerically if both
+// parts are numbers (so that 1.10 is higher than 1.9) and lexically otherwise
+func compareVersions(v1 string, v2 string) int {
+	parts1 := strings.Split(v1, ".")
+	parts2 := strings.Split(v2, ".")
+	for i := 0; i < len(parts1) && i < len(parts2); i++ {
+		n1, err1 := strconv.Atoi(parts1[i])
+		n2, err2 := strconv.Atoi(parts2[i])
+		if err1 != nil || err2 != nil {
+			if c := strings.Compare(parts1[i], parts2[i]); c != 0 {
+				return c
+			}
+			continue
+		}
+		if n1 != n2 {
+			if n1 < n2 {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(parts1) < len(parts2):
+		return -1
+	case len(parts1) > len(parts2):
+		return 1
+	}
+	return 0
+}
+
+// deploymentTargets returns the peers which install the chaincode
+func (rc *Client) deploymentTargets(req DeploymentRequest, opts Opts) ([]fab.Peer, error) {
+	filter := opts.TargetFilter
+	if len(req.Orgs) > 0 && len(opts.Targets) == 0 {
+		if filter == nil {
+			filter = rc.filter
+		}
+		filter = &orgsFilter{mspIDs: req.Orgs, filter: filter}
+	}
+
+	targets, err := rc.calculateTargets(rc.discovery, opts.Targets, filter)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to determine target peers for deployment")
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("No targets available for deployment")
+	}
+	return targets, nil
+}
+
+func deploymentInstallRequest(req DeploymentRequest) InstallCCRequest {
+	return InstallCCRequest{Name: req.Name, Path: req.Path, Version: req.Version, Package: req.Package}
+}
+
+// orgsFilter accepts the peers of the given organisations which are accepted by the
+// filter (if any)
+type orgsFilter struct {
+	mspIDs []string
+	filter TargetFilter
+}
+
+// Accept returns true if the peer belongs to one of the organisations
+func (f *orgsFilter) Accept(peer fab.Peer) bool {
+	if f.filter != nil && !f.filter.Accept(peer) {
+		return false
+	}
+	for _, mspID := range f.mspIDs {
+		if peer.MSPID() == mspID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,296 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestDeploymentPlan(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+	resource := newJoinMockResource()
+	resource.installed["http://peer1.com"] = "v1"
+	rc.resource = resource
+	ledger := &mockCCLedger{chaincodes: map[string]string{"ID": "v0"}}
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(ledger)
+
+	peer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer2 := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+
+	req := DeploymentRequest{
+		Name:     "ID",
+		Path:     "path",
+		Version:  "v1",
+		Package:  &api.CCPackage{Type: 1, Code: []byte("code")},
+		Policy:   cauthdsl.SignedByMspMember("Org1MSP"),
+		Channels: []string{"mychannel"},
+	}
+	plan, err := rc.PlanDeployment(req, WithTargets(peer1, peer2))
+	if err != nil {
+		t.Fatalf("Failed to plan deployment: %s", err)
+	}
+	if !assert.Len(t, plan.Steps, 3) {
+		t.FailNow()
+	}
+	assert.Equal(t, NoAction, plan.Steps[0].Action)
+	assert.Equal(t, "http://peer1.com", plan.Steps[0].Target)
+	assert.Equal(t, InstallAction, plan.Steps[1].Action)
+	assert.Equal(t, "http://peer2.com", plan.Steps[1].Target)
+	assert.Equal(t, UpgradeAction, plan.Steps[2].Action)
+	assert.Equal(t, "v0", plan.Steps[2].CurrentVersion)
+	assert.False(t, plan.UpToDate())
+	assert.Contains(t, plan.String(), "upgrade on channel mychannel from version v0")
+	assert.Contains(t, plan.String(), "already installed on http://peer1.com")
+
+	// The chaincode is instantiated if the channel has no previous version
+	ledger.chaincodes = nil
+	plan, err = rc.PlanDeployment(req, WithTargets(peer1, peer2))
+	if err != nil {
+		t.Fatalf("Failed to plan deployment: %s", err)
+	}
+	assert.Equal(t, InstantiateAction, plan.Steps[2].Action)
+
+	// The channel has been upgraded in the meantime, so only the install is applied
+	ledger.chaincodes = map[string]string{"ID": "v1"}
+	results, err := rc.ApplyDeployment(plan, WithTargets(peer1, peer2))
+	if err != nil {
+		t.Fatalf("Failed to apply deployment: %s", err)
+	}
+	if !assert.Len(t, results, 3) {
+		t.FailNow()
+	}
+	assert.True(t, results[0].Skipped)
+	assert.False(t, results[1].Skipped)
+	assert.Nil(t, results[1].Err)
+	assert.True(t, results[2].Skipped)
+	assert.Equal(t, "v1", resource.installed["http://peer2.com"])
+
+	// Applying the deployment again has no effect
+	plan, err = rc.PlanDeployment(req, WithTargets(peer1, peer2))
+	if err != nil {
+		t.Fatalf("Failed to plan deployment: %s", err)
+	}
+	assert.True(t, plan.UpToDate())
+	results, err = rc.Deploy(req, WithTargets(peer1, peer2))
+	if err != nil {
+		t.Fatalf("Failed to deploy: %s", err)
+	}
+	for _, result := range results {
+		assert.True(t, result.Skipped)
+	}
+}
+
+func TestDeploymentFailure(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+	resource := newJoinMockResource()
+	resource.failing["http://peer2.com"] = true
+	rc.resource = resource
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(&mockCCLedger{})
+
+	peer2 := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+
+	req := DeploymentRequest{
+		Name:     "ID",
+		Path:     "path",
+		Version:  "v1",
+		Package:  &api.CCPackage{Type: 1, Code: []byte("code")},
+		Policy:   cauthdsl.SignedByMspMember("Org1MSP"),
+		Channels: []string{"mychannel"},
+	}
+	results, err := rc.Deploy(req, WithTargets(peer2))
+	assert.NotNil(t, err, "expected error for failed install")
+	if !assert.Len(t, results, 2) {
+		t.FailNow()
+	}
+	assert.NotNil(t, results[0].Err)
+	assert.NotNil(t, results[1].Err, "expected instantiate not to be applied after failed install")
+
+	_, err = rc.PlanDeployment(DeploymentRequest{Name: "ID", Path: "path", Version: "v1"})
+	assert.NotNil(t, err, "expected error for missing package")
+
+	req.Policy = nil
+	_, err = rc.PlanDeployment(req)
+	assert.NotNil(t, err, "expected error for missing policy")
+
+	_, err = rc.ApplyDeployment(nil)
+	assert.NotNil(t, err, "expected error for missing plan")
+}
+
+func TestDeploymentHighestVersion(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+	resource := newJoinMockResource()
+	resource.installed["http://peer1.com"] = "1.10"
+	resource.installed["http://peer2.com"] = "1.10"
+	rc.resource = resource
+
+	// peer1 has not caught up with the upgrade to 1.10 yet
+	ledger := &mockCCLedger{
+		chaincodes:       map[string]string{"ID": "1.10"},
+		targetChaincodes: map[string]map[string]string{"http://peer1.com": {"ID": "1.9"}},
+	}
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(ledger)
+
+	peer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer2 := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+
+	req := DeploymentRequest{
+		Name:     "ID",
+		Path:     "path",
+		Version:  "1.10",
+		Package:  &api.CCPackage{Type: 1, Code: []byte("code")},
+		Policy:   cauthdsl.SignedByMspMember("Org1MSP"),
+		Channels: []string{"mychannel"},
+	}
+	plan, err := rc.PlanDeployment(req, WithTargets(peer1, peer2))
+	if err != nil {
+		t.Fatalf("Failed to plan deployment: %s", err)
+	}
+	assert.True(t, plan.UpToDate(), "expected the highest instantiated version to be used")
+}
+
+func TestDeploymentDowngrade(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+	resource := newJoinMockResource()
+	resource.installed["http://peer1.com"] = "1.9"
+	rc.resource = resource
+	ledger := &mockCCLedger{chaincodes: map[string]string{"ID": "1.10"}}
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(ledger)
+
+	peer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+
+	req := DeploymentRequest{
+		Name:     "ID",
+		Path:     "path",
+		Version:  "1.9",
+		Package:  &api.CCPackage{Type: 1, Code: []byte("code")},
+		Policy:   cauthdsl.SignedByMspMember("Org1MSP"),
+		Channels: []string{"mychannel"},
+	}
+	_, err := rc.PlanDeployment(req, WithTargets(peer1))
+	assert.NotNil(t, err, "expected error for lower version than instantiated")
+
+	// The channel is upgraded to a higher version after the plan has been computed
+	ledger.chaincodes = map[string]string{"ID": "1.8"}
+	plan, err := rc.PlanDeployment(req, WithTargets(peer1))
+	if err != nil {
+		t.Fatalf("Failed to plan deployment: %s", err)
+	}
+	assert.Equal(t, UpgradeAction, plan.Steps[1].Action)
+
+	ledger.chaincodes = map[string]string{"ID": "1.10"}
+	results, err := rc.ApplyDeployment(plan, WithTargets(peer1))
+	assert.NotNil(t, err, "expected error for lower version than instantiated")
+	if !assert.Len(t, results, 2) {
+		t.FailNow()
+	}
+	assert.NotNil(t, results[1].Err, "expected upgrade to be refused")
+	assert.False(t, results[1].Skipped)
+}
+
+func TestDeploymentTargets(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	peer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer1.SetMSPID("Org1MSP")
+	peer2 := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+	peer2.SetMSPID("Org2MSP")
+	peer3 := fcmocks.NewMockPeer("Peer3", "http://peer3.com")
+	peer3.SetMSPID("Org2MSP")
+	discoveryProvider, err := setupTestDiscovery(nil, []fab.Peer{peer1, peer2, peer3})
+	if err != nil {
+		t.Fatalf("Failed to setup discovery service: %s", err)
+	}
+	rc.discovery, err = discoveryProvider.NewDiscoveryService("")
+	if err != nil {
+		t.Fatalf("Failed to setup discovery service: %s", err)
+	}
+
+	// The organisations are restricted by the client's target filter (Org1MSP)
+	targets, err := rc.deploymentTargets(DeploymentRequest{Orgs: []string{"Org1MSP", "Org2MSP"}}, Opts{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []fab.Peer{peer1}, targets)
+	}
+
+	// The organisations are restricted by the target filter of the options
+	targets, err = rc.deploymentTargets(DeploymentRequest{Orgs: []string{"Org2MSP"}}, Opts{TargetFilter: &urlFilter{url: "http://peer3.com"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []fab.Peer{peer3}, targets)
+	}
+
+	_, err = rc.deploymentTargets(DeploymentRequest{Orgs: []string{"Org1MSP"}}, Opts{TargetFilter: &urlFilter{url: "http://peer3.com"}})
+	assert.Error(t, err, "expected error without targets")
+
+	// The organisations are ignored if targets are given
+	targets, err = rc.deploymentTargets(DeploymentRequest{Orgs: []string{"Org1MSP"}}, Opts{Targets: []fab.Peer{peer2}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []fab.Peer{peer2}, targets)
+	}
+}
+
+func TestOrgsFilter(t *testing.T) {
+	filter := &orgsFilter{mspIDs: []string{"Org1MSP", "Org2MSP"}}
+
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer.SetMSPID("Org2MSP")
+	assert.True(t, filter.Accept(peer))
+	peer.SetMSPID("Org3MSP")
+	assert.False(t, filter.Accept(peer))
+
+	filter.filter = &urlFilter{url: "http://peer2.com"}
+	peer.SetMSPID("Org2MSP")
+	assert.False(t, filter.Accept(peer))
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 1, compareVersions("1.10", "1.9"))
+	assert.Equal(t, -1, compareVersions("1.9", "1.10"))
+	assert.Equal(t, 0, compareVersions("1.0", "1.0"))
+	assert.Equal(t, 1, compareVersions("1.0.1", "1.0"))
+	assert.Equal(t, 1, compareVersions("v1", "v0"))
+	assert.Equal(t, -1, compareVersions("1.0-beta", "1.1"))
+}
+
+// urlFilter accepts the peer with the given URL
+type urlFilter struct {
+	url string
+}
+
+func (f *urlFilter) Accept(peer fab.Peer) bool {
+	return peer.URL() == f.url
+}
+
+// mockCCLedger reports the versions of the chaincodes instantiated on the channel, which may
+// differ by target
+type mockCCLedger struct {
+	fab.ChannelLedger
+	chaincodes       map[string]string
+	targetChaincodes map[string]map[string]string
+}
+
+func (l *mockCCLedger) QueryInstantiatedChaincodes(targets []fab.ProposalProcessor) ([]*pb.ChaincodeQueryResponse, error) {
+	var responses []*pb.ChaincodeQueryResponse
+	for _, target := range targets {
+		chaincodes := l.chaincodes
+		if peer, ok := target.(fab.Peer); ok && l.targetChaincodes[peer.URL()] != nil {
+			chaincodes = l.targetChaincodes[peer.URL()]
+		}
+		response := &pb.ChaincodeQueryResponse{}
+		for name, version := range chaincodes {
+			response.Chaincodes = append(response.Chaincodes, &pb.ChaincodeInfo{Name: name, Version: version, Path: "path"})
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"bytes"
+	reqContext "context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// UnsignedCCProposal is a chaincode instantiate or upgrade proposal which is signed outside
+// of the SDK, e.g. by a hardware security module. The signature is computed over Bytes with
+// the key of the proposal's creator.
+type UnsignedCCProposal struct {
+	*txn.UnsignedProposal
+	ChannelID string
+}
+
+// UnsignedCCTransaction is a chaincode instantiate or upgrade transaction which is signed
+// outside of the SDK. The signature is computed over Bytes with the key of the proposal's creator.
+type UnsignedCCTransaction struct {
+	*txn.UnsignedTransaction
+	ChannelID string
+}
+
+// CreateUnsignedInstantiateCCProposal creates a chaincode instantiate proposal without signing it.
+// creator is the serialized identity of the signer; if it is nil then the identity of the client is used.
+func (rc *Client) CreateUnsignedInstantiateCCProposal(channelID string, req InstantiateCCRequest, creator []byte) (*UnsignedCCProposal, error) {
+	return rc.createUnsignedCCProposal(channel.InstantiateChaincode, channelID, req, creator)
+}
+
+// CreateUnsignedUpgradeCCProposal creates a chaincode upgrade proposal without signing it.
+// creator is the serialized identity of the signer; if it is nil then the identity of the client is used.
+func (rc *Client) CreateUnsignedUpgradeCCProposal(channelID string, req UpgradeCCRequest, creator []byte) (*UnsignedCCProposal, error) {
+	return rc.createUnsignedCCProposal(channel.UpgradeChaincode, channelID, InstantiateCCRequest(req), creator)
+}
+
+// SendSignedCCProposal sends the chaincode proposal, together with its signature, to the target
+// peers (specific peers, filtered peers) and returns the endorsements
+func (rc *Client) SendSignedCCProposal(proposal *UnsignedCCProposal, signature []byte, options ...RequestOption) ([]*fab.TransactionProposalResponse, error) {
+	return rc.SendSignedCCProposalWithContext(reqContext.Background(), proposal, signature, options...)
+}
+
+// SendSignedCCProposalWithContext sends the chaincode proposal, together with its signature, to the
+// target peers and returns the endorsements. The proposal is abandoned when ctx is cancelled or its
+// deadline expires. An error is returned unless all endorsements succeeded with the same response.
+func (rc *Client) SendSignedCCProposalWithContext(ctx reqContext.Context, proposal *UnsignedCCProposal, signature []byte, options ...RequestOption) ([]*fab.TransactionProposalResponse, error) {
+	if proposal == nil {
+		return nil, errors.New("proposal is required")
+	}
+	if len(signature) == 0 {
+		return nil, errors.New("signature is required")
+	}
+
+	opts, err := rc.prepareResmgmtOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get opts for cc proposal")
+	}
+
+	targets, err := rc.channelTargets(proposal.ChannelID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	_, transactor, err := rc.channelTransactor(proposal.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	txProposalResponse, err := transactor.SendSignedTransactionProposal(ctx, proposal.Sign(signature), peersToTxnProcessors(targets))
+	if err != nil {
+		return nil, errors.WithMessage(err, "sending deploy transaction proposal failed")
+	}
+	if err := validateCCProposalResponses(txProposalResponse); err != nil {
+		return nil, errors.WithMessage(err, "deploy transaction proposal failed")
+	}
+	return txProposalResponse, nil
+}
+
+// CreateUnsignedCCTransaction creates the transaction for the endorsements returned by
+// SendSignedCCProposal without signing it
+func (rc *Client) CreateUnsignedCCTransaction(proposal *UnsignedCCProposal, responses []*fab.TransactionProposalResponse) (*UnsignedCCTransaction, error) {
+	if proposal == nil {
+		return nil, errors.New("proposal is required")
+	}
+
+	_, transactor, err := rc.channelTransactor(proposal.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := transactor.CreateTransaction(fab.TransactionRequest{
+		Proposal:          proposal.TransactionProposal,
+		ProposalResponses: responses,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "CreateTransaction failed")
+	}
+
+	unsignedTx, err := txn.NewUnsignedTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &UnsignedCCTransaction{UnsignedTransaction: unsignedTx, ChannelID: proposal.ChannelID}, nil
+}
+
+// SendSignedCCTransaction sends the chaincode transaction, together with its signature, to the
+// orderer and waits until it is committed (optional timeout)
+func (rc *Client) SendSignedCCTransaction(tx *UnsignedCCTransaction, signature []byte, options ...RequestOption) error {
+	return rc.SendSignedCCTransactionWithContext(reqContext.Background(), tx, signature, options...)
+}
+
+// SendSignedCCTransactionWithContext sends the chaincode transaction, together with its signature,
+// to the orderer and waits until it is committed (optional timeout). Sending and waiting are
+// abandoned when ctx is cancelled or its deadline expires; the transaction may still be committed.
+func (rc *Client) SendSignedCCTransactionWithContext(ctx reqContext.Context, tx *UnsignedCCTransaction, signature []byte, options ...RequestOption) error {
+	if tx == nil {
+		return errors.New("transaction is required")
+	}
+	if len(signature) == 0 {
+		return errors.New("signature is required")
+	}
+
+	opts, err := rc.prepareResmgmtOpts(options...)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get opts for cc transaction")
+	}
+
+	channelService, transactor, err := rc.channelTransactor(tx.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	return rc.commitCCTransaction(ctx, channelService, tx.TransactionID(), opts, func() error {
+		transactionResponse, err := transactor.SendSignedTransaction(ctx, tx.Sign(signature))
+		if err != nil {
+			return errors.WithMessage(err, "SendSignedTransaction failed")
+		}
+		if transactionResponse.Err != nil {
+			logger.Debugf("orderer %s failed (%s)", transactionResponse.Orderer, transactionResponse.Err.Error())
+			return errors.Wrap(transactionResponse.Err, "orderer failed")
+		}
+		return nil
+	})
+}
+
+// validateCCProposalResponses checks that the endorsements can be assembled into a transaction,
+// i.e. that all endorsers succeeded and returned the same proposal response payload
+func validateCCProposalResponses(responses []*fab.TransactionProposalResponse) error {
+	if len(responses) == 0 {
+		return errors.New("no endorsements received")
+	}
+	for _, r := range responses {
+		if r.ProposalResponse.GetResponse().Status != int32(common.Status_SUCCESS) {
+			return status.NewFromProposalResponse(r.ProposalResponse, r.Endorser)
+		}
+	}
+	for _, r := range responses[1:] {
+		if !bytes.Equal(responses[0].ProposalResponse.Payload, r.ProposalResponse.Payload) {
+			return status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "ProposalResponsePayloads do not match", nil)
+		}
+	}
+	return nil
+}
+
+func (rc *Client) createUnsignedCCProposal(ccProposalType channel.ChaincodeProposalType, channelID string, req InstantiateCCRequest, creator []byte) (*UnsignedCCProposal, error) {
+	if err := checkRequiredCCProposalParams(channelID, req); err != nil {
+		return nil, err
+	}
+
+	if creator == nil {
+		identity, err := rc.identity.Identity()
+		if err != nil {
+			return nil, errors.WithMessage(err, "identity from context failed")
+		}
+		creator = identity
+	}
+
+	txh, err := txn.NewHeaderWithCreator(rc.provider.CryptoSuite(), creator, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "create transaction ID failed")
+	}
+
+	tp, err := channel.CreateChaincodeDeployProposal(txh, ccProposalType, channelID, channel.ChaincodeDeployRequest(req))
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating chaincode deploy transaction proposal failed")
+	}
+
+	unsignedProposal, err := txn.NewUnsignedProposal(tp)
+	if err != nil {
+		return nil, err
+	}
+	return &UnsignedCCProposal{UnsignedProposal: unsignedProposal, ChannelID: channelID}, nil
+}
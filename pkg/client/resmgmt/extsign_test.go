@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	reqContext "context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestCreateUnsignedCCProposal(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	ccPolicy := cauthdsl.SignedByMspMember("Org1MSP")
+	instantiateReq := InstantiateCCRequest{Name: "name", Version: "version", Path: "path", Policy: ccPolicy}
+
+	proposal, err := rc.CreateUnsignedInstantiateCCProposal("mychannel", instantiateReq, []byte("creator"))
+	if err != nil {
+		t.Fatalf("Failed to create unsigned instantiate proposal: %s", err)
+	}
+	assert.Equal(t, "mychannel", proposal.ChannelID)
+	assert.NotEmpty(t, proposal.TxnID)
+
+	p := &pb.Proposal{}
+	if err := proto.Unmarshal(proposal.Bytes, p); err != nil {
+		t.Fatalf("Failed to unmarshal proposal: %s", err)
+	}
+	header := &common.Header{}
+	if err := proto.Unmarshal(p.Header, header); err != nil {
+		t.Fatalf("Failed to unmarshal proposal header: %s", err)
+	}
+	signatureHeader := &common.SignatureHeader{}
+	if err := proto.Unmarshal(header.SignatureHeader, signatureHeader); err != nil {
+		t.Fatalf("Failed to unmarshal signature header: %s", err)
+	}
+	assert.Equal(t, []byte("creator"), signatureHeader.Creator)
+
+	// The identity of the client is used if no creator is given
+	proposal, err = rc.CreateUnsignedUpgradeCCProposal("mychannel", UpgradeCCRequest(instantiateReq), nil)
+	if err != nil {
+		t.Fatalf("Failed to create unsigned upgrade proposal: %s", err)
+	}
+	assert.NotEmpty(t, proposal.Bytes)
+
+	_, err = rc.CreateUnsignedInstantiateCCProposal("", instantiateReq, nil)
+	assert.NotNil(t, err, "expected error for missing channel ID")
+
+	_, err = rc.SendSignedCCProposal(proposal, nil)
+	assert.NotNil(t, err, "expected error for missing signature")
+
+	_, err = rc.CreateUnsignedCCTransaction(nil, nil)
+	assert.NotNil(t, err, "expected error for missing proposal")
+
+	err = rc.SendSignedCCTransaction(nil, []byte("signature"))
+	assert.NotNil(t, err, "expected error for missing transaction")
+}
+
+func TestSendSignedCCProposal(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	ccPolicy := cauthdsl.SignedByMspMember("Org1MSP")
+	instantiateReq := InstantiateCCRequest{Name: "name", Version: "version", Path: "path", Policy: ccPolicy}
+	proposal, err := rc.CreateUnsignedInstantiateCCProposal("mychannel", instantiateReq, nil)
+	if err != nil {
+		t.Fatalf("Failed to create unsigned instantiate proposal: %s", err)
+	}
+
+	peer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer2 := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+	responses, err := rc.SendSignedCCProposalWithContext(reqContext.Background(), proposal, []byte("signature"), WithTargets(peer1, peer2))
+	if err != nil {
+		t.Fatalf("Failed to send signed proposal: %s", err)
+	}
+	assert.Len(t, responses, 2)
+
+	// The endorsements are rejected if a peer fails
+	peer2.Status = 500
+	_, err = rc.SendSignedCCProposal(proposal, []byte("signature"), WithTargets(peer1, peer2))
+	statusError, ok := status.FromError(err)
+	if assert.True(t, ok, "Expected status error got %+v", err) {
+		assert.Equal(t, status.EndorserServerStatus, statusError.Group)
+		assert.EqualValues(t, 500, statusError.Code)
+	}
+}
+
+func TestValidateCCProposalResponses(t *testing.T) {
+	newResponse := func(payload string) *fab.TransactionProposalResponse {
+		return &fab.TransactionProposalResponse{
+			Endorser:         "http://peer1.com",
+			ProposalResponse: &pb.ProposalResponse{Response: &pb.Response{Status: 200}, Payload: []byte(payload)},
+		}
+	}
+
+	assert.Nil(t, validateCCProposalResponses([]*fab.TransactionProposalResponse{newResponse("a"), newResponse("a")}))
+	assert.NotNil(t, validateCCProposalResponses(nil), "expected error without endorsements")
+
+	err := validateCCProposalResponses([]*fab.TransactionProposalResponse{newResponse("a"), newResponse("b")})
+	statusError, ok := status.FromError(err)
+	if assert.True(t, ok, "Expected status error got %+v", err) {
+		assert.EqualValues(t, status.EndorsementMismatch, statusError.Code)
+	}
+}
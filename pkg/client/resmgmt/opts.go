@@ -43,3 +43,19 @@ func WithOrdererID(ordererID string) RequestOption {
 		return nil
 	}
 }
+
+//WithParallelism limits the number of targets which are processed concurrently by JoinChannel, InstallCC and ApplyDeployment
+func WithParallelism(parallelism int) RequestOption {
+	return func(opts *Opts) error {
+		opts.Parallelism = parallelism
+		return nil
+	}
+}
+
+//WithJoinWait makes JoinChannel wait until each joined peer reports the channel, for at most the given timeout
+func WithJoinWait(timeout time.Duration) RequestOption {
+	return func(opts *Opts) error {
+		opts.JoinWait = timeout
+		return nil
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// OrgDefinition defines an organisation which joins a channel or whose MSP is replaced
+type OrgDefinition struct {
+	// Name of the organisation in the channel configuration (defaults to the MSP ID)
+	Name string
+	// MSP ID of the organisation
+	MSPID string
+	// Directory with the verifying MSP of the organisation (cacerts, admincerts, tlscacerts, ...)
+	MSPDir string
+	// Anchor peers of the organisation (optional)
+	AnchorPeers []*pb.AnchorPeer
+}
+
+func (o OrgDefinition) orgName() string {
+	if o.Name != "" {
+		return o.Name
+	}
+	return o.MSPID
+}
+
+// OrgDefinitionFromConfig returns the definition of an organisation of the network configuration.
+// The MSP directory is derived from the crypto path of the organisation, e.g. the crypto path
+// peerOrganizations/org1.example.com/users/{userName}@org1.example.com/msp resolves to the
+// directory peerOrganizations/org1.example.com/msp.
+func (rc *Client) OrgDefinitionFromConfig(orgConfig core.OrganizationConfig) (OrgDefinition, error) {
+	if orgConfig.MspID == "" || orgConfig.CryptoPath == "" {
+		return OrgDefinition{}, errors.New("organisation config must provide MSP ID and crypto path")
+	}
+
+	cryptoPath := orgConfig.CryptoPath
+	if !filepath.IsAbs(cryptoPath) {
+		cryptoPath = filepath.Join(rc.provider.Config().CryptoConfigPath(), cryptoPath)
+	}
+
+	mspDir, err := orgMSPDir(cryptoPath)
+	if err != nil {
+		return OrgDefinition{}, err
+	}
+	return OrgDefinition{MSPID: orgConfig.MspID, MSPDir: mspDir}, nil
+}
+
+// orgMSPDir returns the MSP directory of the organisation for the crypto path of its users
+func orgMSPDir(cryptoPath string) (string, error) {
+	if !strings.Contains(cryptoPath, "{userName}") {
+		return cryptoPath, nil
+	}
+
+	elements := strings.Split(filepath.ToSlash(cryptoPath), "/")
+	for i := len(elements) - 1; i > 0; i-- {
+		if elements[i] == "users" && i+1 < len(elements) && strings.Contains(elements[i+1], "{userName}") {
+			return filepath.Join(filepath.FromSlash(strings.Join(elements[:i], "/")), "msp"), nil
+		}
+	}
+	return "", errors.Errorf("unable to derive organisation MSP directory from crypto path [%s]", cryptoPath)
+}
+
+// CreateAddOrgConfigUpdate returns the config update which adds the organisation to the channel.
+// The update has to be signed by the admins required by the modification policy of the
+// application group before it is submitted with UpdateChannelConfig.
+func (rc *Client) CreateAddOrgConfigUpdate(channelID string, org OrgDefinition, options ...RequestOption) ([]byte, error) {
+	mspConfig, err := configtx.NewMSPConfigFromDir(org.MSPID, org.MSPDir)
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading organisation MSP failed")
+	}
+	orgGroup, err := configtx.NewOrgGroup(mspConfig, org.AnchorPeers)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating organisation config group failed")
+	}
+
+	return rc.createChannelConfigUpdate(channelID, func(config *configtx.Config) error {
+		if config.ApplicationOrg(org.orgName()) != nil {
+			return errors.Errorf("organisation [%s] is already a member of the channel", org.orgName())
+		}
+		return config.SetApplicationOrg(org.orgName(), orgGroup)
+	}, options...)
+}
+
+// CreateRemoveOrgConfigUpdate returns the config update which removes the organisation from the channel
+func (rc *Client) CreateRemoveOrgConfigUpdate(channelID string, orgName string, options ...RequestOption) ([]byte, error) {
+	return rc.createChannelConfigUpdate(channelID, func(config *configtx.Config) error {
+		return config.RemoveApplicationOrg(orgName)
+	}, options...)
+}
+
+// CreateReplaceOrgMSPConfigUpdate returns the config update which replaces the MSP of an organisation
+// of the channel, e.g. after its CA certificates were rotated. The update has to be signed by
+// the admins of the organisation.
+func (rc *Client) CreateReplaceOrgMSPConfigUpdate(channelID string, org OrgDefinition, options ...RequestOption) ([]byte, error) {
+	mspConfig, err := configtx.NewMSPConfigFromDir(org.MSPID, org.MSPDir)
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading organisation MSP failed")
+	}
+
+	return rc.createChannelConfigUpdate(channelID, func(config *configtx.Config) error {
+		if err := config.SetApplicationOrgMSP(org.orgName(), mspConfig); err != nil {
+			return err
+		}
+		if len(org.AnchorPeers) > 0 {
+			return config.SetAnchorPeers(org.orgName(), org.AnchorPeers)
+		}
+		return nil
+	}, options...)
+}
+
+// createChannelConfigUpdate queries the current configuration of the channel and returns the
+// config update for the modifications applied by the given function
+func (rc *Client) createChannelConfigUpdate(channelID string, modify func(config *configtx.Config) error, options ...RequestOption) ([]byte, error) {
+	original, err := rc.QueryChannelConfig(channelID, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := original.Clone()
+	if err := modify(updated); err != nil {
+		return nil, err
+	}
+	return rc.CreateConfigUpdate(original, updated)
+}
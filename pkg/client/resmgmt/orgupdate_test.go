@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const org1MSPDir = "../../../test/fixtures/fabric/v1/crypto-config/peerOrganizations/org1.example.com/msp"
+
+func TestOrgConfigUpdates(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+	rc.channelProvider.(*fcmocks.MockChannelProvider).SetLedger(&mockConfigLedger{configEnvelope: newTestConfigEnvelope(t)})
+
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer.SetMSPID("Org1MSP")
+
+	org3 := OrgDefinition{
+		MSPID:       "Org3MSP",
+		MSPDir:      org1MSPDir,
+		AnchorPeers: []*pb.AnchorPeer{{Host: "peer0.org3.example.com", Port: 7051}},
+	}
+	configUpdate, err := rc.CreateAddOrgConfigUpdate("mychannel", org3, WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to create add org config update: %s", err)
+	}
+	application := applicationWriteSet(t, configUpdate)
+	assert.Equal(t, uint64(1), application.Version)
+	assert.NotNil(t, application.Groups["Org3MSP"])
+	assert.NotNil(t, application.Groups["Org1MSP"])
+
+	configUpdate, err = rc.CreateRemoveOrgConfigUpdate("mychannel", "Org2MSP", WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to create remove org config update: %s", err)
+	}
+	application = applicationWriteSet(t, configUpdate)
+	assert.Equal(t, uint64(1), application.Version)
+	assert.Nil(t, application.Groups["Org2MSP"])
+
+	configUpdate, err = rc.CreateReplaceOrgMSPConfigUpdate("mychannel", OrgDefinition{MSPID: "Org1MSP", MSPDir: org1MSPDir}, WithTargets(peer))
+	if err != nil {
+		t.Fatalf("Failed to create replace org MSP config update: %s", err)
+	}
+	application = applicationWriteSet(t, configUpdate)
+	assert.Equal(t, uint64(0), application.Version)
+	assert.Len(t, application.Groups, 1)
+
+	// The update is submitted like any other config update
+	if err := rc.UpdateChannelConfig(UpdateChannelConfigRequest{ChannelID: "mychannel", ConfigUpdate: configUpdate}); err != nil {
+		t.Fatalf("Failed to update channel config: %s", err)
+	}
+
+	_, err = rc.CreateAddOrgConfigUpdate("mychannel", OrgDefinition{MSPID: "Org1MSP", MSPDir: org1MSPDir}, WithTargets(peer))
+	assert.NotNil(t, err, "expected error for existing org")
+
+	_, err = rc.CreateRemoveOrgConfigUpdate("mychannel", "Org9MSP", WithTargets(peer))
+	assert.NotNil(t, err, "expected error for unknown org")
+
+	_, err = rc.CreateReplaceOrgMSPConfigUpdate("mychannel", OrgDefinition{MSPID: "Org1MSP", MSPDir: "./invalid"}, WithTargets(peer))
+	assert.NotNil(t, err, "expected error for invalid MSP directory")
+}
+
+func TestOrgDefinitionFromConfig(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	org, err := rc.OrgDefinitionFromConfig(core.OrganizationConfig{
+		MspID:      "Org1MSP",
+		CryptoPath: "peerOrganizations/org1.example.com/users/{userName}@org1.example.com/msp",
+	})
+	if err != nil {
+		t.Fatalf("Failed to get org definition: %s", err)
+	}
+	assert.Equal(t, "Org1MSP", org.MSPID)
+	assert.Equal(t, filepath.Join(rc.provider.Config().CryptoConfigPath(), "peerOrganizations/org1.example.com/msp"), org.MSPDir)
+
+	org, err = rc.OrgDefinitionFromConfig(core.OrganizationConfig{MspID: "Org1MSP", CryptoPath: "/opt/msp"})
+	if err != nil {
+		t.Fatalf("Failed to get org definition: %s", err)
+	}
+	assert.Equal(t, "/opt/msp", org.MSPDir)
+
+	_, err = rc.OrgDefinitionFromConfig(core.OrganizationConfig{MspID: "Org1MSP", CryptoPath: "/opt/{userName}/msp"})
+	assert.NotNil(t, err, "expected error for crypto path without users folder")
+
+	_, err = rc.OrgDefinitionFromConfig(core.OrganizationConfig{MspID: "Org1MSP"})
+	assert.NotNil(t, err, "expected error for missing crypto path")
+}
+
+func applicationWriteSet(t *testing.T, configUpdateBytes []byte) *common.ConfigGroup {
+	configUpdate := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdateBytes, configUpdate); err != nil {
+		t.Fatalf("Failed to unmarshal config update: %s", err)
+	}
+	application := configUpdate.WriteSet.Groups[configtx.ApplicationGroupKey]
+	if application == nil {
+		t.Fatalf("Config update does not modify the application group")
+	}
+	return application
+}
@@ -8,11 +8,16 @@ SPDX-License-Identifier: Apache-2.0
 package resmgmt
 
 import (
+	reqContext "context"
+	"fmt"
 	"io/ioutil"
+	"reflect"
+	"sync"
 	"time"
 
 	config "github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
@@ -48,6 +53,21 @@ type InstallCCResponse struct {
 	Target string
 	Status int32
 	Info   string
+	// AlreadyInstalled is true if the chaincode was installed on the target before the request
+	AlreadyInstalled bool
+	// Err is set if the chaincode could not be installed on the target. The status of the
+	// error is available with status.FromError.
+	Err error
+}
+
+// JoinChannelResponse contains the outcome of joining a target peer to a channel
+type JoinChannelResponse struct {
+	Target string
+	// AlreadyJoined is true if the target had joined the channel before the request
+	AlreadyJoined bool
+	// Err is set if the target failed to join the channel. The status of the error
+	// is available with status.FromError.
+	Err error
 }
 
 // InstantiateCCRequest contains instantiate chaincode request parameters
@@ -76,6 +96,8 @@ type Opts struct {
 	TargetFilter TargetFilter  // target filter
 	Timeout      time.Duration //timeout options for instantiate and upgrade CC
 	OrdererID    string        // use specific orderer
+	Parallelism  int           // maximum number of targets processed concurrently by join, install and deployment (all if not set)
+	JoinWait     time.Duration // wait until joined peers report the channel (no wait if not set)
 }
 
 //SaveChannelRequest used to save channel request
@@ -93,6 +115,9 @@ type RequestOption func(opts *Opts) error
 
 var logger = logging.NewLogger("fabric_sdk_go")
 
+// joinPollInterval is the interval at which joined peers are queried while waiting for the join
+var joinPollInterval = 500 * time.Millisecond
+
 // Client enables managing resources in Fabric network.
 type Client struct {
 	provider          context.ProviderContext
@@ -179,56 +204,178 @@ func New(ctx Context, opts ...ClientOption) (*Client, error) {
 
 // JoinChannel allows for peers to join existing channel with optional custom options (specific peers, filtered peers)
 func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error {
+	responses, err := rc.JoinChannelWithResponses(channelID, options...)
+	if err != nil {
+		return err
+	}
+
+	var errs multi.Errors
+	for _, response := range responses {
+		if response.Err != nil {
+			errs = append(errs, errors.WithMessage(response.Err, fmt.Sprintf("join channel failed for %s", response.Target)))
+		}
+	}
+	return errs.ToError()
+}
+
+// JoinChannelWithResponses joins the target peers to an existing channel in parallel and returns
+// the outcome for each target, in the order of the targets. Peers which have already joined the
+// channel are not joined again. The genesis block is retrieved from the first of the channel's
+// orderers which delivers it (or from the orderer selected with WithOrdererID).
+func (rc *Client) JoinChannelWithResponses(channelID string, options ...RequestOption) ([]JoinChannelResponse, error) {
 
 	if channelID == "" {
-		return errors.New("must provide channel ID")
+		return nil, errors.New("must provide channel ID")
 	}
 
 	opts, err := rc.prepareResmgmtOpts(options...)
 	if err != nil {
-		return errors.WithMessage(err, "failed to get opts for JoinChannel")
+		return nil, errors.WithMessage(err, "failed to get opts for JoinChannel")
 	}
 
 	targets, err := rc.calculateTargets(rc.discovery, opts.Targets, opts.TargetFilter)
 	if err != nil {
-		return errors.WithMessage(err, "failed to determine target peers for JoinChannel")
+		return nil, errors.WithMessage(err, "failed to determine target peers for JoinChannel")
 	}
 
 	if len(targets) == 0 {
-		return errors.New("No targets available")
+		return nil, errors.New("No targets available")
 	}
 
-	// TODO: should the code to get orderers from sdk config be part of channel service?
-	oConfig, err := rc.provider.Config().ChannelOrderers(channelID)
+	genesisBlock, err := rc.genesisBlock(channelID, opts)
 	if err != nil {
-		return errors.WithMessage(err, "failed to load orderer config")
-	}
-	if len(oConfig) == 0 {
-		return errors.Errorf("no orderers are configured for channel %s", channelID)
+		return nil, err
 	}
 
-	// TODO: handle more than the first orderer.
-	orderer, err := rc.fabricProvider.CreateOrdererFromConfig(&oConfig[0])
-	if err != nil {
-		return errors.WithMessage(err, "failed to create orderers from config")
-	}
+	responses := make([]JoinChannelResponse, len(targets))
+	forEachTarget(targets, opts.Parallelism, func(i int, target fab.Peer) {
+		responses[i] = rc.joinTarget(channelID, genesisBlock, target, opts)
+	})
+	return responses, nil
+}
 
-	genesisBlock, err := rc.resource.GenesisBlockFromOrderer(channelID, orderer)
+// joinTarget joins a single target to the channel
+func (rc *Client) joinTarget(channelID string, genesisBlock *common.Block, target fab.Peer, opts Opts) JoinChannelResponse {
+	response := JoinChannelResponse{Target: target.URL()}
+
+	joined, err := rc.isChannelJoined(channelID, target)
 	if err != nil {
-		return errors.WithMessage(err, "genesis block retrieval failed")
+		// The join request reports whether the target is able to join
+		logger.Debugf("unable to verify if %s has joined channel %s: %s", target.URL(), channelID, err)
+	}
+	if joined {
+		response.AlreadyJoined = true
+		return response
 	}
 
 	joinChannelRequest := api.JoinChannelRequest{
-		Targets:      peersToTxnProcessors(targets),
+		Targets:      []fab.ProposalProcessor{target},
 		GenesisBlock: genesisBlock,
 	}
+	if err := rc.resource.JoinChannel(joinChannelRequest); err != nil {
+		response.Err = err
+		return response
+	}
+
+	if opts.JoinWait > 0 {
+		response.Err = rc.waitForJoin(channelID, target, opts.JoinWait)
+	}
+	return response
+}
+
+// genesisBlock retrieves the genesis block of the channel from the orderer selected by the options,
+// or else from the first of the channel's orderers which delivers it
+func (rc *Client) genesisBlock(channelID string, opts Opts) (*common.Block, error) {
+	var oConfig []config.OrdererConfig
+	if opts.OrdererID != "" {
+		ordererCfg, err := rc.provider.Config().OrdererConfig(opts.OrdererID)
+		if err != nil || ordererCfg == nil {
+			return nil, errors.Errorf("failed to retrieve orderer config: %s", err)
+		}
+		oConfig = append(oConfig, *ordererCfg)
+	} else {
+		// TODO: should the code to get orderers from sdk config be part of channel service?
+		var err error
+		oConfig, err = rc.provider.Config().ChannelOrderers(channelID)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to load orderer config")
+		}
+	}
+	if len(oConfig) == 0 {
+		return nil, errors.Errorf("no orderers are configured for channel %s", channelID)
+	}
 
-	err = rc.resource.JoinChannel(joinChannelRequest)
+	var errs multi.Errors
+	for i := range oConfig {
+		orderer, err := rc.fabricProvider.CreateOrdererFromConfig(&oConfig[i])
+		if err != nil {
+			errs = append(errs, errors.WithMessage(err, fmt.Sprintf("failed to create orderer %s from config", oConfig[i].URL)))
+			continue
+		}
+
+		genesisBlock, err := rc.resource.GenesisBlockFromOrderer(channelID, orderer)
+		if err != nil {
+			logger.Debugf("genesis block retrieval from orderer %s failed: %s", oConfig[i].URL, err)
+			errs = append(errs, errors.WithMessage(err, fmt.Sprintf("genesis block retrieval from orderer %s failed", oConfig[i].URL)))
+			continue
+		}
+		return genesisBlock, nil
+	}
+	return nil, errors.WithMessage(errs.ToError(), "genesis block retrieval failed")
+}
+
+// isChannelJoined verifies if the peer has joined the channel
+func (rc *Client) isChannelJoined(channelID string, peer fab.Peer) (bool, error) {
+	channelQueryResponse, err := rc.resource.QueryChannels(peer)
 	if err != nil {
-		return errors.WithMessage(err, "join channel failed")
+		return false, err
 	}
 
-	return nil
+	for _, channel := range channelQueryResponse.Channels {
+		if channel.ChannelId == channelID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// waitForJoin waits until the peer reports the channel in its joined channels
+func (rc *Client) waitForJoin(channelID string, peer fab.Peer, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		joined, err := rc.isChannelJoined(channelID, peer)
+		if joined {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return errors.WithMessage(err, fmt.Sprintf("unable to verify if peer joined channel %s", channelID))
+			}
+			return errors.Errorf("peer did not report channel %s within %s", channelID, timeout)
+		}
+		time.Sleep(joinPollInterval)
+	}
+}
+
+// forEachTarget calls the function for each target with at most the given number of concurrent
+// calls (unlimited if parallelism is not positive) and returns when all calls have completed
+func forEachTarget(targets []fab.Peer, parallelism int, fn func(i int, target fab.Peer)) {
+	if parallelism <= 0 || parallelism > len(targets) {
+		parallelism = len(targets)
+	}
+
+	semaphore := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, target fab.Peer) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			fn(i, target)
+		}(i, target)
+	}
+	wg.Wait()
 }
 
 // filterTargets is helper method to filter peers
@@ -271,6 +418,12 @@ func (rc *Client) calculateTargets(discovery fab.DiscoveryService, peers []fab.P
 		return nil, errors.New("If targets are provided, filter cannot be provided")
 	}
 
+	for _, target := range peers {
+		if isNilPeer(target) {
+			return nil, errors.New("target peer is nil")
+		}
+	}
+
 	targets := peers
 	targetFilter := filter
 
@@ -294,6 +447,16 @@ func (rc *Client) calculateTargets(discovery fab.DiscoveryService, peers []fab.P
 	return targets, nil
 }
 
+// isNilPeer returns true if the peer is nil, including a nil pointer of a peer implementation
+// (e.g. returned by peer.New together with an error)
+func isNilPeer(peer fab.Peer) bool {
+	if peer == nil {
+		return true
+	}
+	v := reflect.ValueOf(peer)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
 // isChaincodeInstalled verify if chaincode is installed on peer
 func (rc *Client) isChaincodeInstalled(req InstallCCRequest, peer fab.Peer) (bool, error) {
 	chaincodeQueryResponse, err := rc.resource.QueryInstalledChaincodes(peer)
@@ -315,8 +478,8 @@ func (rc *Client) isChaincodeInstalled(req InstallCCRequest, peer fab.Peer) (boo
 // InstallCC installs chaincode with optional custom options (specific peers, filtered peers)
 func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]InstallCCResponse, error) {
 
-	// For each peer query if chaincode installed. If cc is installed treat as success with message 'already installed'.
-	// If cc is not installed try to install, and if that fails add to the list with error and peer name.
+	// For each peer (in parallel) query if chaincode installed. If cc is installed treat as success with message 'already installed'.
+	// If cc is not installed try to install, and if that fails set the error in the response of the peer.
 
 	err := checkRequiredInstallCCParams(req)
 	if err != nil {
@@ -345,48 +508,48 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 		return nil, errors.New("No targets available for install cc")
 	}
 
-	responses := make([]InstallCCResponse, 0)
-	var errs multi.Errors
+	responses := make([]InstallCCResponse, len(targets))
+	forEachTarget(targets, opts.Parallelism, func(i int, target fab.Peer) {
+		responses[i] = rc.installTarget(req, target)
+	})
 
-	// Targets will be adjusted if cc has already been installed
-	newTargets := make([]fab.Peer, 0)
-	for _, target := range targets {
-		installed, err := rc.isChaincodeInstalled(req, target)
-		if err != nil {
-			// Add to errors with unable to verify error message
-			errs = append(errs, errors.Errorf("unable to verify if cc is installed on %s", target.URL()))
-			continue
-		}
-		if installed {
-			// Nothing to do - add info message to response
-			response := InstallCCResponse{Target: target.URL(), Info: "already installed"}
-			responses = append(responses, response)
-		} else {
-			// Not installed - add for processing
-			newTargets = append(newTargets, target)
+	var errs multi.Errors
+	for _, response := range responses {
+		if response.Err != nil {
+			errs = append(errs, errors.WithMessage(response.Err, fmt.Sprintf("install cc failed for %s", response.Target)))
 		}
 	}
-
-	if len(newTargets) == 0 {
-		// CC is already installed on all targets and/or
-		// we are unable to verify if cc is installed on target(s)
-		return responses, nil
+	if len(errs) > 0 {
+		return responses, errors.WithMessage(errs.ToError(), "InstallChaincode failed")
 	}
 
-	icr := api.InstallChaincodeRequest{Name: req.Name, Path: req.Path, Version: req.Version, Package: req.Package, Targets: peer.PeersToTxnProcessors(newTargets)}
-	transactionProposalResponse, _, err := rc.resource.InstallChaincode(icr)
-	for _, v := range transactionProposalResponse {
-		logger.Debugf("Install chaincode '%s' endorser '%s' returned ProposalResponse status:%v", req.Name, v.Endorser, v.Status)
+	return responses, nil
+}
 
-		response := InstallCCResponse{Target: v.Endorser, Status: v.Status}
-		responses = append(responses, response)
-	}
+// installTarget installs the chaincode on a single target unless it is already installed
+func (rc *Client) installTarget(req InstallCCRequest, target fab.Peer) InstallCCResponse {
+	response := InstallCCResponse{Target: target.URL()}
 
+	installed, err := rc.isChaincodeInstalled(req, target)
 	if err != nil {
-		return responses, errors.WithMessage(err, "InstallChaincode failed")
+		response.Err = errors.WithMessage(err, "unable to verify if cc is installed")
+		return response
+	}
+	if installed {
+		// Nothing to do - add info message to response
+		response.Info = "already installed"
+		response.AlreadyInstalled = true
+		return response
 	}
 
-	return responses, nil
+	icr := api.InstallChaincodeRequest{Name: req.Name, Path: req.Path, Version: req.Version, Package: req.Package, Targets: peer.PeersToTxnProcessors([]fab.Peer{target})}
+	transactionProposalResponse, _, err := rc.resource.InstallChaincode(icr)
+	for _, v := range transactionProposalResponse {
+		logger.Debugf("Install chaincode '%s' endorser '%s' returned ProposalResponse status:%v", req.Name, v.Endorser, v.Status)
+		response.Status = v.Status
+	}
+	response.Err = err
+	return response
 }
 
 func checkRequiredInstallCCParams(req InstallCCRequest) error {
@@ -430,37 +593,15 @@ func (rc *Client) sendCCProposal(ccProposalType channel.ChaincodeProposalType, c
 		return errors.WithMessage(err, "failed to get opts for cc proposal")
 	}
 
-	// per channel discovery service
-	discovery, err := rc.discoveryProvider.NewDiscoveryService(channelID)
-	if err != nil {
-		return errors.WithMessage(err, "failed to create channel discovery service")
-	}
-
-	//Default targets when targets are not provided in options
-	if len(opts.Targets) == 0 {
-		opts.Targets, err = rc.getDefaultTargets(discovery)
-		if err != nil {
-			return errors.WithMessage(err, "failed to get default targets for cc proposal")
-		}
-	}
-
-	targets, err := rc.calculateTargets(discovery, opts.Targets, opts.TargetFilter)
+	targets, err := rc.channelTargets(channelID, opts)
 	if err != nil {
-		return errors.WithMessage(err, "failed to determine target peers for cc proposal")
-	}
-
-	if len(targets) == 0 {
-		return errors.New("No targets available for cc proposal")
+		return err
 	}
 
 	// Get transactor on the channel to create and send the deploy proposal
-	channelService, err := rc.channelProvider.ChannelService(rc.identity, channelID)
+	channelService, transactor, err := rc.channelTransactor(channelID)
 	if err != nil {
-		return errors.WithMessage(err, "Unable to get channel service")
-	}
-	transactor, err := channelService.Transactor()
-	if err != nil {
-		return errors.WithMessage(err, "get channel transactor failed")
+		return err
 	}
 
 	// create a transaction proposal for chaincode deployment
@@ -480,11 +621,66 @@ func (rc *Client) sendCCProposal(ccProposalType channel.ChaincodeProposalType, c
 	}
 
 	// Process and send transaction proposal
-	txProposalResponse, err := transactor.SendTransactionProposal(tp, peersToTxnProcessors(targets))
+	txProposalResponse, err := transactor.SendTransactionProposal(reqContext.Background(), tp, peersToTxnProcessors(targets))
 	if err != nil {
 		return errors.WithMessage(err, "sending deploy transaction proposal failed")
 	}
 
+	return rc.commitCCTransaction(reqContext.Background(), channelService, tp.TxnID, opts, func() error {
+		transactionRequest := fab.TransactionRequest{
+			Proposal:          tp,
+			ProposalResponses: txProposalResponse,
+		}
+		if _, err := createAndSendTransaction(transactor, transactionRequest); err != nil {
+			return errors.WithMessage(err, "CreateAndSendTransaction failed")
+		}
+		return nil
+	})
+}
+
+// channelTargets returns the peers of the channel to which a proposal is sent
+func (rc *Client) channelTargets(channelID string, opts Opts) ([]fab.Peer, error) {
+	// per channel discovery service
+	discovery, err := rc.discoveryProvider.NewDiscoveryService(channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel discovery service")
+	}
+
+	//Default targets when targets are not provided in options
+	if len(opts.Targets) == 0 {
+		opts.Targets, err = rc.getDefaultTargets(discovery)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get default targets")
+		}
+	}
+
+	targets, err := rc.calculateTargets(discovery, opts.Targets, opts.TargetFilter)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to determine target peers")
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.New("No targets available")
+	}
+	return targets, nil
+}
+
+// channelTransactor returns the channel service and transactor of the channel
+func (rc *Client) channelTransactor(channelID string) (fab.ChannelService, fab.Transactor, error) {
+	channelService, err := rc.channelProvider.ChannelService(rc.identity, channelID)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "Unable to get channel service")
+	}
+	transactor, err := channelService.Transactor()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "get channel transactor failed")
+	}
+	return channelService, transactor, nil
+}
+
+// commitCCTransaction registers for the commit event of the transaction, sends the transaction
+// with the given function and waits until it is committed or ctx is done
+func (rc *Client) commitCCTransaction(ctx reqContext.Context, channelService fab.ChannelService, txnID fab.TransactionID, opts Opts, send func() error) error {
 	eventHub, err := channelService.EventHub()
 	if err != nil {
 		return errors.WithMessage(err, "Unable to get EventHub")
@@ -498,14 +694,10 @@ func (rc *Client) sendCCProposal(ccProposalType channel.ChaincodeProposalType, c
 	}
 
 	// Register for commit event
-	statusNotifier := txn.RegisterStatus(tp.TxnID, eventHub)
+	statusNotifier := txn.RegisterStatus(txnID, eventHub)
 
-	transactionRequest := fab.TransactionRequest{
-		Proposal:          tp,
-		ProposalResponses: txProposalResponse,
-	}
-	if _, err = createAndSendTransaction(transactor, transactionRequest); err != nil {
-		return errors.WithMessage(err, "CreateAndSendTransaction failed")
+	if err := send(); err != nil {
+		return err
 	}
 
 	timeout := rc.provider.Config().TimeoutOrDefault(config.Execute)
@@ -521,8 +713,9 @@ func (rc *Client) sendCCProposal(ccProposalType channel.ChaincodeProposalType, c
 		return errors.WithMessage(result.Error, "instantiateOrUpgradeCC failed")
 	case <-time.After(timeout):
 		return errors.New("instantiateOrUpgradeCC timeout")
+	case <-ctx.Done():
+		return errors.WithMessage(ctx.Err(), "instantiateOrUpgradeCC abandoned")
 	}
-
 }
 
 func checkRequiredCCProposalParams(channelID string, req InstantiateCCRequest) error {
@@ -556,7 +749,7 @@ func createAndSendTransaction(sender fab.Sender, request fab.TransactionRequest)
 		return nil, errors.WithMessage(err, "CreateTransaction failed")
 	}
 
-	transactionResponse, err := sender.SendTransaction(tx)
+	transactionResponse, err := sender.SendTransaction(reqContext.Background(), tx)
 	if err != nil {
 		return nil, errors.WithMessage(err, "SendTransaction failed")
 
@@ -627,23 +820,9 @@ func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption)
 	var configSignatures []*common.ConfigSignature
 	configSignatures = append(configSignatures, configSignature)
 
-	// Figure out orderer configuration
-	var ordererCfg *config.OrdererConfig
-	if opts.OrdererID != "" {
-		ordererCfg, err = rc.provider.Config().OrdererConfig(opts.OrdererID)
-	} else {
-		// Default is random orderer from configuration
-		ordererCfg, err = rc.provider.Config().RandomOrdererConfig()
-	}
-
-	// Check if retrieving orderer configuration went ok
-	if err != nil || ordererCfg == nil {
-		return errors.Errorf("failed to retrieve orderer config: %s", err)
-	}
-
-	orderer, err := orderer.New(rc.provider.Config(), orderer.FromOrdererConfig(ordererCfg))
+	orderer, err := rc.requestOrderer(opts)
 	if err != nil {
-		return errors.WithMessage(err, "failed to create new orderer from config")
+		return err
 	}
 
 	request := api.CreateChannelRequest{
@@ -661,6 +840,29 @@ func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption)
 	return nil
 }
 
+// requestOrderer returns the orderer selected by the request options or a random orderer from configuration
+func (rc *Client) requestOrderer(opts Opts) (fab.Orderer, error) {
+	var ordererCfg *config.OrdererConfig
+	var err error
+	if opts.OrdererID != "" {
+		ordererCfg, err = rc.provider.Config().OrdererConfig(opts.OrdererID)
+	} else {
+		// Default is random orderer from configuration
+		ordererCfg, err = rc.provider.Config().RandomOrdererConfig()
+	}
+
+	// Check if retrieving orderer configuration went ok
+	if err != nil || ordererCfg == nil {
+		return nil, errors.Errorf("failed to retrieve orderer config: %s", err)
+	}
+
+	orderer, err := orderer.New(rc.provider.Config(), orderer.FromOrdererConfig(ordererCfg), orderer.WithConnector(comm.ContextConnector(rc.provider)))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create new orderer from config")
+	}
+	return orderer, nil
+}
+
 //prepareSaveChannelOpts Reads chmgmt.Opts from chmgmt.Option array
 func (rc *Client) prepareSaveChannelOpts(options ...RequestOption) (Opts, error) {
 	saveChannelOpts := Opts{}
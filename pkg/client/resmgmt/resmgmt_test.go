@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -304,6 +306,92 @@ func TestJoinChannelNoOrdererConfig(t *testing.T) {
 	}
 }
 
+func TestJoinChannelWithResponses(t *testing.T) {
+
+	rc := setupDefaultResMgmtClient(t)
+	resource := newJoinMockResource()
+	resource.joined["http://peer1.com"] = true
+	resource.failing["http://peer3.com"] = true
+	rc.resource = resource
+
+	peer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer2 := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+	peer3 := fcmocks.NewMockPeer("Peer3", "http://peer3.com")
+
+	responses, err := rc.JoinChannelWithResponses("mychannel", WithTargets(peer1, peer2, peer3), WithParallelism(2), WithJoinWait(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("Expecting 3 responses, got %d", len(responses))
+	}
+
+	// Responses are in the order of the targets
+	if responses[0].Target != "http://peer1.com" || !responses[0].AlreadyJoined || responses[0].Err != nil {
+		t.Fatalf("Expecting peer1 to have already joined, got %+v", responses[0])
+	}
+	if responses[1].Target != "http://peer2.com" || responses[1].AlreadyJoined || responses[1].Err != nil {
+		t.Fatalf("Expecting peer2 to join, got %+v", responses[1])
+	}
+	if responses[2].Target != "http://peer3.com" || responses[2].Err == nil {
+		t.Fatalf("Expecting peer3 to fail, got %+v", responses[2])
+	}
+
+	// JoinChannel reports the failed peer
+	err = rc.JoinChannel("mychannel", WithTargets(peer1, peer2, peer3))
+	if err == nil || !strings.Contains(err.Error(), "http://peer3.com") {
+		t.Fatalf("Should have failed to join peer3, got %v", err)
+	}
+	err = rc.JoinChannel("mychannel", WithTargets(peer1, peer2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The wait times out if the peer does not report the channel (the mock reports mychannel only)
+	responses, err = rc.JoinChannelWithResponses("otherchannel", WithTargets(peer2), WithOrdererID("orderer.example.com"), WithJoinWait(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if responses[0].Err == nil || !strings.Contains(responses[0].Err.Error(), "did not report channel") {
+		t.Fatalf("Expecting wait for join to time out, got %v", responses[0].Err)
+	}
+}
+
+func TestJoinChannelOrdererFailover(t *testing.T) {
+
+	ctx := setupTestContext("test", "Org1MSP")
+	multiOrdererConfig, err := config.FromFile("./testdata/multiorderer_test.yaml")()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.SetConfig(multiOrdererConfig)
+	rc := setupResMgmtClient(ctx, nil, t)
+
+	resource := newJoinMockResource()
+	resource.failingOrderers["orderer.example.com:7050"] = true
+	rc.resource = resource
+
+	peer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+
+	// The genesis block is retrieved from the second orderer
+	err = rc.JoinChannel("mychannel", WithTargets(peer1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The selected orderer is not failed over
+	err = rc.JoinChannel("mychannel", WithTargets(peer1), WithOrdererID("orderer.example.com"))
+	if err == nil || !strings.Contains(err.Error(), "genesis block retrieval failed") {
+		t.Fatalf("Should have failed to retrieve genesis block from selected orderer, got %v", err)
+	}
+
+	resource.failingOrderers["orderer2.example.com:7050"] = true
+	err = rc.JoinChannel("mychannel", WithTargets(peer1))
+	if err == nil || !strings.Contains(err.Error(), "genesis block retrieval failed") {
+		t.Fatalf("Should have failed to retrieve genesis block from all orderers, got %v", err)
+	}
+}
+
 func TestIsChaincodeInstalled(t *testing.T) {
 
 	rc := setupDefaultResMgmtClient(t)
@@ -475,6 +563,44 @@ func TestInstallCC(t *testing.T) {
 	}
 }
 
+func TestInstallCCWithResponses(t *testing.T) {
+
+	rc := setupDefaultResMgmtClient(t)
+
+	// The first peer has already installed the chaincode
+	peer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer2 := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+	resource := newJoinMockResource()
+	resource.installed["http://peer1.com"] = "v0"
+	resource.failing["http://peer3.com"] = true
+	rc.resource = resource
+
+	req := InstallCCRequest{Name: "ID", Version: "v0", Path: "path", Package: &api.CCPackage{Type: 1, Code: []byte("code")}}
+	responses, err := rc.InstallCC(req, WithTargets(peer1, peer2), WithParallelism(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("Expecting 2 responses, got %d", len(responses))
+	}
+	if responses[0].Target != "http://peer1.com" || !responses[0].AlreadyInstalled {
+		t.Fatalf("Expecting chaincode to be already installed on peer1, got %+v", responses[0])
+	}
+	if responses[1].Target != "http://peer2.com" || responses[1].AlreadyInstalled || responses[1].Status != 200 {
+		t.Fatalf("Expecting chaincode to be installed on peer2, got %+v", responses[1])
+	}
+
+	// The failed peer is reported in its response and in the error
+	peer3 := fcmocks.NewMockPeer("Peer3", "http://peer3.com")
+	responses, err = rc.InstallCC(req, WithTargets(peer2, peer3))
+	if err == nil || !strings.Contains(err.Error(), "http://peer3.com") {
+		t.Fatalf("Should have failed to install on peer3, got %v", err)
+	}
+	if len(responses) != 2 || responses[0].Err != nil || responses[1].Err == nil {
+		t.Fatalf("Expecting failure for peer3 only, got %+v", responses)
+	}
+}
+
 func TestInstallCCRequiredParameters(t *testing.T) {
 
 	rc := setupDefaultResMgmtClient(t)
@@ -1311,3 +1437,80 @@ func TestSaveChannelWithOpts(t *testing.T) {
 		t.Fatal("Should have failed for invalid orderer ID")
 	}
 }
+
+// joinMockResource records the peers which joined mychannel and the installed versions of chaincode ID
+type joinMockResource struct {
+	*fcmocks.MockResource
+	mutex           sync.Mutex
+	joined          map[string]bool
+	installed       map[string]string
+	failing         map[string]bool
+	failingOrderers map[string]bool
+}
+
+func newJoinMockResource() *joinMockResource {
+	return &joinMockResource{
+		MockResource:    fcmocks.NewMockResource(),
+		joined:          make(map[string]bool),
+		installed:       make(map[string]string),
+		failing:         make(map[string]bool),
+		failingOrderers: make(map[string]bool),
+	}
+}
+
+func (r *joinMockResource) GenesisBlockFromOrderer(channelName string, orderer fab.Orderer) (*common.Block, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.failingOrderers[orderer.URL()] {
+		return nil, errors.Errorf("orderer %s is not available", orderer.URL())
+	}
+	return fcmocks.NewSimpleMockBlock(), nil
+}
+
+func (r *joinMockResource) JoinChannel(request api.JoinChannelRequest) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, target := range request.Targets {
+		url := target.(fab.Peer).URL()
+		if r.failing[url] {
+			return errors.Errorf("join failed for %s", url)
+		}
+		r.joined[url] = true
+	}
+	return nil
+}
+
+func (r *joinMockResource) QueryChannels(target fab.ProposalProcessor) (*pb.ChannelQueryResponse, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	response := &pb.ChannelQueryResponse{}
+	if r.joined[target.(fab.Peer).URL()] {
+		response.Channels = append(response.Channels, &pb.ChannelInfo{ChannelId: "mychannel"})
+	}
+	return response, nil
+}
+
+func (r *joinMockResource) QueryInstalledChaincodes(target fab.ProposalProcessor) (*pb.ChaincodeQueryResponse, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	response := &pb.ChaincodeQueryResponse{}
+	if version, ok := r.installed[target.(fab.Peer).URL()]; ok {
+		response.Chaincodes = append(response.Chaincodes, &pb.ChaincodeInfo{Name: "ID", Version: version, Path: "path"})
+	}
+	return response, nil
+}
+
+func (r *joinMockResource) InstallChaincode(req api.InstallChaincodeRequest) ([]*fab.TransactionProposalResponse, fab.TransactionID, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var responses []*fab.TransactionProposalResponse
+	for _, target := range req.Targets {
+		url := target.(fab.Peer).URL()
+		if r.failing[url] {
+			return responses, "1234", errors.Errorf("install failed for %s", url)
+		}
+		r.installed[url] = req.Version
+		responses = append(responses, &fab.TransactionProposalResponse{Endorser: url, Status: 200})
+	}
+	return responses, "1234", nil
+}
@@ -85,6 +85,7 @@ type ChannelConfig interface {
 type ChannelCfg interface {
 	Name() string
 	Msps() []*mspCfg.MSPConfig
+	OrdererMsps() []string
 	AnchorPeers() []*OrgAnchorPeer
 	Orderers() []string
 	Versions() *Versions
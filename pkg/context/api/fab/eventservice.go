@@ -104,5 +104,5 @@ type EventClient interface {
 	// RegisterConnectionEvent registers a connection event. The returned
 	// ConnectionEvent channel is called whenever the client clients to
 	// or disconnects from the event server
-	RegisterConnectionEvent() (Registration, chan ConnectionEvent, error)
+	RegisterConnectionEvent() (Registration, chan *ConnectionEvent, error)
 }
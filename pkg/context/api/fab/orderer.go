@@ -16,7 +16,7 @@ import (
 // HFC sends a block of transactions of endorsed proposals requiring ordering.
 type Orderer interface {
 	URL() string
-	SendBroadcast(envelope *SignedEnvelope) (*common.Status, error)
+	SendBroadcast(ctx context.Context, envelope *SignedEnvelope) (*common.Status, error)
 	SendDeliver(envelope *SignedEnvelope) (chan *common.Block, chan error, context.CancelFunc)
 }
 
@@ -7,18 +7,22 @@ SPDX-License-Identifier: Apache-2.0
 package fab
 
 import (
+	reqContext "context"
+
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
 // ProposalProcessor simulates transaction proposal, so that a client can submit the result for ordering.
 type ProposalProcessor interface {
-	ProcessTransactionProposal(ProcessProposalRequest) (*TransactionProposalResponse, error)
+	ProcessTransactionProposal(reqContext.Context, ProcessProposalRequest) (*TransactionProposalResponse, error)
 }
 
 // ProposalSender provides the ability for a transaction proposal to be created and sent.
 type ProposalSender interface {
 	CreateTransactionHeader() (TransactionHeader, error)
-	SendTransactionProposal(*TransactionProposal, []ProposalProcessor) ([]*TransactionProposalResponse, error)
+	SendTransactionProposal(reqContext.Context, *TransactionProposal, []ProposalProcessor) ([]*TransactionProposalResponse, error)
+	SendSignedTransactionProposal(reqContext.Context, *pb.SignedProposal, []ProposalProcessor) ([]*TransactionProposalResponse, error)
+	SignTransactionProposal(*TransactionProposal) (*pb.SignedProposal, error)
 }
 
 // TransactionID provides the identifier of a Fabric transaction proposal.
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package fab
 
 import (
+	reqContext "context"
+
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -21,7 +23,8 @@ type TransactionRequest struct {
 // TODO: CreateTransaction should be refactored as it is actually a factory method.
 type Sender interface {
 	CreateTransaction(request TransactionRequest) (*Transaction, error)
-	SendTransaction(tx *Transaction) (*TransactionResponse, error)
+	SendTransaction(reqCtx reqContext.Context, tx *Transaction) (*TransactionResponse, error)
+	SendSignedTransaction(reqCtx reqContext.Context, envelope *SignedEnvelope) (*TransactionResponse, error)
 }
 
 // The Transaction object created from an endorsed proposal.
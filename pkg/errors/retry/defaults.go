@@ -35,6 +35,14 @@ var DefaultOpts = Opts{
 	RetryableCodes: DefaultRetryableCodes,
 }
 
+// DefaultCommitRetryableCodes are the transaction validation codes for which a transaction
+// is retried by default when commit retries are enabled. A transaction rejected with these
+// codes may succeed when it is simulated again against the latest state.
+var DefaultCommitRetryableCodes = []pb.TxValidationCode{
+	pb.TxValidationCode_MVCC_READ_CONFLICT,
+	pb.TxValidationCode_PHANTOM_READ_CONFLICT,
+}
+
 // DefaultRetryableCodes these are the error codes, grouped by source of error,
 // that are considered to be transient error conditions by default
 var DefaultRetryableCodes = map[status.Group][]status.Code{
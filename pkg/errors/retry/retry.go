@@ -8,9 +8,11 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	reqContext "context"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
 // Opts defines the retry parameters
@@ -29,6 +31,33 @@ type Opts struct {
 	// RetryableCodes defines the status codes, mapped by group, returned by fabric-sdk-go
 	// that warrant a retry. This will default to retry.DefaultRetryableCodes.
 	RetryableCodes map[status.Group][]status.Code
+	// Commit defines the retries made when a transaction is rejected at commit. These
+	// retries are separate from, and in addition to, the retries defined above; see CommitOpts.
+	Commit CommitOpts
+}
+
+// CommitOpts defines the parameters for retrying a transaction that was rejected at commit.
+// Each attempt runs the whole endorse-order-commit cycle again with a new transaction ID.
+//
+// Rejected transactions are also retried, in the same way, if the EventServerStatus group of
+// RetryableCodes contains their validation code, as ChannelClientRetryableCodes does for
+// MVCC_READ_CONFLICT and PHANTOM_READ_CONFLICT. Those retries share the attempts and backoff of
+// every other transient error. CommitOpts gives rejected transactions attempts and a backoff of
+// their own, and is applied first: once its attempts are exhausted, RetryableCodes still apply.
+// To retry rejected transactions only as defined here, leave their codes out of RetryableCodes.
+type CommitOpts struct {
+	// Attempts the number retry attempts
+	Attempts int
+	// InitialBackoff the backoff interval for the first retry attempt
+	InitialBackoff time.Duration
+	// MaxBackoff the maximum backoff interval for any retry attempt
+	MaxBackoff time.Duration
+	// BackoffFactor the factor by which the InitialBackoff is exponentially
+	// incremented for consecutive retry attempts.
+	BackoffFactor float64
+	// ValidationCodes defines the transaction validation codes that warrant a retry.
+	// This will default to retry.DefaultCommitRetryableCodes.
+	ValidationCodes []pb.TxValidationCode
 }
 
 // Handler retry handler interface decides whether a retry is required for the given
@@ -37,6 +66,13 @@ type Handler interface {
 	Required(err error) bool
 }
 
+// ContextHandler is a retry Handler whose backoff ends early, without a retry, once the
+// given context is done
+type ContextHandler interface {
+	Handler
+	RequiredWithContext(ctx reqContext.Context, err error) bool
+}
+
 // impl retry Handler implementation
 type impl struct {
 	opts    Opts
@@ -51,6 +87,28 @@ func New(opts Opts) Handler {
 	return &impl{opts: opts}
 }
 
+// NewCommit returns a retry Handler which requires a retry for errors carrying
+// one of the transaction validation codes in the given opts
+func NewCommit(opts CommitOpts) Handler {
+	validationCodes := opts.ValidationCodes
+	if len(validationCodes) == 0 {
+		validationCodes = DefaultCommitRetryableCodes
+	}
+
+	codes := make([]status.Code, len(validationCodes))
+	for i, c := range validationCodes {
+		codes[i] = status.Code(c)
+	}
+
+	return &impl{opts: Opts{
+		Attempts:       opts.Attempts,
+		InitialBackoff: opts.InitialBackoff,
+		MaxBackoff:     opts.MaxBackoff,
+		BackoffFactor:  opts.BackoffFactor,
+		RetryableCodes: map[status.Group][]status.Code{status.EventServerStatus: codes},
+	}}
+}
+
 // WithDefaults new retry Handler with default opts
 func WithDefaults() Handler {
 	return &impl{opts: DefaultOpts}
@@ -66,18 +124,31 @@ func WithAttempts(attempts int) Handler {
 // Required determines if retry is required for the given error
 // Note: backoffs are implemented behind this interface
 func (i *impl) Required(err error) bool {
+	return i.RequiredWithContext(reqContext.Background(), err)
+}
+
+// RequiredWithContext determines if retry is required for the given error. No retry is
+// required if the context is done before the backoff has elapsed.
+func (i *impl) RequiredWithContext(ctx reqContext.Context, err error) bool {
 	if i.retries == i.opts.Attempts {
 		return false
 	}
 
 	s, ok := status.FromError(err)
-	if ok && i.isRetryable(s.Group, s.Code) {
-		time.Sleep(i.backoffPeriod())
+	if !ok || !i.isRetryable(s.Group, s.Code) {
+		return false
+	}
+
+	timer := time.NewTimer(i.backoffPeriod())
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
 		i.retries++
 		return true
+	case <-ctx.Done():
+		return false
 	}
-
-	return false
 }
 
 // backoffPeriod calculates the backoff duration based on the provided opts
@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	reqContext "context"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -63,3 +65,44 @@ func TestBackoffPeriod(t *testing.T) {
 	i.retries = 3
 	assert.Equal(t, testMaxBackoff, i.backoffPeriod(), "Expected max backoff")
 }
+
+func TestCommitRetryRequired(t *testing.T) {
+	mvccErr := status.New(status.EventServerStatus, int32(pb.TxValidationCode_MVCC_READ_CONFLICT), "", nil)
+	phantomErr := status.New(status.EventServerStatus, int32(pb.TxValidationCode_PHANTOM_READ_CONFLICT), "", nil)
+	policyErr := status.New(status.EventServerStatus, int32(pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE), "", nil)
+	mismatchErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+
+	r := NewCommit(CommitOpts{
+		Attempts:       2,
+		BackoffFactor:  2,
+		InitialBackoff: 1 * time.Millisecond,
+		MaxBackoff:     1 * time.Second,
+	})
+	assert.False(t, r.Required(policyErr), "Expected retry to not be required on validation code that is not configured")
+	assert.False(t, r.Required(mismatchErr), "Expected retry to not be required on non-commit error")
+	assert.True(t, r.Required(mvccErr), "Expected retry to be required on MVCC read conflict")
+	assert.True(t, r.Required(phantomErr), "Expected retry to be required on phantom read conflict")
+	assert.False(t, r.Required(mvccErr), "Expected retry to not be required after exhausting attempts")
+
+	r = NewCommit(CommitOpts{Attempts: 1, ValidationCodes: []pb.TxValidationCode{pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE}})
+	assert.False(t, r.Required(mvccErr), "Expected retry to not be required on validation code that is not configured")
+	assert.True(t, r.Required(policyErr), "Expected retry to be required on configured validation code")
+}
+
+func TestRetryRequiredWithContext(t *testing.T) {
+	transientErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+
+	r := New(Opts{
+		Attempts:       1,
+		BackoffFactor:  1,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     time.Minute,
+	}).(ContextHandler)
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	assert.False(t, r.RequiredWithContext(ctx, transientErr), "Expected retry to not be required once the context is done")
+	assert.True(t, time.Since(start) < 5*time.Second, "Expected the backoff to end with the context")
+}
@@ -41,6 +41,13 @@ const (
 
 	// MultipleErrors multiple errors occurred
 	MultipleErrors Code = 7
+
+	// Canceled the operation was canceled by the caller
+	Canceled Code = 8
+
+	// EndorsementPolicyNotSatisfied is returned when the endorsements received by the SDK
+	// do not satisfy the endorsement policy of the chaincode
+	EndorsementPolicyNotSatisfied Code = 9
 )
 
 // CodeName maps the codes in this packages to human-readable strings
@@ -53,6 +60,8 @@ var CodeName = map[int32]string{
 	5: "TIMEOUT",
 	6: "NO_PEERS_FOUND",
 	7: "MULTIPLE_ERRORS",
+	8: "CANCELED",
+	9: "ENDORSEMENT_POLICY_NOT_SATISFIED",
 }
 
 // ToInt32 cast to int32
@@ -10,6 +10,7 @@ SPDX-License-Identifier: Apache-2.0
 package status
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -149,6 +150,15 @@ func NewFromProposalResponse(res *pb.ProposalResponse, endorser string) *Status
 	return New(EndorserServerStatus, res.Response.Status, res.Response.Message, details)
 }
 
+// NewFromContextError creates a status from the error of a done request context. The status
+// code is Timeout if the deadline of the context was exceeded and Canceled otherwise.
+func NewFromContextError(group Group, err error, details []interface{}) *Status {
+	if err == context.DeadlineExceeded {
+		return New(group, Timeout.ToInt32(), "request timed out", details)
+	}
+	return New(group, Canceled.ToInt32(), "request canceled", details)
+}
+
 // NewFromGRPCStatus new Status from gRPC status response
 func NewFromGRPCStatus(s *grpcstatus.Status) *Status {
 	if s == nil {
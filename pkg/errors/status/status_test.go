@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package status
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -46,6 +47,14 @@ func TestStatusConstructors(t *testing.T) {
 	assert.Equal(t, EndorserServerStatus, s.Group)
 	assert.Equal(t, "test", s.Message, "Expected test message")
 	assert.Equal(t, "localhost", s.Details[0].(string))
+
+	s = NewFromContextError(OrdererClientStatus, context.DeadlineExceeded, []interface{}{"localhost"})
+	assert.EqualValues(t, Timeout, ToSDKStatusCode(s.Code))
+	assert.Equal(t, OrdererClientStatus, s.Group)
+	assert.Equal(t, "localhost", s.Details[0].(string))
+	s = NewFromContextError(ClientStatus, context.Canceled, nil)
+	assert.EqualValues(t, Canceled, ToSDKStatusCode(s.Code))
+	assert.Equal(t, ClientStatus, s.Group)
 }
 
 func TestFromError(t *testing.T) {
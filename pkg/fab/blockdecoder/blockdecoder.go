@@ -0,0 +1,245 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package blockdecoder decodes blocks into a typed model of their transactions. Decoded blocks
+// and transactions may be exported to JSON in the layout of configtxlator.
+package blockdecoder
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/rwset"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Block is a decoded block
+type Block struct {
+	Number       uint64
+	PreviousHash []byte
+	DataHash     []byte
+	// Number of the block containing the last config transaction
+	LastConfig   uint64
+	Transactions []*Transaction
+
+	block *cb.Block
+}
+
+// Transaction is a decoded transaction envelope
+type Transaction struct {
+	Type           cb.HeaderType
+	TxID           string
+	ChannelID      string
+	Timestamp      time.Time
+	Creator        *Identity
+	ValidationCode pb.TxValidationCode
+	// Validated is false if the block has no transaction filter, e.g. a block delivered by an
+	// orderer, in which case the validation code of the transaction is not known
+	Validated bool
+	// Chaincode actions of an endorser transaction
+	Actions []*Action
+	// Configuration of a config transaction
+	Config *cb.ConfigEnvelope
+
+	envelope *cb.Envelope
+}
+
+// Identity is a decoded serialized identity
+type Identity struct {
+	MSPID string
+	// PEM encoded certificate of the identity
+	Cert []byte
+}
+
+// Endorsement is the endorsement of a chaincode action by a peer
+type Endorsement struct {
+	Endorser  *Identity
+	Signature []byte
+}
+
+// Action is a decoded chaincode action of an endorser transaction, which consists of the
+// chaincode invocation and its results
+type Action struct {
+	*rwset.ChaincodeAction
+	// Input arguments of the invocation, starting with the function name
+	Args         [][]byte
+	Endorsements []*Endorsement
+}
+
+// Decode decodes the block and all of its transactions
+func Decode(block *cb.Block) (*Block, error) {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return nil, errors.New("block header and data are required")
+	}
+
+	decoded := &Block{
+		Number:       block.Header.Number,
+		PreviousHash: block.Header.PreviousHash,
+		DataHash:     block.Header.DataHash,
+		block:        block,
+	}
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil {
+		metadata := block.Metadata.Metadata
+		if len(metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+			txFilter = ledgerutil.TxValidationFlags(metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		}
+		if len(metadata) > int(cb.BlockMetadataIndex_LAST_CONFIG) && len(metadata[cb.BlockMetadataIndex_LAST_CONFIG]) > 0 {
+			lastConfig, err := lastConfigIndex(metadata[cb.BlockMetadataIndex_LAST_CONFIG])
+			if err != nil {
+				return nil, err
+			}
+			decoded.LastConfig = lastConfig
+		}
+	}
+
+	for i, data := range block.Data.Data {
+		envelope, err := utils.GetEnvelopeFromBlock(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error extracting envelope %d from block", i)
+		}
+		tx, err := DecodeEnvelope(envelope)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error decoding transaction %d", i))
+		}
+		if i < len(txFilter) {
+			tx.ValidationCode = txFilter.Flag(i)
+			tx.Validated = true
+		}
+		decoded.Transactions = append(decoded.Transactions, tx)
+	}
+
+	return decoded, nil
+}
+
+// DecodeEnvelope decodes a transaction envelope. The validation code of the transaction is only
+// known once the transaction is committed in a block, so the transaction is not validated.
+func DecodeEnvelope(envelope *cb.Envelope) (*Transaction, error) {
+	payload, err := utils.ExtractPayload(envelope)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting payload from envelope")
+	}
+	if payload.Header == nil {
+		return nil, errors.New("payload header is nil")
+	}
+
+	channelHeader, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting channel header from payload")
+	}
+	signatureHeader, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting signature header from payload")
+	}
+	creator, err := decodeIdentity(signatureHeader.Creator)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error decoding creator")
+	}
+
+	tx := &Transaction{
+		Type:      cb.HeaderType(channelHeader.Type),
+		TxID:      channelHeader.TxId,
+		ChannelID: channelHeader.ChannelId,
+		Creator:   creator,
+		envelope:  envelope,
+	}
+	if channelHeader.Timestamp != nil {
+		if tx.Timestamp, err = ptypes.Timestamp(channelHeader.Timestamp); err != nil {
+			return nil, errors.Wrap(err, "invalid timestamp")
+		}
+	}
+
+	switch tx.Type {
+	case cb.HeaderType_ENDORSER_TRANSACTION:
+		if tx.Actions, err = decodeActions(payload.Data); err != nil {
+			return nil, err
+		}
+	case cb.HeaderType_CONFIG:
+		tx.Config = &cb.ConfigEnvelope{}
+		if err := proto.Unmarshal(payload.Data, tx.Config); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling config envelope")
+		}
+	}
+	return tx, nil
+}
+
+func decodeActions(data []byte) ([]*Action, error) {
+	tx, err := utils.GetTransaction(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling transaction")
+	}
+
+	var actions []*Action
+	for _, txAction := range tx.Actions {
+		ccActionPayload, err := utils.GetChaincodeActionPayload(txAction.Payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling chaincode action payload")
+		}
+		if ccActionPayload.Action == nil {
+			return nil, errors.New("chaincode endorsed action is nil")
+		}
+
+		ccAction, err := rwset.FromProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+		if err != nil {
+			return nil, err
+		}
+		action := &Action{ChaincodeAction: ccAction}
+
+		if action.Args, err = decodeArgs(ccActionPayload.ChaincodeProposalPayload); err != nil {
+			return nil, err
+		}
+		for _, endorsement := range ccActionPayload.Action.Endorsements {
+			endorser, err := decodeIdentity(endorsement.Endorser)
+			if err != nil {
+				return nil, errors.WithMessage(err, "error decoding endorser")
+			}
+			action.Endorsements = append(action.Endorsements, &Endorsement{Endorser: endorser, Signature: endorsement.Signature})
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+func decodeArgs(proposalPayload []byte) ([][]byte, error) {
+	ccProposalPayload, err := utils.GetChaincodeProposalPayload(proposalPayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode proposal payload")
+	}
+	invocationSpec := &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(ccProposalPayload.Input, invocationSpec); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode invocation spec")
+	}
+	return invocationSpec.GetChaincodeSpec().GetInput().GetArgs(), nil
+}
+
+func decodeIdentity(serializedIdentity []byte) (*Identity, error) {
+	identity := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedIdentity, identity); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling serialized identity")
+	}
+	return &Identity{MSPID: identity.Mspid, Cert: identity.IdBytes}, nil
+}
+
+func lastConfigIndex(metadataBytes []byte) (uint64, error) {
+	metadata := &cb.Metadata{}
+	if err := proto.Unmarshal(metadataBytes, metadata); err != nil {
+		return 0, errors.Wrap(err, "error unmarshalling last config metadata")
+	}
+	lastConfig := &cb.LastConfig{}
+	if err := proto.Unmarshal(metadata.Value, lastConfig); err != nil {
+		return 0, errors.Wrap(err, "error unmarshalling last config")
+	}
+	return lastConfig.Index, nil
+}
@@ -0,0 +1,443 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockdecoder
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+const (
+	testChannelID = "mychannel"
+	testCCID      = "examplecc"
+)
+
+var testTimestamp = time.Date(2018, 3, 1, 10, 30, 0, 0, time.UTC)
+
+func TestDecode(t *testing.T) {
+	block, err := Decode(newTestBlock())
+	if err != nil {
+		t.Fatalf("Failed to decode block: %s", err)
+	}
+	assert.Equal(t, uint64(10), block.Number)
+	assert.Equal(t, []byte("previoushash"), block.PreviousHash)
+	assert.Equal(t, uint64(7), block.LastConfig)
+	if !assert.Len(t, block.Transactions, 2) {
+		t.FailNow()
+	}
+
+	config := block.Transactions[0]
+	assert.Equal(t, cb.HeaderType_CONFIG, config.Type)
+	assert.NotNil(t, config.Config)
+	assert.Empty(t, config.Actions)
+
+	tx := block.Transactions[1]
+	assert.Equal(t, cb.HeaderType_ENDORSER_TRANSACTION, tx.Type)
+	assert.Equal(t, "txid1", tx.TxID)
+	assert.Equal(t, testChannelID, tx.ChannelID)
+	assert.True(t, testTimestamp.Equal(tx.Timestamp))
+	assert.Equal(t, "Org1MSP", tx.Creator.MSPID)
+	assert.Equal(t, []byte("creatorcert"), tx.Creator.Cert)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, tx.ValidationCode)
+	assert.True(t, tx.Validated)
+
+	if !assert.Len(t, tx.Actions, 1) {
+		t.FailNow()
+	}
+	action := tx.Actions[0]
+	assert.Equal(t, testCCID, action.ChaincodeID.Name)
+	assert.Equal(t, "v1", action.ChaincodeID.Version)
+	assert.Equal(t, [][]byte{[]byte("move"), []byte("a"), []byte("b")}, action.Args)
+	assert.Equal(t, int32(200), action.Response.Status)
+	assert.Equal(t, "event1", action.Event.EventName)
+	assert.Equal(t, "b", action.Namespace(testCCID).Writes[0].Key)
+	if !assert.Len(t, action.Endorsements, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "Org2MSP", action.Endorsements[1].Endorser.MSPID)
+	assert.Equal(t, []byte("signature2"), action.Endorsements[1].Signature)
+}
+
+func TestDecodeWithoutTxFilter(t *testing.T) {
+	// blocks delivered by an orderer are not validated yet
+	block := newTestBlock()
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = nil
+
+	decoded, err := Decode(block)
+	if err != nil {
+		t.Fatalf("Failed to decode block: %s", err)
+	}
+	for _, tx := range decoded.Transactions {
+		assert.False(t, tx.Validated)
+	}
+
+	_, err = json.Marshal(decoded)
+	assert.Nil(t, err, "expected export of block without transaction filter")
+}
+
+func TestMarshalJSON(t *testing.T) {
+	block, err := Decode(newTestBlock())
+	if err != nil {
+		t.Fatalf("Failed to decode block: %s", err)
+	}
+
+	data, err := json.Marshal(block)
+	if err != nil {
+		t.Fatalf("Failed to export block: %s", err)
+	}
+	again, err := json.Marshal(block)
+	if err != nil {
+		t.Fatalf("Failed to export block: %s", err)
+	}
+	assert.Equal(t, string(data), string(again), "expected stable JSON")
+
+	type identity struct {
+		MSPID   string `json:"mspid"`
+		IDBytes []byte `json:"id_bytes"`
+	}
+	type envelope struct {
+		Payload struct {
+			Header struct {
+				ChannelHeader struct {
+					Type      int    `json:"type"`
+					TxID      string `json:"tx_id"`
+					ChannelID string `json:"channel_id"`
+					Timestamp string `json:"timestamp"`
+					Extension struct {
+						ChaincodeID struct {
+							Name string `json:"name"`
+						} `json:"chaincode_id"`
+					} `json:"extension"`
+				} `json:"channel_header"`
+				SignatureHeader struct {
+					Creator identity `json:"creator"`
+				} `json:"signature_header"`
+			} `json:"header"`
+			Data struct {
+				Config struct {
+					Sequence string `json:"sequence"`
+				} `json:"config"`
+				Actions []struct {
+					Header struct {
+						Creator identity `json:"creator"`
+					} `json:"header"`
+					Payload struct {
+						ChaincodeProposalPayload struct {
+							Input struct {
+								ChaincodeSpec struct {
+									Input struct {
+										Args [][]byte `json:"args"`
+									} `json:"input"`
+								} `json:"chaincode_spec"`
+							} `json:"input"`
+						} `json:"chaincode_proposal_payload"`
+						Action struct {
+							ProposalResponsePayload struct {
+								Extension struct {
+									ChaincodeID struct {
+										Name    string `json:"name"`
+										Version string `json:"version"`
+									} `json:"chaincode_id"`
+									Events struct {
+										EventName string `json:"event_name"`
+									} `json:"events"`
+									Results struct {
+										DataModel string `json:"data_model"`
+										NsRWSet   []struct {
+											Namespace string `json:"namespace"`
+											RWSet     struct {
+												Reads []struct {
+													Key     string `json:"key"`
+													Version struct {
+														BlockNum string `json:"block_num"`
+													} `json:"version"`
+												} `json:"reads"`
+												Writes []struct {
+													Key      string `json:"key"`
+													IsDelete bool   `json:"is_delete"`
+												} `json:"writes"`
+											} `json:"rwset"`
+										} `json:"ns_rwset"`
+									} `json:"results"`
+								} `json:"extension"`
+							} `json:"proposal_response_payload"`
+							Endorsements []struct {
+								Endorser  identity `json:"endorser"`
+								Signature []byte   `json:"signature"`
+							} `json:"endorsements"`
+						} `json:"action"`
+					} `json:"payload"`
+				} `json:"actions"`
+			} `json:"data"`
+		} `json:"payload"`
+	}
+	var exported struct {
+		Header struct {
+			Number       string `json:"number"`
+			PreviousHash []byte `json:"previous_hash"`
+		} `json:"header"`
+		Data struct {
+			Data []envelope `json:"data"`
+		} `json:"data"`
+		Metadata struct {
+			Metadata []json.RawMessage `json:"metadata"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to unmarshal exported block: %s", err)
+	}
+
+	assert.Equal(t, "10", exported.Header.Number)
+	assert.Equal(t, []byte("previoushash"), exported.Header.PreviousHash)
+	if !assert.Len(t, exported.Metadata.Metadata, len(cb.BlockMetadataIndex_name)) {
+		t.FailNow()
+	}
+	var lastConfig struct {
+		Value struct {
+			Index string `json:"index"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(exported.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG], &lastConfig); err != nil {
+		t.Fatalf("Failed to unmarshal last config: %s", err)
+	}
+	assert.Equal(t, "7", lastConfig.Value.Index)
+
+	if !assert.Len(t, exported.Data.Data, 2) {
+		t.FailNow()
+	}
+	config := exported.Data.Data[0].Payload
+	assert.Equal(t, int(cb.HeaderType_CONFIG), config.Header.ChannelHeader.Type)
+	assert.Equal(t, "3", config.Data.Config.Sequence)
+
+	tx := exported.Data.Data[1].Payload
+	assert.Equal(t, int(cb.HeaderType_ENDORSER_TRANSACTION), tx.Header.ChannelHeader.Type)
+	assert.Equal(t, "txid1", tx.Header.ChannelHeader.TxID)
+	assert.Equal(t, testChannelID, tx.Header.ChannelHeader.ChannelID)
+	assert.Equal(t, "2018-03-01T10:30:00Z", tx.Header.ChannelHeader.Timestamp)
+	assert.Equal(t, testCCID, tx.Header.ChannelHeader.Extension.ChaincodeID.Name)
+	assert.Equal(t, "Org1MSP", tx.Header.SignatureHeader.Creator.MSPID)
+	assert.Equal(t, []byte("creatorcert"), tx.Header.SignatureHeader.Creator.IDBytes)
+
+	if !assert.Len(t, tx.Data.Actions, 1) {
+		t.FailNow()
+	}
+	action := tx.Data.Actions[0]
+	assert.Equal(t, "Org1MSP", action.Header.Creator.MSPID)
+	assert.Equal(t, [][]byte{[]byte("move"), []byte("a"), []byte("b")}, action.Payload.ChaincodeProposalPayload.Input.ChaincodeSpec.Input.Args)
+	if !assert.Len(t, action.Payload.Action.Endorsements, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "Org2MSP", action.Payload.Action.Endorsements[1].Endorser.MSPID)
+	assert.Equal(t, []byte("signature2"), action.Payload.Action.Endorsements[1].Signature)
+
+	ccAction := action.Payload.Action.ProposalResponsePayload.Extension
+	assert.Equal(t, testCCID, ccAction.ChaincodeID.Name)
+	assert.Equal(t, "v1", ccAction.ChaincodeID.Version)
+	assert.Equal(t, "event1", ccAction.Events.EventName)
+	assert.Equal(t, "KV", ccAction.Results.DataModel)
+	if !assert.Len(t, ccAction.Results.NsRWSet, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "lscc", ccAction.Results.NsRWSet[0].Namespace)
+	ns := ccAction.Results.NsRWSet[1]
+	assert.Equal(t, testCCID, ns.Namespace)
+	assert.Equal(t, "5", ns.RWSet.Reads[0].Version.BlockNum)
+	if !assert.Len(t, ns.RWSet.Writes, 2) {
+		t.FailNow()
+	}
+	assert.False(t, ns.RWSet.Writes[0].IsDelete)
+	assert.Equal(t, "c", ns.RWSet.Writes[1].Key)
+	assert.True(t, ns.RWSet.Writes[1].IsDelete)
+
+	// a transaction is exported as its envelope
+	txData, err := json.Marshal(block.Transactions[1])
+	if err != nil {
+		t.Fatalf("Failed to export transaction: %s", err)
+	}
+	var txEnvelope envelope
+	if err := json.Unmarshal(txData, &txEnvelope); err != nil {
+		t.Fatalf("Failed to unmarshal exported transaction: %s", err)
+	}
+	assert.Equal(t, exported.Data.Data[1], txEnvelope)
+}
+
+func TestMarshalJSONConfig(t *testing.T) {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP"},
+			OrdererAddress: "localhost:7050",
+			RootCA:         "rootcert",
+		},
+		Index:           0,
+		LastConfigIndex: 0,
+	}
+	block := builder.Build()
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{})
+	block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{})
+
+	decoded, err := Decode(block)
+	if err != nil {
+		t.Fatalf("Failed to decode block: %s", err)
+	}
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Failed to export block: %s", err)
+	}
+
+	type policy struct {
+		Policy struct {
+			Type  int `json:"type"`
+			Value struct {
+				Version int `json:"version"`
+			} `json:"value"`
+		} `json:"policy"`
+		ModPolicy string `json:"mod_policy"`
+	}
+	type orgGroup struct {
+		Values struct {
+			MSP struct {
+				Value struct {
+					Config struct {
+						Name      string   `json:"name"`
+						RootCerts [][]byte `json:"root_certs"`
+					} `json:"config"`
+				} `json:"value"`
+			} `json:"MSP"`
+		} `json:"values"`
+		Policies struct {
+			Admins policy `json:"Admins"`
+		} `json:"policies"`
+	}
+	var exported struct {
+		Data struct {
+			Data []struct {
+				Payload struct {
+					Data struct {
+						Config struct {
+							ChannelGroup struct {
+								Values struct {
+									OrdererAddresses struct {
+										Value struct {
+											Addresses []string `json:"addresses"`
+										} `json:"value"`
+										ModPolicy string `json:"mod_policy"`
+									} `json:"OrdererAddresses"`
+								} `json:"values"`
+								Groups struct {
+									Orderer struct {
+										Values struct {
+											BatchSize struct {
+												Value struct {
+													MaxMessageCount int `json:"max_message_count"`
+												} `json:"value"`
+											} `json:"BatchSize"`
+											ConsensusType struct {
+												Value struct {
+													Type string `json:"type"`
+												} `json:"value"`
+											} `json:"ConsensusType"`
+											AnchorPeers struct {
+												Value struct {
+													AnchorPeers []struct {
+														Host string `json:"host"`
+													} `json:"anchor_peers"`
+												} `json:"value"`
+											} `json:"AnchorPeers"`
+										} `json:"values"`
+										Groups struct {
+											OrdererMSP orgGroup `json:"OrdererMSP"`
+										} `json:"groups"`
+									} `json:"Orderer"`
+									Application struct {
+										Groups struct {
+											Org1MSP orgGroup `json:"Org1MSP"`
+										} `json:"groups"`
+									} `json:"Application"`
+								} `json:"groups"`
+							} `json:"channel_group"`
+						} `json:"config"`
+					} `json:"data"`
+				} `json:"payload"`
+			} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to unmarshal exported block: %s", err)
+	}
+	if !assert.Len(t, exported.Data.Data, 1) {
+		t.FailNow()
+	}
+
+	channelGroup := exported.Data.Data[0].Payload.Data.Config.ChannelGroup
+	assert.Equal(t, []string{"localhost:7050"}, channelGroup.Values.OrdererAddresses.Value.Addresses)
+	assert.Equal(t, "Admins", channelGroup.Values.OrdererAddresses.ModPolicy)
+	orderer := channelGroup.Groups.Orderer
+	assert.Equal(t, 10, orderer.Values.BatchSize.Value.MaxMessageCount)
+	assert.Equal(t, "sample-Consensus-Type", orderer.Values.ConsensusType.Value.Type)
+	if assert.Len(t, orderer.Values.AnchorPeers.Value.AnchorPeers, 1) {
+		assert.Equal(t, "sample-host", orderer.Values.AnchorPeers.Value.AnchorPeers[0].Host)
+	}
+	assert.Equal(t, "OrdererMSP", orderer.Groups.OrdererMSP.Values.MSP.Value.Config.Name)
+
+	org := channelGroup.Groups.Application.Groups.Org1MSP
+	assert.Equal(t, "Org1MSP", org.Values.MSP.Value.Config.Name)
+	assert.Equal(t, [][]byte{[]byte("rootcert")}, org.Values.MSP.Value.Config.RootCerts)
+	assert.Equal(t, int(cb.Policy_SIGNATURE), org.Policies.Admins.Policy.Type)
+	assert.Equal(t, "Admins", org.Policies.Admins.ModPolicy)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, err := Decode(nil)
+	assert.NotNil(t, err, "expected error for missing block")
+
+	block := newTestBlock()
+	block.Data.Data = append(block.Data.Data, []byte("invalid"))
+	_, err = Decode(block)
+	assert.NotNil(t, err, "expected error for invalid envelope")
+
+	block = newTestBlock()
+	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = []byte("invalid")
+	_, err = Decode(block)
+	assert.NotNil(t, err, "expected error for invalid last config")
+
+	_, err = DecodeEnvelope(newTxBuilder("txid2").BuildEnvelopeWithData(cb.HeaderType_ENDORSER_TRANSACTION, []byte("invalid")))
+	assert.NotNil(t, err, "expected error for invalid transaction")
+}
+
+func newTestBlock() *cb.Block {
+	configEnvelope := newTxBuilder("").BuildEnvelopeWithData(cb.HeaderType_CONFIG, utils.MarshalOrPanic(&cb.ConfigEnvelope{Config: &cb.Config{Sequence: 3}}))
+	block := mocks.NewMockBlockWithEnvelopes(10, []*cb.Envelope{configEnvelope, newTxBuilder("txid1").BuildEnvelope()},
+		[]pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT})
+	block.Header.PreviousHash = []byte("previoushash")
+	block.Header.DataHash = []byte("datahash")
+	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{Value: utils.MarshalOrPanic(&cb.LastConfig{Index: 7})})
+	return block
+}
+
+func newTxBuilder(txID string) *mocks.MockEndorserTxBuilder {
+	return &mocks.MockEndorserTxBuilder{
+		ChannelID:   testChannelID,
+		TxID:        txID,
+		ChaincodeID: testCCID,
+		Timestamp:   testTimestamp,
+		Creator:     mocks.NewMockSerializedIdentity("Org1MSP", "creatorcert"),
+		Args:        [][]byte{[]byte("move"), []byte("a"), []byte("b")},
+		Endorsements: []*pb.Endorsement{
+			{Endorser: mocks.NewMockSerializedIdentity("Org1MSP", "peer1cert"), Signature: []byte("signature1")},
+			{Endorser: mocks.NewMockSerializedIdentity("Org2MSP", "peer2cert"), Signature: []byte("signature2")},
+		},
+	}
+}
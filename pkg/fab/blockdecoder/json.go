@@ -0,0 +1,455 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockdecoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	lrwset "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// The JSON export has the layout of configtxlator: blocks and envelopes are exported as their
+// protos in the proto3 JSON mapping with the original field names and default values, where the
+// fields that hold serialized protos, such as the payload of an envelope or the read-write set of
+// a chaincode action, hold the decoded protos instead. Bytes are base64 encoded, 64 bit integers
+// are strings and enums are names. Config values are decoded by their key, so the values of keys
+// which are not known remain bytes, as does the transaction filter of the block metadata.
+var marshaler = &jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+
+// configValues creates the protos of the config values by their key
+var configValues = map[string]func() proto.Message{
+	"HashingAlgorithm":          func() proto.Message { return &cb.HashingAlgorithm{} },
+	"BlockDataHashingStructure": func() proto.Message { return &cb.BlockDataHashingStructure{} },
+	"OrdererAddresses":          func() proto.Message { return &cb.OrdererAddresses{} },
+	"Consortium":                func() proto.Message { return &cb.Consortium{} },
+	"Capabilities":              func() proto.Message { return &cb.Capabilities{} },
+	"ChannelCreationPolicy":     func() proto.Message { return &cb.Policy{} },
+	"ConsensusType":             func() proto.Message { return &ab.ConsensusType{} },
+	"BatchSize":                 func() proto.Message { return &ab.BatchSize{} },
+	"BatchTimeout":              func() proto.Message { return &ab.BatchTimeout{} },
+	"KafkaBrokers":              func() proto.Message { return &ab.KafkaBrokers{} },
+	"ChannelRestrictions":       func() proto.Message { return &ab.ChannelRestrictions{} },
+	"MSP":                       func() proto.Message { return &msp.MSPConfig{} },
+	"AnchorPeers":               func() proto.Message { return &pb.AnchorPeers{} },
+}
+
+// tree is the generic JSON representation of a proto, in which serialized protos are replaced
+// by the trees of the decoded protos
+type tree map[string]interface{}
+
+// MarshalJSON exports the block to JSON
+func (b *Block) MarshalJSON() ([]byte, error) {
+	if b.block == nil {
+		return nil, errors.New("only blocks returned by Decode can be exported")
+	}
+	t, err := exportBlock(b.block)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(t)
+}
+
+// MarshalJSON exports the transaction envelope to JSON
+func (tx *Transaction) MarshalJSON() ([]byte, error) {
+	if tx.envelope == nil {
+		return nil, errors.New("only transactions returned by Decode or DecodeEnvelope can be exported")
+	}
+	t, err := toTree(tx.envelope)
+	if err != nil {
+		return nil, err
+	}
+	if err := exportEnvelope(t, tx.envelope); err != nil {
+		return nil, errors.WithMessage(err, "export of transaction "+tx.TxID+" failed")
+	}
+	return json.Marshal(t)
+}
+
+func exportBlock(block *cb.Block) (tree, error) {
+	t, err := toTree(block)
+	if err != nil {
+		return nil, err
+	}
+
+	data := t.child("data")
+	for i, envelopeBytes := range block.Data.Data {
+		envelope := &cb.Envelope{}
+		envelopeTree, err := data.decodeElem("data", i, envelopeBytes, envelope)
+		if err != nil {
+			return nil, err
+		}
+		if err := exportEnvelope(envelopeTree, envelope); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("export of transaction %d failed", i))
+		}
+	}
+
+	if block.Metadata == nil {
+		return t, nil
+	}
+	metadata := t.child("metadata")
+	for i, metadataBytes := range block.Metadata.Metadata {
+		if i == int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+			// the transaction filter holds the validation codes and is not a proto
+			continue
+		}
+		if err := exportMetadata(metadata, i, metadataBytes); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("export of metadata %d failed", i))
+		}
+	}
+	return t, nil
+}
+
+func exportMetadata(t tree, index int, metadataBytes []byte) error {
+	metadata := &cb.Metadata{}
+	metadataTree, err := t.decodeElem("metadata", index, metadataBytes, metadata)
+	if err != nil {
+		return err
+	}
+	for i, signature := range metadata.Signatures {
+		if err := exportSignatureHeader(metadataTree.elem("signatures", i), "signature_header", signature.SignatureHeader); err != nil {
+			return err
+		}
+	}
+	if index == int(cb.BlockMetadataIndex_LAST_CONFIG) {
+		if _, err := metadataTree.decode("value", metadata.Value, &cb.LastConfig{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportEnvelope(t tree, envelope *cb.Envelope) error {
+	payload := &cb.Payload{}
+	payloadTree, err := t.decode("payload", envelope.Payload, payload)
+	if err != nil {
+		return err
+	}
+	if payload.Header == nil {
+		return nil
+	}
+
+	header := payloadTree.child("header")
+	channelHeader := &cb.ChannelHeader{}
+	channelHeaderTree, err := header.decode("channel_header", payload.Header.ChannelHeader, channelHeader)
+	if err != nil {
+		return err
+	}
+	if err := exportSignatureHeader(header, "signature_header", payload.Header.SignatureHeader); err != nil {
+		return err
+	}
+
+	switch cb.HeaderType(channelHeader.Type) {
+	case cb.HeaderType_ENDORSER_TRANSACTION:
+		if _, err := channelHeaderTree.decode("extension", channelHeader.Extension, &pb.ChaincodeHeaderExtension{}); err != nil {
+			return err
+		}
+		tx := &pb.Transaction{}
+		txTree, err := payloadTree.decode("data", payload.Data, tx)
+		if err != nil {
+			return err
+		}
+		return exportTransaction(txTree, tx)
+	case cb.HeaderType_CONFIG:
+		configEnvelope := &cb.ConfigEnvelope{}
+		configEnvelopeTree, err := payloadTree.decode("data", payload.Data, configEnvelope)
+		if err != nil {
+			return err
+		}
+		return exportConfigEnvelope(configEnvelopeTree, configEnvelope)
+	case cb.HeaderType_CONFIG_UPDATE:
+		configUpdateEnvelope := &cb.ConfigUpdateEnvelope{}
+		configUpdateEnvelopeTree, err := payloadTree.decode("data", payload.Data, configUpdateEnvelope)
+		if err != nil {
+			return err
+		}
+		return exportConfigUpdateEnvelope(configUpdateEnvelopeTree, configUpdateEnvelope)
+	}
+	return nil
+}
+
+func exportSignatureHeader(t tree, field string, signatureHeaderBytes []byte) error {
+	signatureHeader := &cb.SignatureHeader{}
+	signatureHeaderTree, err := t.decode(field, signatureHeaderBytes, signatureHeader)
+	if err != nil {
+		return err
+	}
+	_, err = signatureHeaderTree.decode("creator", signatureHeader.Creator, &msp.SerializedIdentity{})
+	return err
+}
+
+func exportTransaction(t tree, tx *pb.Transaction) error {
+	for i, action := range tx.Actions {
+		actionTree := t.elem("actions", i)
+		if err := exportSignatureHeader(actionTree, "header", action.Header); err != nil {
+			return err
+		}
+
+		payload := &pb.ChaincodeActionPayload{}
+		payloadTree, err := actionTree.decode("payload", action.Payload, payload)
+		if err != nil {
+			return err
+		}
+		proposalPayload := &pb.ChaincodeProposalPayload{}
+		proposalPayloadTree, err := payloadTree.decode("chaincode_proposal_payload", payload.ChaincodeProposalPayload, proposalPayload)
+		if err != nil {
+			return err
+		}
+		if _, err := proposalPayloadTree.decode("input", proposalPayload.Input, &pb.ChaincodeInvocationSpec{}); err != nil {
+			return err
+		}
+
+		if payload.Action == nil {
+			continue
+		}
+		if err := exportEndorsedAction(payloadTree.child("action"), payload.Action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportEndorsedAction(t tree, action *pb.ChaincodeEndorsedAction) error {
+	responsePayload := &pb.ProposalResponsePayload{}
+	responsePayloadTree, err := t.decode("proposal_response_payload", action.ProposalResponsePayload, responsePayload)
+	if err != nil {
+		return err
+	}
+	ccAction := &pb.ChaincodeAction{}
+	ccActionTree, err := responsePayloadTree.decode("extension", responsePayload.Extension, ccAction)
+	if err != nil {
+		return err
+	}
+	if _, err := ccActionTree.decode("events", ccAction.Events, &pb.ChaincodeEvent{}); err != nil {
+		return err
+	}
+
+	txRWSet := &lrwset.TxReadWriteSet{}
+	txRWSetTree, err := ccActionTree.decode("results", ccAction.Results, txRWSet)
+	if err != nil {
+		return err
+	}
+	for i, ns := range txRWSet.NsRwset {
+		nsTree := txRWSetTree.elem("ns_rwset", i)
+		if _, err := nsTree.decode("rwset", ns.Rwset, &kvrwset.KVRWSet{}); err != nil {
+			return err
+		}
+		for j, coll := range ns.CollectionHashedRwset {
+			if _, err := nsTree.elem("collection_hashed_rwset", j).decode("hashed_rwset", coll.HashedRwset, &kvrwset.HashedRWSet{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, endorsement := range action.Endorsements {
+		if _, err := t.elem("endorsements", i).decode("endorser", endorsement.Endorser, &msp.SerializedIdentity{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportConfigEnvelope(t tree, configEnvelope *cb.ConfigEnvelope) error {
+	if configEnvelope.Config != nil {
+		if err := exportConfigGroup(t.child("config").child("channel_group"), configEnvelope.Config.ChannelGroup); err != nil {
+			return err
+		}
+	}
+	if configEnvelope.LastUpdate != nil {
+		if err := exportEnvelope(t.child("last_update"), configEnvelope.LastUpdate); err != nil {
+			return errors.WithMessage(err, "export of last update failed")
+		}
+	}
+	return nil
+}
+
+func exportConfigUpdateEnvelope(t tree, configUpdateEnvelope *cb.ConfigUpdateEnvelope) error {
+	configUpdate := &cb.ConfigUpdate{}
+	configUpdateTree, err := t.decode("config_update", configUpdateEnvelope.ConfigUpdate, configUpdate)
+	if err != nil {
+		return err
+	}
+	if err := exportConfigGroup(configUpdateTree.child("read_set"), configUpdate.ReadSet); err != nil {
+		return err
+	}
+	if err := exportConfigGroup(configUpdateTree.child("write_set"), configUpdate.WriteSet); err != nil {
+		return err
+	}
+
+	for i, signature := range configUpdateEnvelope.Signatures {
+		if err := exportSignatureHeader(t.elem("signatures", i), "signature_header", signature.SignatureHeader); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportConfigGroup(t tree, group *cb.ConfigGroup) error {
+	if group == nil {
+		return nil
+	}
+
+	for name, child := range group.Groups {
+		if err := exportConfigGroup(t.child("groups").child(name), child); err != nil {
+			return errors.WithMessage(err, "export of config group "+name+" failed")
+		}
+	}
+
+	for key, value := range group.Values {
+		newValue, ok := configValues[key]
+		if !ok || value == nil {
+			continue
+		}
+		msg := newValue()
+		valueTree, err := t.child("values").child(key).decode("value", value.Value, msg)
+		if err != nil {
+			return errors.WithMessage(err, "export of config value "+key+" failed")
+		}
+		switch v := msg.(type) {
+		case *msp.MSPConfig:
+			err = exportMSPConfig(valueTree, v)
+		case *cb.Policy:
+			err = exportPolicy(valueTree, v)
+		}
+		if err != nil {
+			return errors.WithMessage(err, "export of config value "+key+" failed")
+		}
+	}
+
+	for key, policy := range group.Policies {
+		if policy == nil || policy.Policy == nil {
+			continue
+		}
+		if err := exportPolicy(t.child("policies").child(key).child("policy"), policy.Policy); err != nil {
+			return errors.WithMessage(err, "export of policy "+key+" failed")
+		}
+	}
+	return nil
+}
+
+func exportMSPConfig(t tree, config *msp.MSPConfig) error {
+	// only the configuration of fabric MSPs, which are of type 0, is known
+	if config.Type != 0 {
+		return nil
+	}
+	_, err := t.decode("config", config.Config, &msp.FabricMSPConfig{})
+	return err
+}
+
+func exportPolicy(t tree, policy *cb.Policy) error {
+	switch cb.Policy_PolicyType(policy.Type) {
+	case cb.Policy_SIGNATURE:
+		envelope := &cb.SignaturePolicyEnvelope{}
+		envelopeTree, err := t.decode("value", policy.Value, envelope)
+		if err != nil {
+			return err
+		}
+		for i, principal := range envelope.Identities {
+			if err := exportPrincipal(envelopeTree.elem("identities", i), principal); err != nil {
+				return err
+			}
+		}
+	case cb.Policy_IMPLICIT_META:
+		if _, err := t.decode("value", policy.Value, &cb.ImplicitMetaPolicy{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportPrincipal(t tree, principal *msp.MSPPrincipal) error {
+	var msg proto.Message
+	switch principal.PrincipalClassification {
+	case msp.MSPPrincipal_ROLE:
+		msg = &msp.MSPRole{}
+	case msp.MSPPrincipal_ORGANIZATION_UNIT:
+		msg = &msp.OrganizationUnit{}
+	case msp.MSPPrincipal_IDENTITY:
+		msg = &msp.SerializedIdentity{}
+	default:
+		return nil
+	}
+	_, err := t.decode("principal", principal.Principal, msg)
+	return err
+}
+
+func toTree(msg proto.Message) (tree, error) {
+	data, err := marshaler.MarshalToString(msg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "export of %T failed", msg)
+	}
+
+	t := tree{}
+	decoder := json.NewDecoder(strings.NewReader(data))
+	// keep the numbers as they were exported instead of converting them to floats
+	decoder.UseNumber()
+	if err := decoder.Decode(&t); err != nil {
+		return nil, errors.Wrapf(err, "export of %T failed", msg)
+	}
+	return t, nil
+}
+
+func decodeTree(data []byte, msg proto.Message) (tree, error) {
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshalling %T", msg)
+	}
+	return toTree(msg)
+}
+
+// decode replaces the serialized proto of the field by the tree of the decoded proto
+func (t tree) decode(field string, data []byte, msg proto.Message) (tree, error) {
+	if t == nil {
+		return nil, errors.Errorf("export of %s failed: parent not found", field)
+	}
+	decoded, err := decodeTree(data, msg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "export of "+field+" failed")
+	}
+	t[field] = map[string]interface{}(decoded)
+	return decoded, nil
+}
+
+// decodeElem replaces the serialized proto of an element of the repeated field by the tree of
+// the decoded proto
+func (t tree) decodeElem(field string, index int, data []byte, msg proto.Message) (tree, error) {
+	elems, ok := t[field].([]interface{})
+	if !ok || index >= len(elems) {
+		return nil, errors.Errorf("export of %s failed: element %d not found", field, index)
+	}
+	decoded, err := decodeTree(data, msg)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("export of %s %d failed", field, index))
+	}
+	elems[index] = map[string]interface{}(decoded)
+	return decoded, nil
+}
+
+// child returns the tree of the message field, or nil if the field is not a message
+func (t tree) child(field string) tree {
+	child, _ := t[field].(map[string]interface{})
+	return child
+}
+
+// elem returns the tree of an element of the repeated message field, or nil if there is no
+// such element
+func (t tree) elem(field string, index int) tree {
+	elems, _ := t[field].([]interface{})
+	if index >= len(elems) {
+		return nil
+	}
+	elem, _ := elems[index].(map[string]interface{})
+	return elem
+}
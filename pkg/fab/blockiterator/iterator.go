@@ -0,0 +1,282 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package blockiterator streams a bounded range of blocks from the deliver service of a peer or
+// from an orderer, e.g. to backfill an off-chain database.
+//
+// Blocks are only received as fast as they are read, and the iterator reconnects from the next
+// block of the range if the stream is disconnected.
+package blockiterator
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+const (
+	defaultBufferSize        = 10
+	defaultReconnectAttempts = 3
+	defaultReconnectBackoff  = time.Second
+)
+
+// Block is a block delivered by the iterator, which is either a full or a filtered block
+type Block struct {
+	Number        uint64
+	Block         *common.Block
+	FilteredBlock *pb.FilteredBlock
+}
+
+// Source opens deliver streams of a peer or an orderer
+type Source interface {
+	// Open sends the seek request and returns the stream of the requested blocks. At most
+	// bufferSize blocks are received ahead of the reader of the stream.
+	Open(seekInfo *ab.SeekInfo, bufferSize int) (Stream, error)
+}
+
+// Stream is an open deliver stream
+type Stream interface {
+	// Recv returns the next block, or io.EOF once all requested blocks are delivered
+	Recv() (*Block, error)
+	// Close terminates the stream
+	Close()
+}
+
+// StatusError is returned if the deliver service rejects the seek request, e.g. with NOT_FOUND
+// if the range is not available yet and the iterator does not wait for blocks. The request is
+// not retried.
+type StatusError struct {
+	Status common.Status
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("deliver service returned status %s", e.Status)
+}
+
+// errClosed is returned by Next once the iterator is closed
+var errClosed = errors.New("iterator is closed")
+
+// Iterator streams the blocks of the range [from, to]
+type Iterator struct {
+	source            Source
+	to                uint64
+	next              uint64
+	done              bool
+	closed            chan struct{}
+	closeOnce         sync.Once
+	mutex             sync.Mutex
+	stream            Stream
+	attempts          int
+	bufferSize        int
+	reconnectAttempts int
+	reconnectBackoff  time.Duration
+	behavior          ab.SeekInfo_SeekBehavior
+}
+
+// Option configures the iterator
+type Option func(it *Iterator) error
+
+// WithBufferSize sets the number of blocks which are received ahead of the reader
+func WithBufferSize(size int) Option {
+	return func(it *Iterator) error {
+		if size < 0 {
+			return errors.New("buffer size must not be negative")
+		}
+		it.bufferSize = size
+		return nil
+	}
+}
+
+// WithReconnect sets the number of consecutive attempts to reconnect after the stream is
+// disconnected and the backoff before the first attempt, which grows linearly with the attempts
+func WithReconnect(attempts int, backoff time.Duration) Option {
+	return func(it *Iterator) error {
+		it.reconnectAttempts = attempts
+		it.reconnectBackoff = backoff
+		return nil
+	}
+}
+
+// WithFailIfNotReady fails with a StatusError if blocks of the range have not been created
+// yet. By default the iterator waits until the blocks are created.
+func WithFailIfNotReady() Option {
+	return func(it *Iterator) error {
+		it.behavior = ab.SeekInfo_FAIL_IF_NOT_READY
+		return nil
+	}
+}
+
+// New returns an iterator of the blocks from, ..., to of the source. The stream is opened by
+// the first call of Next.
+func New(source Source, from uint64, to uint64, opts ...Option) (*Iterator, error) {
+	if source == nil {
+		return nil, errors.New("source is required")
+	}
+	if from > to {
+		return nil, errors.Errorf("invalid block range [%d, %d]", from, to)
+	}
+
+	it := &Iterator{
+		source:            source,
+		to:                to,
+		next:              from,
+		closed:            make(chan struct{}),
+		bufferSize:        defaultBufferSize,
+		reconnectAttempts: defaultReconnectAttempts,
+		reconnectBackoff:  defaultReconnectBackoff,
+		behavior:          ab.SeekInfo_BLOCK_UNTIL_READY,
+	}
+	for _, opt := range opts {
+		if err := opt(it); err != nil {
+			return nil, err
+		}
+	}
+	return it, nil
+}
+
+// Next returns the next block of the range, or io.EOF after the last block
+func (it *Iterator) Next() (*Block, error) {
+	if it.isClosed() {
+		return nil, errClosed
+	}
+
+	for !it.done {
+		stream := it.currentStream()
+		if stream == nil {
+			seekInfo := seek.InfoRange(it.next, it.to)
+			seekInfo.Behavior = it.behavior
+
+			var err error
+			stream, err = it.source.Open(seekInfo, it.bufferSize)
+			if err != nil {
+				if !it.reconnect(err) {
+					return nil, it.failure(err, "opening deliver stream failed")
+				}
+				continue
+			}
+			if !it.setStream(stream) {
+				return nil, errClosed
+			}
+		}
+
+		block, err := stream.Recv()
+		if err == io.EOF {
+			err = errors.Errorf("deliver stream ended before block %d", it.next)
+		}
+		if err != nil {
+			it.closeStream()
+			if !it.reconnect(err) {
+				return nil, it.failure(err, "receiving blocks failed")
+			}
+			continue
+		}
+
+		if block.Number < it.next {
+			// already delivered before the stream was reconnected
+			continue
+		}
+		if block.Number > it.next {
+			it.closeStream()
+			return nil, errors.Errorf("expected block %d but received block %d", it.next, block.Number)
+		}
+
+		it.attempts = 0
+		if block.Number == it.to {
+			it.done = true
+			it.closeStream()
+		} else {
+			it.next++
+		}
+		return block, nil
+	}
+
+	return nil, io.EOF
+}
+
+// Close terminates the stream of the iterator. It may be called while Next is blocked, which
+// then returns an error.
+func (it *Iterator) Close() {
+	it.closeOnce.Do(func() { close(it.closed) })
+	it.closeStream()
+}
+
+func (it *Iterator) isClosed() bool {
+	select {
+	case <-it.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (it *Iterator) currentStream() Stream {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+	return it.stream
+}
+
+// setStream sets the opened stream, or closes it and returns false if the iterator is closed
+func (it *Iterator) setStream(stream Stream) bool {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	if it.isClosed() {
+		stream.Close()
+		return false
+	}
+	it.stream = stream
+	return true
+}
+
+func (it *Iterator) closeStream() {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	if it.stream != nil {
+		it.stream.Close()
+		it.stream = nil
+	}
+}
+
+// failure returns the error with which Next fails, which is errClosed if the iterator was closed
+func (it *Iterator) failure(err error, msg string) error {
+	if it.isClosed() {
+		return errClosed
+	}
+	return errors.WithMessage(err, msg)
+}
+
+// reconnect waits before the next attempt to open the stream, unless the attempts are
+// exhausted, the error is not recoverable or the iterator is closed while waiting
+func (it *Iterator) reconnect(err error) bool {
+	if _, ok := errors.Cause(err).(*StatusError); ok || it.attempts >= it.reconnectAttempts || it.isClosed() {
+		return false
+	}
+
+	it.attempts++
+	logger.Warnf("deliver stream failed: %s - reconnecting from block %d (attempt %d)", err, it.next, it.attempts)
+
+	timer := time.NewTimer(it.reconnectBackoff * time.Duration(it.attempts))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-it.closed:
+		return false
+	}
+}
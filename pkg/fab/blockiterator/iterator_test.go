@@ -0,0 +1,220 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockiterator
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+type fakeResult struct {
+	number uint64
+	err    error
+}
+
+type fakeStream struct {
+	results []fakeResult
+	closed  bool
+}
+
+func (s *fakeStream) Recv() (*Block, error) {
+	if len(s.results) == 0 {
+		return nil, io.EOF
+	}
+	result := s.results[0]
+	s.results = s.results[1:]
+	if result.err != nil {
+		return nil, result.err
+	}
+	return &Block{Number: result.number, Block: &common.Block{Header: &common.BlockHeader{Number: result.number}}}, nil
+}
+
+func (s *fakeStream) Close() {
+	s.closed = true
+}
+
+// fakeSource returns the streams in order, or fails to open once they are used up
+type fakeSource struct {
+	streams   []*fakeStream
+	seekInfos []*ab.SeekInfo
+}
+
+func (s *fakeSource) Open(seekInfo *ab.SeekInfo, bufferSize int) (Stream, error) {
+	s.seekInfos = append(s.seekInfos, seekInfo)
+	if len(s.streams) == 0 {
+		return nil, errors.New("connection refused")
+	}
+	stream := s.streams[0]
+	s.streams = s.streams[1:]
+	return stream, nil
+}
+
+func blocks(numbers ...uint64) []fakeResult {
+	var results []fakeResult
+	for _, n := range numbers {
+		results = append(results, fakeResult{number: n})
+	}
+	return results
+}
+
+func readAll(it *Iterator) ([]uint64, error) {
+	var numbers []uint64
+	for {
+		block, err := it.Next()
+		if err == io.EOF {
+			return numbers, nil
+		}
+		if err != nil {
+			return numbers, err
+		}
+		numbers = append(numbers, block.Number)
+	}
+}
+
+func assertSeek(t *testing.T, seekInfo *ab.SeekInfo, from uint64, to uint64) {
+	assert.Equal(t, from, seekInfo.Start.GetSpecified().GetNumber())
+	assert.Equal(t, to, seekInfo.Stop.GetSpecified().GetNumber())
+}
+
+func TestIterator(t *testing.T) {
+	stream := &fakeStream{results: blocks(2, 3, 4)}
+	source := &fakeSource{streams: []*fakeStream{stream}}
+
+	it, err := New(source, 2, 4)
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+	numbers, err := readAll(it)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4}, numbers)
+	assert.True(t, stream.closed)
+
+	if assert.Len(t, source.seekInfos, 1) {
+		assertSeek(t, source.seekInfos[0], 2, 4)
+		assert.Equal(t, ab.SeekInfo_BLOCK_UNTIL_READY, source.seekInfos[0].Behavior)
+	}
+
+	_, err = it.Next()
+	assert.Equal(t, io.EOF, err)
+
+	it.Close()
+	_, err = it.Next()
+	assert.Error(t, err)
+}
+
+func TestIteratorResume(t *testing.T) {
+	source := &fakeSource{streams: []*fakeStream{
+		{results: append(blocks(2, 3), fakeResult{err: errors.New("connection reset")})},
+		{results: blocks(3, 4, 5)},
+	}}
+
+	it, err := New(source, 2, 5, WithReconnect(1, time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+	numbers, err := readAll(it)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4, 5}, numbers)
+
+	if assert.Len(t, source.seekInfos, 2) {
+		assertSeek(t, source.seekInfos[1], 4, 5)
+	}
+}
+
+func TestIteratorCloseDuringReconnect(t *testing.T) {
+	it, err := New(&fakeSource{}, 0, 1, WithReconnect(1, time.Minute))
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+
+	errch := make(chan error, 1)
+	go func() {
+		_, err := it.Next()
+		errch <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	it.Close()
+
+	select {
+	case err := <-errch:
+		assert.Equal(t, errClosed, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not interrupt the reconnect backoff")
+	}
+}
+
+func TestIteratorReconnectAttempts(t *testing.T) {
+	source := &fakeSource{}
+	it, err := New(source, 0, 1, WithReconnect(2, time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+	_, err = it.Next()
+	assert.Error(t, err)
+	assert.Len(t, source.seekInfos, 3)
+
+	// a stream which ends before the range is complete is reconnected too
+	source = &fakeSource{streams: []*fakeStream{{results: blocks(0)}}}
+	it, err = New(source, 0, 1, WithReconnect(0, 0))
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+	numbers, err := readAll(it)
+	assert.Error(t, err)
+	assert.Equal(t, []uint64{0}, numbers)
+}
+
+func TestIteratorStatusError(t *testing.T) {
+	source := &fakeSource{streams: []*fakeStream{
+		{results: []fakeResult{{err: &StatusError{Status: common.Status_NOT_FOUND}}}},
+		{results: blocks(7)},
+	}}
+
+	it, err := New(source, 7, 7, WithFailIfNotReady(), WithReconnect(3, time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+	_, err = it.Next()
+	statusErr, ok := errors.Cause(err).(*StatusError)
+	if assert.True(t, ok, "expected status error but got %v", err) {
+		assert.Equal(t, common.Status_NOT_FOUND, statusErr.Status)
+	}
+	if assert.Len(t, source.seekInfos, 1) {
+		assert.Equal(t, ab.SeekInfo_FAIL_IF_NOT_READY, source.seekInfos[0].Behavior)
+	}
+}
+
+func TestIteratorMissingBlock(t *testing.T) {
+	stream := &fakeStream{results: blocks(1, 3)}
+	it, err := New(&fakeSource{streams: []*fakeStream{stream}}, 1, 3)
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+	numbers, err := readAll(it)
+	assert.Error(t, err)
+	assert.Equal(t, []uint64{1}, numbers)
+	assert.True(t, stream.closed)
+}
+
+func TestNewIterator(t *testing.T) {
+	_, err := New(&fakeSource{}, 2, 1)
+	assert.Error(t, err)
+
+	_, err = New(nil, 1, 2)
+	assert.Error(t, err)
+
+	_, err = New(&fakeSource{}, 1, 2, WithBufferSize(-1))
+	assert.Error(t, err)
+}
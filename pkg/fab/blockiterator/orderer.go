@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockiterator
+
+import (
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// drainTimeout bounds the time for which blocks of a closed orderer stream are discarded until
+// the orderer client terminates
+const drainTimeout = 5 * time.Second
+
+type ordererSource struct {
+	ctx       fabcontext.Context
+	channelID string
+	orderer   fab.Orderer
+}
+
+type ordererStream struct {
+	results chan *ordererResult
+	done    chan struct{}
+	cancel  func()
+}
+
+type ordererResult struct {
+	block *Block
+	err   error
+}
+
+// FromOrderer returns a source of full blocks from the orderer
+func FromOrderer(ctx fabcontext.Context, channelID string, orderer fab.Orderer) Source {
+	return &ordererSource{ctx: ctx, channelID: channelID, orderer: orderer}
+}
+
+// Open sends the signed seek request to the orderer
+func (s *ordererSource) Open(seekInfo *ab.SeekInfo, bufferSize int) (Stream, error) {
+	envelope, err := txn.CreateSeekEnvelope(s.ctx, s.channelID, seekInfo)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating seek envelope failed")
+	}
+
+	blocks, errs, cancel := s.orderer.SendDeliver(envelope)
+	stream := &ordererStream{
+		results: make(chan *ordererResult, bufferSize),
+		done:    make(chan struct{}),
+		cancel:  cancel,
+	}
+	go stream.receive(blocks, errs)
+	return stream, nil
+}
+
+// Recv returns the next block received from the orderer
+func (s *ordererStream) Recv() (*Block, error) {
+	select {
+	case result := <-s.results:
+		return result.block, result.err
+	case <-s.done:
+		return nil, errors.New("deliver stream is closed")
+	}
+}
+
+// Close cancels the deliver request
+func (s *ordererStream) Close() {
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+		s.cancel()
+	}
+}
+
+// receive queues the blocks of the orderer for the reader until the stream ends or is closed
+func (s *ordererStream) receive(blocks chan *common.Block, errs chan error) {
+	for {
+		var result *ordererResult
+		select {
+		case block, ok := <-blocks:
+			switch {
+			case !ok:
+				result = &ordererResult{err: io.EOF}
+			case block == nil || block.Header == nil:
+				result = &ordererResult{err: errors.New("received block without header")}
+			default:
+				result = &ordererResult{block: &Block{Number: block.Header.Number, Block: block}}
+			}
+		case err := <-errs:
+			result = &ordererResult{err: statusError(err)}
+		case <-s.done:
+			drain(blocks, errs)
+			return
+		}
+
+		select {
+		case s.results <- result:
+		case <-s.done:
+			drain(blocks, errs)
+			return
+		}
+		if result.err != nil {
+			return
+		}
+	}
+}
+
+// statusError returns a StatusError if the orderer rejected the seek request, so that the
+// request is not retried
+func statusError(err error) error {
+	if s, ok := status.FromError(err); ok && s.Group == status.OrdererServerStatus {
+		return &StatusError{Status: status.ToOrdererStatusCode(s.Code)}
+	}
+	return err
+}
+
+// drain discards the blocks which the orderer client sends until it terminates
+func drain(blocks chan *common.Block, errs chan error) {
+	timeout := time.After(drainTimeout)
+	for {
+		select {
+		case _, ok := <-blocks:
+			if !ok {
+				return
+			}
+		case <-errs:
+			return
+		case <-timeout:
+			return
+		}
+	}
+}
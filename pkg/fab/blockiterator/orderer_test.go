@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockiterator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// fakeOrderer delivers the blocks of a ledger to each deliver request
+type fakeOrderer struct {
+	blocks    []*common.Block
+	status    common.Status
+	envelopes []*fab.SignedEnvelope
+	cancelled chan struct{}
+}
+
+func (o *fakeOrderer) URL() string {
+	return "orderer.example.com:7050"
+}
+
+func (o *fakeOrderer) SendBroadcast(ctx context.Context, envelope *fab.SignedEnvelope) (*common.Status, error) {
+	return nil, nil
+}
+
+func (o *fakeOrderer) SendDeliver(envelope *fab.SignedEnvelope) (chan *common.Block, chan error, context.CancelFunc) {
+	o.envelopes = append(o.envelopes, envelope)
+	blocks := make(chan *common.Block)
+	errs := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		defer close(o.cancelled)
+		for _, block := range o.blocks {
+			select {
+			case blocks <- block:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+		if o.status != common.Status_UNKNOWN {
+			errs <- status.New(status.OrdererServerStatus, int32(o.status), "error status from ordering service", nil)
+			return
+		}
+		close(blocks)
+	}()
+	return blocks, errs, cancel
+}
+
+func newLedgerBlocks(numbers ...uint64) []*common.Block {
+	var blocks []*common.Block
+	for _, n := range numbers {
+		blocks = append(blocks, &common.Block{Header: &common.BlockHeader{Number: n}})
+	}
+	return blocks
+}
+
+func TestOrdererSource(t *testing.T) {
+	orderer := &fakeOrderer{blocks: newLedgerBlocks(3, 4), cancelled: make(chan struct{})}
+	ctx := fcmocks.NewMockContext(fcmocks.NewMockUser("user"))
+
+	it, err := New(FromOrderer(ctx, "mychannel", orderer), 3, 4)
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+	numbers, err := readAll(it)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 4}, numbers)
+
+	if !assert.Len(t, orderer.envelopes, 1) {
+		return
+	}
+	payload, err := utils.GetPayload(&common.Envelope{Payload: orderer.envelopes[0].Payload})
+	if err != nil {
+		t.Fatalf("invalid seek request payload: %s", err)
+	}
+	channelHeader, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		t.Fatalf("invalid channel header: %s", err)
+	}
+	assert.Equal(t, int32(common.HeaderType_DELIVER_SEEK_INFO), channelHeader.Type)
+	assert.Equal(t, "mychannel", channelHeader.ChannelId)
+
+	seekInfo := &ab.SeekInfo{}
+	if err := proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		t.Fatalf("invalid seek info: %s", err)
+	}
+	assertSeek(t, seekInfo, 3, 4)
+}
+
+func TestOrdererSourceClose(t *testing.T) {
+	orderer := &fakeOrderer{blocks: newLedgerBlocks(1, 2, 3, 4), cancelled: make(chan struct{})}
+	ctx := fcmocks.NewMockContext(fcmocks.NewMockUser("user"))
+
+	it, err := New(FromOrderer(ctx, "mychannel", orderer), 1, 4, WithBufferSize(0))
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+	block, err := it.Next()
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(1), block.Number)
+	}
+
+	it.Close()
+	<-orderer.cancelled
+}
+
+func TestOrdererSourceEndOfStream(t *testing.T) {
+	orderer := &fakeOrderer{blocks: newLedgerBlocks(1), cancelled: make(chan struct{})}
+	ctx := fcmocks.NewMockContext(fcmocks.NewMockUser("user"))
+
+	it, err := New(FromOrderer(ctx, "mychannel", orderer), 1, 2, WithReconnect(0, 0))
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+	numbers, err := readAll(it)
+	assert.Error(t, err)
+	assert.Equal(t, []uint64{1}, numbers)
+}
+
+func TestOrdererSourceStatus(t *testing.T) {
+	orderer := &fakeOrderer{blocks: newLedgerBlocks(1), status: common.Status_NOT_FOUND, cancelled: make(chan struct{})}
+	ctx := fcmocks.NewMockContext(fcmocks.NewMockUser("user"))
+
+	it, err := New(FromOrderer(ctx, "mychannel", orderer), 1, 2, WithFailIfNotReady())
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+	numbers, err := readAll(it)
+	assert.Equal(t, []uint64{1}, numbers)
+	statusErr, ok := errors.Cause(err).(*StatusError)
+	if assert.True(t, ok, "expected status error but got %v", err) {
+		assert.Equal(t, common.Status_NOT_FOUND, statusErr.Status)
+	}
+	assert.Len(t, orderer.envelopes, 1, "seek request must not be retried")
+}
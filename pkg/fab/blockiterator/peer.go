@@ -0,0 +1,138 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockiterator
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	clientdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
+	deliverconn "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/connection"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+type deliverConnection interface {
+	Send(seekInfo *ab.SeekInfo) error
+	Receive(eventch chan<- interface{})
+	Close()
+}
+
+// deliverProvider is the connection provider used for connecting to the deliver service of a peer
+var deliverProvider = func(ctx fabcontext.Context, channelID string, streamProvider deliverconn.StreamProvider, url string) (deliverConnection, error) {
+	return deliverconn.New(ctx, channelID, streamProvider, url)
+}
+
+type peerSource struct {
+	ctx            fabcontext.Context
+	channelID      string
+	url            string
+	streamProvider deliverconn.StreamProvider
+}
+
+type peerStream struct {
+	conn   deliverConnection
+	events chan interface{}
+	exited chan struct{}
+}
+
+// FromPeer returns a source of full blocks from the deliver service of the peer
+func FromPeer(ctx fabcontext.Context, channelID string, peer fab.Peer) Source {
+	return &peerSource{ctx: ctx, channelID: channelID, url: peer.URL(), streamProvider: deliverconn.Deliver}
+}
+
+// FromPeerFiltered returns a source of filtered blocks from the deliver service of the peer
+func FromPeerFiltered(ctx fabcontext.Context, channelID string, peer fab.Peer) Source {
+	return &peerSource{ctx: ctx, channelID: channelID, url: peer.URL(), streamProvider: deliverconn.DeliverFiltered}
+}
+
+// Open connects to the deliver service of the peer and sends the seek request
+func (s *peerSource) Open(seekInfo *ab.SeekInfo, bufferSize int) (Stream, error) {
+	conn, err := deliverProvider(s.ctx, s.channelID, s.streamProvider, s.url)
+	if err != nil {
+		return nil, errors.WithMessage(err, "connection to deliver service failed")
+	}
+
+	stream := &peerStream{
+		conn:   conn,
+		events: make(chan interface{}, bufferSize),
+		exited: make(chan struct{}),
+	}
+	go func() {
+		conn.Receive(stream.events)
+		close(stream.exited)
+	}()
+
+	if err := conn.Send(seekInfo); err != nil {
+		stream.Close()
+		return nil, errors.WithMessage(err, "sending seek request failed")
+	}
+	return stream, nil
+}
+
+// Recv returns the next block received from the peer
+func (s *peerStream) Recv() (*Block, error) {
+	select {
+	case event := <-s.events:
+		return eventToBlock(event)
+	case <-s.exited:
+		// events may have been queued before the receiver exited
+		select {
+		case event := <-s.events:
+			return eventToBlock(event)
+		default:
+			return nil, errors.New("deliver stream terminated")
+		}
+	}
+}
+
+// Close closes the connection. Events which are still sent by the receiver are discarded.
+func (s *peerStream) Close() {
+	s.conn.Close()
+	go func() {
+		for {
+			select {
+			case <-s.events:
+			case <-s.exited:
+				return
+			}
+		}
+	}()
+}
+
+func eventToBlock(event interface{}) (*Block, error) {
+	switch evt := event.(type) {
+	case *pb.DeliverResponse:
+		switch response := evt.Type.(type) {
+		case *pb.DeliverResponse_Block:
+			if response.Block == nil || response.Block.Header == nil {
+				return nil, errors.New("received block without header")
+			}
+			return &Block{Number: response.Block.Header.Number, Block: response.Block}, nil
+		case *pb.DeliverResponse_FilteredBlock:
+			if response.FilteredBlock == nil {
+				return nil, errors.New("received empty filtered block")
+			}
+			return &Block{Number: response.FilteredBlock.Number, FilteredBlock: response.FilteredBlock}, nil
+		case *pb.DeliverResponse_Status:
+			if response.Status == common.Status_SUCCESS {
+				return nil, io.EOF
+			}
+			return nil, &StatusError{Status: response.Status}
+		default:
+			return nil, errors.Errorf("unsupported deliver response type %T", response)
+		}
+	case *clientdisp.DisconnectedEvent:
+		return nil, errors.WithMessage(evt.Err, "disconnected from deliver service")
+	default:
+		return nil, errors.Errorf("unsupported event type %T", event)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockiterator
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	clientdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
+	deliverconn "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/connection"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// fakeConnection produces the events until it is closed, like a deliver connection
+type fakeConnection struct {
+	events    []interface{}
+	seekInfo  *ab.SeekInfo
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newFakeConnection(events ...interface{}) *fakeConnection {
+	return &fakeConnection{events: events, closed: make(chan struct{})}
+}
+
+func (c *fakeConnection) Send(seekInfo *ab.SeekInfo) error {
+	c.seekInfo = seekInfo
+	return nil
+}
+
+func (c *fakeConnection) Receive(eventch chan<- interface{}) {
+	for _, event := range c.events {
+		select {
+		case eventch <- event:
+		case <-c.closed:
+			return
+		}
+	}
+	<-c.closed
+}
+
+func (c *fakeConnection) Close() {
+	c.closeOnce.Do(func() { close(c.closed) })
+}
+
+func blockResponse(number uint64) *pb.DeliverResponse {
+	return &pb.DeliverResponse{Type: &pb.DeliverResponse_Block{Block: &common.Block{Header: &common.BlockHeader{Number: number}}}}
+}
+
+func filteredBlockResponse(number uint64) *pb.DeliverResponse {
+	return &pb.DeliverResponse{Type: &pb.DeliverResponse_FilteredBlock{FilteredBlock: &pb.FilteredBlock{ChannelId: "mychannel", Number: number}}}
+}
+
+func statusResponse(status common.Status) *pb.DeliverResponse {
+	return &pb.DeliverResponse{Type: &pb.DeliverResponse_Status{Status: status}}
+}
+
+// withConnections replaces the deliver provider by one which returns the given connections in order
+func withConnections(connections ...*fakeConnection) (restore func()) {
+	provider := deliverProvider
+	deliverProvider = func(ctx fabcontext.Context, channelID string, streamProvider deliverconn.StreamProvider, url string) (deliverConnection, error) {
+		if len(connections) == 0 {
+			return nil, errors.New("connection refused")
+		}
+		conn := connections[0]
+		connections = connections[1:]
+		return conn, nil
+	}
+	return func() { deliverProvider = provider }
+}
+
+func TestPeerSource(t *testing.T) {
+	conn := newFakeConnection(blockResponse(5), blockResponse(6), statusResponse(common.Status_SUCCESS))
+	defer withConnections(conn)()
+
+	ctx := fcmocks.NewMockContext(fcmocks.NewMockUser("user"))
+	it, err := New(FromPeer(ctx, "mychannel", fcmocks.NewMockPeer("peer1", "peer1.example.com:7051")), 5, 6, WithBufferSize(1))
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+
+	block, err := it.Next()
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(5), block.Number)
+		assert.NotNil(t, block.Block)
+	}
+	assertSeek(t, conn.seekInfo, 5, 6)
+
+	numbers, err := readAll(it)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{6}, numbers)
+}
+
+func TestPeerSourceFiltered(t *testing.T) {
+	defer withConnections(newFakeConnection(filteredBlockResponse(1), filteredBlockResponse(2)))()
+
+	ctx := fcmocks.NewMockContext(fcmocks.NewMockUser("user"))
+	it, err := New(FromPeerFiltered(ctx, "mychannel", fcmocks.NewMockPeer("peer1", "peer1.example.com:7051")), 1, 2)
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+
+	for _, number := range []uint64{1, 2} {
+		block, err := it.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, number, block.Number)
+			assert.Nil(t, block.Block)
+			assert.Equal(t, "mychannel", block.FilteredBlock.ChannelId)
+		}
+	}
+}
+
+func TestPeerSourceDisconnect(t *testing.T) {
+	first := newFakeConnection(blockResponse(1), clientdisp.NewDisconnectedEvent(errors.New("connection reset")))
+	second := newFakeConnection(blockResponse(2), blockResponse(3))
+	defer withConnections(first, second)()
+
+	ctx := fcmocks.NewMockContext(fcmocks.NewMockUser("user"))
+	it, err := New(FromPeer(ctx, "mychannel", fcmocks.NewMockPeer("peer1", "peer1.example.com:7051")), 1, 3, WithReconnect(1, time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+
+	numbers, err := readAll(it)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, numbers)
+	assertSeek(t, second.seekInfo, 2, 3)
+}
+
+func TestPeerSourceStatus(t *testing.T) {
+	defer withConnections(newFakeConnection(statusResponse(common.Status_FORBIDDEN)))()
+
+	ctx := fcmocks.NewMockContext(fcmocks.NewMockUser("user"))
+	it, err := New(FromPeer(ctx, "mychannel", fcmocks.NewMockPeer("peer1", "peer1.example.com:7051")), 1, 3)
+	if err != nil {
+		t.Fatalf("failed to create iterator: %s", err)
+	}
+
+	_, err = it.Next()
+	assert.IsType(t, &StatusError{}, errors.Cause(err))
+}
@@ -0,0 +1,299 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package blockverifier checks the integrity of blocks independently of the peer which returned
+// them: the data hash of each header, the hash chain of consecutive headers and the signatures
+// of the orderers.
+package blockverifier
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+// Verifier verifies blocks against the MSPs of a channel configuration
+type Verifier struct {
+	msps map[string]msp.MSP
+	// MSPs which may sign blocks
+	signers map[string]bool
+}
+
+// Option configures the verifier
+type Option func(v *Verifier) error
+
+// WithOrdererMSPs only accepts block signatures of the given MSPs instead of the orderer MSPs
+// of the channel configuration. Channel configurations contain the MSPs of the application
+// organisations too, which must not sign blocks.
+func WithOrdererMSPs(mspIDs ...string) Option {
+	return func(v *Verifier) error {
+		v.signers = make(map[string]bool)
+		for _, mspID := range mspIDs {
+			if _, ok := v.msps[mspID]; !ok {
+				return errors.Errorf("MSP %s is not part of the channel configuration", mspID)
+			}
+			v.signers[mspID] = true
+		}
+		return nil
+	}
+}
+
+// BlockReport is the result of the verification of a block
+type BlockReport struct {
+	Number uint64
+	// Hash of the block header, which the next block refers to
+	HeaderHash []byte
+	// Set if the data hash of the header does not match the block data
+	DataHashError error
+	// Set if the previous hash of the header does not match the previous block. The linkage of
+	// the first block of a range is only checked if the previous header is known.
+	PreviousHashError error
+	// Set if the block has no valid orderer signature. The genesis block is not signed.
+	SignatureError error
+	// Results of the individual orderer signatures
+	Signatures []*SignatureReport
+}
+
+// SignatureReport is the result of the verification of an orderer signature of a block
+type SignatureReport struct {
+	MSPID string
+	// Set if the signature is invalid
+	Error error
+}
+
+// Report is the result of the verification of a range of blocks
+type Report struct {
+	Blocks []*BlockReport
+}
+
+// New returns a verifier for blocks of the channel with the given configuration. Only block
+// signatures of the orderer MSPs of the configuration are accepted, unless other MSPs are set
+// with WithOrdererMSPs.
+func New(cfg fab.ChannelCfg, cs core.CryptoSuite, opts ...Option) (*Verifier, error) {
+	if cfg == nil {
+		return nil, errors.New("channel configuration is required")
+	}
+
+	msps, err := loadMSPs(cfg.Msps(), cs)
+	if err != nil {
+		return nil, errors.WithMessage(err, "load MSPs from config failed")
+	}
+
+	v := &Verifier{msps: msps}
+	if err := WithOrdererMSPs(cfg.OrdererMsps()...)(v); err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(v.signers) == 0 {
+		return nil, errors.New("orderer MSPs of the channel are unknown")
+	}
+	return v, nil
+}
+
+// VerifyBlock verifies the block. The linkage to the previous block is checked if its header
+// is given.
+func (v *Verifier) VerifyBlock(block *common.Block, previous *common.BlockHeader) (*BlockReport, error) {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return nil, errors.New("block header and data are required")
+	}
+
+	report := &BlockReport{Number: block.Header.Number, HeaderHash: HeaderHash(block.Header)}
+
+	if dataHash := DataHash(block.Data); !bytes.Equal(dataHash, block.Header.DataHash) {
+		report.DataHashError = errors.Errorf("data hash %x does not match the hash %x of the block data", block.Header.DataHash, dataHash)
+	}
+
+	if previous != nil {
+		if previous.Number+1 != block.Header.Number {
+			report.PreviousHashError = errors.Errorf("block %d does not follow block %d", block.Header.Number, previous.Number)
+		} else if previousHash := HeaderHash(previous); !bytes.Equal(previousHash, block.Header.PreviousHash) {
+			report.PreviousHashError = errors.Errorf("previous hash %x does not match the hash %x of block %d", block.Header.PreviousHash, previousHash, previous.Number)
+		}
+	}
+
+	if block.Header.Number > 0 {
+		v.verifySignatures(block, report)
+	}
+
+	if !report.Valid() {
+		logger.Warnf("verification of block %d failed", report.Number)
+	}
+	return report, nil
+}
+
+// VerifyBlocks verifies the blocks, which must be ordered by number, and the hash chain between
+// them. The linkage of the first block is checked if the header of its predecessor is given.
+func (v *Verifier) VerifyBlocks(blocks []*common.Block, previous *common.BlockHeader) (*Report, error) {
+	report := &Report{}
+	for _, block := range blocks {
+		blockReport, err := v.VerifyBlock(block, previous)
+		if err != nil {
+			return nil, errors.WithMessage(err, "block verification failed")
+		}
+		report.Blocks = append(report.Blocks, blockReport)
+		previous = block.Header
+	}
+	return report, nil
+}
+
+// Valid returns true if all checks of the block succeeded
+func (r *BlockReport) Valid() bool {
+	return r.DataHashError == nil && r.PreviousHashError == nil && r.SignatureError == nil
+}
+
+// Valid returns true if all blocks are valid
+func (r *Report) Valid() bool {
+	return len(r.Failed()) == 0
+}
+
+// Failed returns the reports of the blocks which are not valid
+func (r *Report) Failed() []*BlockReport {
+	var failed []*BlockReport
+	for _, block := range r.Blocks {
+		if !block.Valid() {
+			failed = append(failed, block)
+		}
+	}
+	return failed
+}
+
+// HeaderHash returns the hash of the block header, which is the previous hash of the next block
+func HeaderHash(header *common.BlockHeader) []byte {
+	hash := sha256.Sum256(headerBytes(header))
+	return hash[:]
+}
+
+// DataHash returns the hash of the block data, which is the data hash of the block header
+func DataHash(data *common.BlockData) []byte {
+	h := sha256.New()
+	for _, d := range data.Data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// asn1Header is the ASN.1 structure of the header which is hashed and signed by the orderer
+type asn1Header struct {
+	Number       *big.Int
+	PreviousHash []byte
+	DataHash     []byte
+}
+
+func headerBytes(header *common.BlockHeader) []byte {
+	result, err := asn1.Marshal(asn1Header{
+		Number:       new(big.Int).SetUint64(header.Number),
+		PreviousHash: header.PreviousHash,
+		DataHash:     header.DataHash,
+	})
+	if err != nil {
+		// Marshal only fails for unsupported types
+		panic(err)
+	}
+	return result
+}
+
+// verifySignatures verifies the orderer signatures in the block metadata, which sign the
+// metadata value, the signature header and the block header
+func (v *Verifier) verifySignatures(block *common.Block, report *BlockReport) {
+	metadata := &common.Metadata{}
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_SIGNATURES) {
+		report.SignatureError = errors.New("block has no signatures metadata")
+		return
+	}
+	if err := proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], metadata); err != nil {
+		report.SignatureError = errors.Wrap(err, "unmarshal of signatures metadata failed")
+		return
+	}
+	if len(metadata.Signatures) == 0 {
+		report.SignatureError = errors.New("block is not signed")
+		return
+	}
+
+	signedHeader := headerBytes(block.Header)
+	valid := false
+	for _, signature := range metadata.Signatures {
+		signatureReport := v.verifySignature(signature, append(append(append([]byte{}, metadata.Value...), signature.SignatureHeader...), signedHeader...))
+		report.Signatures = append(report.Signatures, signatureReport)
+		if signatureReport.Error == nil {
+			valid = true
+		}
+	}
+	if !valid {
+		report.SignatureError = errors.New("block has no valid orderer signature")
+	}
+}
+
+func (v *Verifier) verifySignature(signature *common.MetadataSignature, signedBytes []byte) *SignatureReport {
+	report := &SignatureReport{}
+
+	signatureHeader := &common.SignatureHeader{}
+	if err := proto.Unmarshal(signature.SignatureHeader, signatureHeader); err != nil {
+		report.Error = errors.Wrap(err, "unmarshal of signature header failed")
+		return report
+	}
+	creator := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(signatureHeader.Creator, creator); err != nil {
+		report.Error = errors.Wrap(err, "unmarshal of signer identity failed")
+		return report
+	}
+	report.MSPID = creator.Mspid
+
+	mspImpl, ok := v.msps[creator.Mspid]
+	if !ok || !v.signers[creator.Mspid] {
+		report.Error = errors.Errorf("MSP %s is not an orderer MSP of the channel", creator.Mspid)
+		return report
+	}
+
+	identity, err := mspImpl.DeserializeIdentity(signatureHeader.Creator)
+	if err != nil {
+		report.Error = errors.WithMessage(err, "failed to deserialize signer identity")
+		return report
+	}
+	if err := identity.Validate(); err != nil {
+		report.Error = errors.WithMessage(err, "signer identity is not valid")
+		return report
+	}
+	if err := identity.Verify(signedBytes, signature.Signature); err != nil {
+		report.Error = errors.WithMessage(err, "signature verification failed")
+	}
+	return report
+}
+
+// loadMSPs loads the MSPs of the channel keyed by their identifiers
+func loadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite) (map[string]msp.MSP, error) {
+	channelMSPs, err := channel.LoadMSPs(mspConfigs, cs)
+	if err != nil {
+		return nil, err
+	}
+
+	msps := make(map[string]msp.MSP)
+	for _, m := range channelMSPs {
+		mspID, err := m.GetIdentifier()
+		if err != nil {
+			return nil, errors.Wrap(err, "MSP identifier not found")
+		}
+		msps[mspID] = m
+	}
+	return msps, nil
+}
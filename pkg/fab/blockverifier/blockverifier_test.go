@@ -0,0 +1,237 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockverifier
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/configtx"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+const cryptoConfigPath = "../../../test/fixtures/fabric/v1/crypto-config"
+
+var (
+	ordererMSPDir = filepath.Join(cryptoConfigPath, "ordererOrganizations/example.com/msp")
+	ordererDir    = filepath.Join(cryptoConfigPath, "ordererOrganizations/example.com/orderers/orderer.example.com/msp")
+	org1MSPDir    = filepath.Join(cryptoConfigPath, "peerOrganizations/org1.example.com/msp")
+	peerDir       = filepath.Join(cryptoConfigPath, "peerOrganizations/org1.example.com/peers/peer0.org1.example.com/msp")
+)
+
+type signer struct {
+	creator []byte
+	key     *ecdsa.PrivateKey
+}
+
+func newSigner(t *testing.T, mspID string, dir string) *signer {
+	cert := readSingleFile(t, filepath.Join(dir, "signcerts"))
+	creator, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: cert})
+	if err != nil {
+		t.Fatalf("marshal of identity failed: %s", err)
+	}
+	key, err := utils.PEMtoPrivateKey(readSingleFile(t, filepath.Join(dir, "keystore")), nil)
+	if err != nil {
+		t.Fatalf("reading private key failed: %s", err)
+	}
+	return &signer{creator: creator, key: key.(*ecdsa.PrivateKey)}
+}
+
+func (s *signer) sign(t *testing.T, msg []byte) []byte {
+	digest := sha256.Sum256(msg)
+	r, sv, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		t.Fatalf("signing failed: %s", err)
+	}
+	sv, _, err = utils.ToLowS(&s.key.PublicKey, sv)
+	if err != nil {
+		t.Fatalf("signing failed: %s", err)
+	}
+	signature, err := utils.MarshalECDSASignature(r, sv)
+	if err != nil {
+		t.Fatalf("signing failed: %s", err)
+	}
+	return signature
+}
+
+func readSingleFile(t *testing.T, dir string) []byte {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one file in %s: %v", dir, err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatalf("reading %s failed: %s", files[0].Name(), err)
+	}
+	return data
+}
+
+func newTestVerifier(t *testing.T, opts ...Option) *Verifier {
+	ordererMSP, err := configtx.NewMSPConfigFromDir("OrdererMSP", ordererMSPDir)
+	if err != nil {
+		t.Fatalf("failed to read orderer MSP: %s", err)
+	}
+	org1MSP, err := configtx.NewMSPConfigFromDir("Org1MSP", org1MSPDir)
+	if err != nil {
+		t.Fatalf("failed to read org1 MSP: %s", err)
+	}
+
+	cfg := &fcmocks.MockChannelCfg{MockName: "mychannel", MockMsps: []*mb.MSPConfig{ordererMSP, org1MSP}, MockOrdererMsps: []string{"OrdererMSP"}}
+	v, err := New(cfg, cryptosuite.GetDefault(), opts...)
+	if err != nil {
+		t.Fatalf("failed to create verifier: %s", err)
+	}
+	return v
+}
+
+// newChain creates a chain of blocks where all blocks but the genesis block are signed
+func newChain(t *testing.T, length int, s *signer) []*common.Block {
+	var blocks []*common.Block
+	var previous *common.BlockHeader
+	for i := 0; i < length; i++ {
+		data := &common.BlockData{Data: [][]byte{[]byte("tx1"), []byte("tx2")}}
+		block := &common.Block{
+			Header:   &common.BlockHeader{Number: uint64(i), DataHash: DataHash(data)},
+			Data:     data,
+			Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+		}
+		if previous != nil {
+			block.Header.PreviousHash = HeaderHash(previous)
+			signBlock(t, block, s)
+		}
+		blocks = append(blocks, block)
+		previous = block.Header
+	}
+	return blocks
+}
+
+func signBlock(t *testing.T, block *common.Block, s *signer) {
+	signatureHeader, err := proto.Marshal(&common.SignatureHeader{Creator: s.creator, Nonce: []byte("nonce")})
+	if err != nil {
+		t.Fatalf("marshal of signature header failed: %s", err)
+	}
+	value := []byte("value")
+	signature := s.sign(t, append(append(append([]byte{}, value...), signatureHeader...), headerBytes(block.Header)...))
+
+	metadata, err := proto.Marshal(&common.Metadata{
+		Value:      value,
+		Signatures: []*common.MetadataSignature{{SignatureHeader: signatureHeader, Signature: signature}},
+	})
+	if err != nil {
+		t.Fatalf("marshal of metadata failed: %s", err)
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = metadata
+}
+
+func TestVerifyBlocks(t *testing.T) {
+	v := newTestVerifier(t)
+	blocks := newChain(t, 4, newSigner(t, "OrdererMSP", ordererDir))
+
+	report, err := v.VerifyBlocks(blocks, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, report.Valid())
+	assert.Len(t, report.Blocks, 4)
+	assert.Empty(t, report.Blocks[0].Signatures)
+	for i, block := range report.Blocks[1:] {
+		if assert.Len(t, block.Signatures, 1) {
+			assert.Equal(t, "OrdererMSP", block.Signatures[0].MSPID)
+			assert.NoError(t, block.Signatures[0].Error)
+		}
+		assert.Equal(t, blocks[i+1].Header.PreviousHash, report.Blocks[i].HeaderHash)
+	}
+
+	// a range which does not start at the genesis block is linked to the given header
+	report, err = v.VerifyBlocks(blocks[2:], blocks[1].Header)
+	if assert.NoError(t, err) {
+		assert.True(t, report.Valid())
+	}
+	report, err = v.VerifyBlocks(blocks[2:], blocks[0].Header)
+	if assert.NoError(t, err) {
+		assert.Error(t, report.Blocks[0].PreviousHashError)
+	}
+}
+
+func TestVerifyTamperedBlocks(t *testing.T) {
+	v := newTestVerifier(t)
+	blocks := newChain(t, 4, newSigner(t, "OrdererMSP", ordererDir))
+
+	// modified data breaks the data hash
+	blocks[1].Data.Data[0] = []byte("modified")
+	// a modified header breaks the signature and the linkage of the next block
+	blocks[2].Header.DataHash = []byte("modified")
+
+	report, err := v.VerifyBlocks(blocks, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, report.Valid())
+	if assert.Len(t, report.Failed(), 3) {
+		assert.Error(t, report.Blocks[1].DataHashError)
+		assert.NoError(t, report.Blocks[1].SignatureError)
+
+		assert.Error(t, report.Blocks[2].DataHashError)
+		assert.Error(t, report.Blocks[2].SignatureError)
+		assert.Error(t, report.Blocks[2].Signatures[0].Error)
+
+		assert.Error(t, report.Blocks[3].PreviousHashError)
+		assert.NoError(t, report.Blocks[3].DataHashError)
+	}
+}
+
+func TestVerifySigner(t *testing.T) {
+	blocks := newChain(t, 2, newSigner(t, "Org1MSP", peerDir))
+
+	// an application MSP may not sign blocks
+	report, err := newTestVerifier(t).VerifyBlock(blocks[1], blocks[0].Header)
+	if assert.NoError(t, err) {
+		assert.Error(t, report.SignatureError)
+		if assert.Len(t, report.Signatures, 1) {
+			assert.Equal(t, "Org1MSP", report.Signatures[0].MSPID)
+			assert.Error(t, report.Signatures[0].Error)
+		}
+	}
+
+	report, err = newTestVerifier(t, WithOrdererMSPs("Org1MSP")).VerifyBlock(blocks[1], blocks[0].Header)
+	if assert.NoError(t, err) {
+		assert.True(t, report.Valid())
+	}
+
+	// unsigned blocks are invalid
+	blocks[1].Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = nil
+	report, err = newTestVerifier(t).VerifyBlock(blocks[1], nil)
+	if assert.NoError(t, err) {
+		assert.Error(t, report.SignatureError)
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	v := newTestVerifier(t)
+	_, err := v.VerifyBlock(&common.Block{}, nil)
+	assert.Error(t, err)
+
+	_, err = New(nil, cryptosuite.GetDefault())
+	assert.Error(t, err)
+
+	_, err = New(&fcmocks.MockChannelCfg{}, cryptosuite.GetDefault(), WithOrdererMSPs("OrdererMSP"))
+	assert.Error(t, err)
+
+	_, err = New(&fcmocks.MockChannelCfg{}, cryptosuite.GetDefault())
+	assert.Error(t, err, "expected error without orderer MSPs")
+}
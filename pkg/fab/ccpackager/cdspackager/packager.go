@@ -0,0 +1,289 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cdspackager creates, signs and reads chaincode deployment spec (CDS) packages in the
+// format of 'peer chaincode package'.
+//
+// A signed package contains the CDS together with an instantiation policy and the endorsements
+// of the chaincode owners. Each owner creates or signs a copy of the package; the copies are
+// merged into the package which is installed on the peers.
+package cdspackager
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Package is a CDS package, which is signed if it has an envelope
+type Package struct {
+	Spec *pb.ChaincodeDeploymentSpec
+	// Envelope of a signed package or nil
+	Envelope *common.Envelope
+
+	signedCDS *pb.SignedChaincodeDeploymentSpec
+}
+
+// NewDeploymentSpec creates the CDS of the chaincode package. The CDS has no effective date, so
+// owners who create it independently from the same code get the same CDS and can merge their packages.
+func NewDeploymentSpec(name string, path string, version string, ccPkg *api.CCPackage) (*pb.ChaincodeDeploymentSpec, error) {
+	if name == "" || path == "" || version == "" {
+		return nil, errors.New("chaincode name, path and version are required")
+	}
+	if ccPkg == nil {
+		return nil, errors.New("chaincode package is required")
+	}
+
+	return &pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        ccPkg.Type,
+			ChaincodeId: &pb.ChaincodeID{Name: name, Path: path, Version: version},
+		},
+		CodePackage: ccPkg.Code,
+	}, nil
+}
+
+// NewSignedPackage creates a signed package of the CDS with the instantiation policy. The package
+// is endorsed and signed by the owner. If no owner is given, the package has no endorsements.
+func NewSignedPackage(spec *pb.ChaincodeDeploymentSpec, instantiationPolicy *common.SignaturePolicyEnvelope, owner context.Context) (*Package, error) {
+	if spec == nil {
+		return nil, errors.New("chaincode deployment spec is required")
+	}
+	if instantiationPolicy == nil {
+		return nil, errors.New("instantiation policy is required")
+	}
+
+	specBytes, err := proto.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of chaincode deployment spec failed")
+	}
+	policyBytes, err := proto.Marshal(instantiationPolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of instantiation policy failed")
+	}
+
+	p := &Package{
+		Spec:      spec,
+		signedCDS: &pb.SignedChaincodeDeploymentSpec{ChaincodeDeploymentSpec: specBytes, InstantiationPolicy: policyBytes},
+	}
+	if owner != nil {
+		if err := p.endorse(owner); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.seal(owner); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Sign adds the endorsement of the owner to the signed package and signs the package
+// on behalf of the owner
+func (p *Package) Sign(owner context.Context) error {
+	if p.signedCDS == nil {
+		return errors.New("package is not signed")
+	}
+	if owner == nil {
+		return errors.New("owner is required")
+	}
+
+	if err := p.endorse(owner); err != nil {
+		return err
+	}
+	return p.seal(owner)
+}
+
+// Merge combines the endorsements of the signed packages of the same CDS and instantiation
+// policy into a package which can be installed on the peers
+func Merge(packages ...*Package) (*Package, error) {
+	if len(packages) == 0 {
+		return nil, errors.New("at least one package is required")
+	}
+
+	var endorsements []*pb.Endorsement
+	for i, p := range packages {
+		if p.signedCDS == nil {
+			return nil, errors.Errorf("package %d is not signed", i)
+		}
+		if !bytes.Equal(p.signedCDS.ChaincodeDeploymentSpec, packages[0].signedCDS.ChaincodeDeploymentSpec) {
+			return nil, errors.Errorf("package %d contains a different chaincode deployment spec", i)
+		}
+		if !bytes.Equal(p.signedCDS.InstantiationPolicy, packages[0].signedCDS.InstantiationPolicy) {
+			return nil, errors.Errorf("package %d contains a different instantiation policy", i)
+		}
+		endorsements = append(endorsements, p.signedCDS.OwnerEndorsements...)
+	}
+
+	merged := &Package{
+		Spec: packages[0].Spec,
+		signedCDS: &pb.SignedChaincodeDeploymentSpec{
+			ChaincodeDeploymentSpec: packages[0].signedCDS.ChaincodeDeploymentSpec,
+			InstantiationPolicy:     packages[0].signedCDS.InstantiationPolicy,
+			OwnerEndorsements:       endorsements,
+		},
+	}
+	if err := merged.seal(nil); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// ReadFile reads a package written by 'peer chaincode package' or Bytes
+func ReadFile(path string) (*Package, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading chaincode package failed")
+	}
+	return Read(data)
+}
+
+// Read reads a package, which is either a signed package or a CDS
+func Read(data []byte) (*Package, error) {
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(data, envelope); err == nil && len(envelope.Payload) > 0 {
+		if p, err := readSignedPackage(envelope); err == nil {
+			return p, nil
+		}
+	}
+
+	spec := &pb.ChaincodeDeploymentSpec{}
+	if err := proto.Unmarshal(data, spec); err != nil || spec.GetChaincodeSpec().GetChaincodeId() == nil {
+		return nil, errors.New("data is neither a signed chaincode package nor a chaincode deployment spec")
+	}
+	return &Package{Spec: spec}, nil
+}
+
+// Bytes returns the package in the format of 'peer chaincode package'
+func (p *Package) Bytes() ([]byte, error) {
+	var msg proto.Message = p.Spec
+	if p.Envelope != nil {
+		msg = p.Envelope
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of chaincode package failed")
+	}
+	return data, nil
+}
+
+// ChaincodeID returns the name, path and version of the chaincode
+func (p *Package) ChaincodeID() *pb.ChaincodeID {
+	return p.Spec.GetChaincodeSpec().GetChaincodeId()
+}
+
+// InstantiationPolicy returns the instantiation policy of a signed package
+func (p *Package) InstantiationPolicy() (*common.SignaturePolicyEnvelope, error) {
+	if p.signedCDS == nil {
+		return nil, errors.New("package is not signed")
+	}
+	policy := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(p.signedCDS.InstantiationPolicy, policy); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of instantiation policy failed")
+	}
+	return policy, nil
+}
+
+// Endorsements returns the owner endorsements of a signed package
+func (p *Package) Endorsements() []*pb.Endorsement {
+	if p.signedCDS == nil {
+		return nil
+	}
+	return p.signedCDS.OwnerEndorsements
+}
+
+// CCPackage returns the package for an install request
+func (p *Package) CCPackage() *api.CCPackage {
+	return &api.CCPackage{
+		Type:   p.Spec.GetChaincodeSpec().GetType(),
+		Code:   p.Spec.CodePackage,
+		Signed: p.Envelope,
+	}
+}
+
+// endorse adds the endorsement of the owner, who signs the CDS, the instantiation policy and
+// the owner's identity
+func (p *Package) endorse(owner context.Context) error {
+	endorser, err := owner.Identity()
+	if err != nil {
+		return errors.WithMessage(err, "failed to get owner's identity")
+	}
+
+	signingBytes := append(append(append([]byte{}, p.signedCDS.ChaincodeDeploymentSpec...), p.signedCDS.InstantiationPolicy...), endorser...)
+	signature, err := owner.SigningManager().Sign(signingBytes, owner.PrivateKey())
+	if err != nil {
+		return errors.WithMessage(err, "signing of chaincode package failed")
+	}
+
+	p.signedCDS.OwnerEndorsements = append(p.signedCDS.OwnerEndorsements, &pb.Endorsement{Endorser: endorser, Signature: signature})
+	return nil
+}
+
+// seal creates the envelope of the signed package, which is signed by the owner if given
+func (p *Package) seal(owner context.Context) error {
+	signatureHeader := &common.SignatureHeader{}
+	if owner != nil {
+		creator, err := owner.Identity()
+		if err != nil {
+			return errors.WithMessage(err, "failed to get owner's identity")
+		}
+		nonce, err := crypto.GetRandomNonce()
+		if err != nil {
+			return errors.WithMessage(err, "nonce creation failed")
+		}
+		signatureHeader = &common.SignatureHeader{Creator: creator, Nonce: nonce}
+	}
+
+	data, err := proto.Marshal(p.signedCDS)
+	if err != nil {
+		return errors.Wrap(err, "marshal of signed chaincode deployment spec failed")
+	}
+	channelHeader := protos_utils.MakeChannelHeader(common.HeaderType_CHAINCODE_PACKAGE, 0, "", 0)
+	payload := &common.Payload{Header: protos_utils.MakePayloadHeader(channelHeader, signatureHeader), Data: data}
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "marshal of payload failed")
+	}
+
+	envelope := &common.Envelope{Payload: payloadBytes}
+	if owner != nil {
+		envelope.Signature, err = owner.SigningManager().Sign(payloadBytes, owner.PrivateKey())
+		if err != nil {
+			return errors.WithMessage(err, "signing of chaincode package failed")
+		}
+	}
+	p.Envelope = envelope
+	return nil
+}
+
+func readSignedPackage(envelope *common.Envelope) (*Package, error) {
+	payload, err := protos_utils.GetPayload(envelope)
+	if err != nil || payload.Header == nil {
+		return nil, errors.New("invalid payload")
+	}
+	channelHeader, err := protos_utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil || channelHeader.Type != int32(common.HeaderType_CHAINCODE_PACKAGE) {
+		return nil, errors.New("payload is not a chaincode package")
+	}
+
+	signedCDS := &pb.SignedChaincodeDeploymentSpec{}
+	if err := proto.Unmarshal(payload.Data, signedCDS); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of signed chaincode deployment spec failed")
+	}
+	spec := &pb.ChaincodeDeploymentSpec{}
+	if err := proto.Unmarshal(signedCDS.ChaincodeDeploymentSpec, spec); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of chaincode deployment spec failed")
+	}
+	return &Package{Spec: spec, Envelope: envelope, signedCDS: signedCDS}, nil
+}
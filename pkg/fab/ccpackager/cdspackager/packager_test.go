@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cdspackager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/internal/testutils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/nodepackager"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestSignedPackage(t *testing.T) {
+	spec, err := NewDeploymentSpec("examplecc", "github.com/example_cc", "v1", &api.CCPackage{Type: pb.ChaincodeSpec_GOLANG, Code: []byte("code")})
+	if err != nil {
+		t.Fatalf("Failed to create deployment spec: %s", err)
+	}
+	policy := cauthdsl.SignedByAnyMember([]string{"Org1MSP", "Org2MSP"})
+
+	owner1 := mocks.NewMockContext(mocks.NewMockUserWithMSPID("user1", "Org1MSP"))
+	pkg1, err := NewSignedPackage(spec, policy, owner1)
+	if err != nil {
+		t.Fatalf("Failed to create signed package: %s", err)
+	}
+	if !assert.Len(t, pkg1.Endorsements(), 1) {
+		t.FailNow()
+	}
+	assert.NotEmpty(t, pkg1.Envelope.Signature)
+
+	// The mock signing manager returns the signed bytes as signature
+	endorsement := pkg1.Endorsements()[0]
+	specBytes, err := proto.Marshal(spec)
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %s", err)
+	}
+	policyBytes, err := proto.Marshal(policy)
+	if err != nil {
+		t.Fatalf("Failed to marshal policy: %s", err)
+	}
+	assert.Equal(t, append(append(specBytes, policyBytes...), endorsement.Endorser...), endorsement.Signature)
+	assert.Equal(t, pkg1.Envelope.Payload, pkg1.Envelope.Signature)
+
+	// The package is written and read like a file of 'peer chaincode package -s'
+	data, err := pkg1.Bytes()
+	if err != nil {
+		t.Fatalf("Failed to marshal package: %s", err)
+	}
+	pkg2, err := Read(data)
+	if err != nil {
+		t.Fatalf("Failed to read package: %s", err)
+	}
+	assert.Equal(t, "examplecc", pkg2.ChaincodeID().Name)
+	readPolicy, err := pkg2.InstantiationPolicy()
+	if err != nil {
+		t.Fatalf("Failed to get instantiation policy: %s", err)
+	}
+	assert.True(t, proto.Equal(policy, readPolicy), "unexpected instantiation policy")
+
+	// A second owner co-signs the package
+	owner2 := mocks.NewMockContext(mocks.NewMockUserWithMSPID("user2", "Org2MSP"))
+	if err := pkg2.Sign(owner2); err != nil {
+		t.Fatalf("Failed to sign package: %s", err)
+	}
+	assert.Len(t, pkg2.Endorsements(), 2)
+
+	unsigned, err := NewSignedPackage(spec, policy, nil)
+	if err != nil {
+		t.Fatalf("Failed to create signed package: %s", err)
+	}
+	assert.Empty(t, unsigned.Endorsements())
+	assert.Empty(t, unsigned.Envelope.Signature)
+
+	merged, err := Merge(pkg1, pkg2, unsigned)
+	if err != nil {
+		t.Fatalf("Failed to merge packages: %s", err)
+	}
+	assert.Len(t, merged.Endorsements(), 3)
+	assert.Empty(t, merged.Envelope.Signature)
+
+	ccPackage := merged.CCPackage()
+	assert.Equal(t, pb.ChaincodeSpec_GOLANG, ccPackage.Type)
+	assert.Equal(t, []byte("code"), ccPackage.Code)
+	assert.Equal(t, merged.Envelope, ccPackage.Signed)
+}
+
+func TestMergeIndependentPackages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cdspackager")
+	if err != nil {
+		t.Fatalf("error from ioutil.TempDir %v", err)
+	}
+	defer os.RemoveAll(dir)
+	testutils.WriteFiles(t, dir, []string{"package.json", "index.js"})
+	policy := cauthdsl.SignedByAnyMember([]string{"Org1MSP", "Org2MSP"})
+
+	// Each owner packages the chaincode on its own
+	newPackage := func(owner *mocks.MockContext) *Package {
+		ccPkg, err := nodepackager.NewCCPackage(dir)
+		if err != nil {
+			t.Fatalf("Failed to package chaincode: %s", err)
+		}
+		spec, err := NewDeploymentSpec("examplecc", dir, "v1", ccPkg)
+		if err != nil {
+			t.Fatalf("Failed to create deployment spec: %s", err)
+		}
+		p, err := NewSignedPackage(spec, policy, owner)
+		if err != nil {
+			t.Fatalf("Failed to create signed package: %s", err)
+		}
+		return p
+	}
+	pkg1 := newPackage(mocks.NewMockContext(mocks.NewMockUserWithMSPID("user1", "Org1MSP")))
+	pkg2 := newPackage(mocks.NewMockContext(mocks.NewMockUserWithMSPID("user2", "Org2MSP")))
+
+	merged, err := Merge(pkg1, pkg2)
+	if err != nil {
+		t.Fatalf("Failed to merge packages: %s", err)
+	}
+	assert.Len(t, merged.Endorsements(), 2)
+	assert.Nil(t, merged.Spec.EffectiveDate)
+}
+
+func TestReadDeploymentSpec(t *testing.T) {
+	spec, err := NewDeploymentSpec("examplecc", "github.com/example_cc", "v1", &api.CCPackage{Type: pb.ChaincodeSpec_GOLANG, Code: []byte("code")})
+	if err != nil {
+		t.Fatalf("Failed to create deployment spec: %s", err)
+	}
+	data, err := (&Package{Spec: spec}).Bytes()
+	if err != nil {
+		t.Fatalf("Failed to marshal package: %s", err)
+	}
+
+	dir, err := ioutil.TempDir("", "cds")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "examplecc.cds")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write package: %s", err)
+	}
+
+	pkg, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read package: %s", err)
+	}
+	assert.True(t, proto.Equal(spec, pkg.Spec), "unexpected deployment spec")
+	assert.Nil(t, pkg.Envelope)
+	assert.Nil(t, pkg.CCPackage().Signed)
+
+	_, err = pkg.InstantiationPolicy()
+	assert.NotNil(t, err, "expected error for unsigned package")
+	assert.NotNil(t, pkg.Sign(mocks.NewMockContext(mocks.NewMockUser("user1"))), "expected error signing unsigned package")
+	_, err = Merge(pkg)
+	assert.NotNil(t, err, "expected error merging unsigned package")
+
+	_, err = Read([]byte("invalid"))
+	assert.NotNil(t, err, "expected error for invalid package")
+	_, err = ReadFile(filepath.Join(dir, "missing.cds"))
+	assert.NotNil(t, err, "expected error for missing file")
+}
+
+func TestSignedPackageErrors(t *testing.T) {
+	spec, err := NewDeploymentSpec("examplecc", "github.com/example_cc", "v1", &api.CCPackage{Type: pb.ChaincodeSpec_GOLANG, Code: []byte("code")})
+	if err != nil {
+		t.Fatalf("Failed to create deployment spec: %s", err)
+	}
+
+	_, err = NewDeploymentSpec("examplecc", "", "v1", &api.CCPackage{})
+	assert.NotNil(t, err, "expected error for missing path")
+	_, err = NewDeploymentSpec("examplecc", "github.com/example_cc", "v1", nil)
+	assert.NotNil(t, err, "expected error for missing package")
+
+	_, err = NewSignedPackage(nil, cauthdsl.SignedByMspMember("Org1MSP"), nil)
+	assert.NotNil(t, err, "expected error for missing spec")
+	_, err = NewSignedPackage(spec, nil, nil)
+	assert.NotNil(t, err, "expected error for missing policy")
+
+	pkg1, err := NewSignedPackage(spec, cauthdsl.SignedByMspMember("Org1MSP"), nil)
+	if err != nil {
+		t.Fatalf("Failed to create signed package: %s", err)
+	}
+	pkg2, err := NewSignedPackage(spec, cauthdsl.SignedByMspMember("Org2MSP"), nil)
+	if err != nil {
+		t.Fatalf("Failed to create signed package: %s", err)
+	}
+	_, err = Merge(pkg1, pkg2)
+	assert.NotNil(t, err, "expected error for different instantiation policies")
+	_, err = Merge()
+	assert.NotNil(t, err, "expected error for missing packages")
+	assert.NotNil(t, pkg1.Sign(nil), "expected error for missing owner")
+}
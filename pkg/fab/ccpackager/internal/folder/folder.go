@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package folder packages the source folder of a chaincode in the layout used by the peer
+// for chaincode which is not written in Go.
+package folder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// metaInfDir contains the chaincode metadata, e.g. META-INF/statedb/couchdb/indexes
+const metaInfDir = "META-INF"
+
+// NewTarGz returns the .tar.gz of the files in the root folder. Source files are placed under
+// src/ and metadata files under META-INF/. The .git folder, the given top level folders and
+// files with the given extensions are excluded, as are hidden metadata files.
+func NewTarGz(root string, excludeDirs []string, excludeFileTypes []string) ([]byte, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "chaincode folder not found")
+	}
+	if !info.IsDir() {
+		return nil, errors.Errorf("chaincode path [%s] is not a folder", root)
+	}
+
+	var codePackage bytes.Buffer
+	gw := gzip.NewWriter(&codePackage)
+	tw := tar.NewWriter(gw)
+
+	fileCount := 0
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if info.IsDir() {
+			if info.Name() == ".git" || (path != root && contains(excludeDirs, relPath)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || contains(excludeFileTypes, filepath.Ext(path)) {
+			return nil
+		}
+
+		name := "src/" + relPath
+		if strings.HasPrefix(relPath, metaInfDir+"/") {
+			if strings.HasPrefix(info.Name(), ".") {
+				return nil
+			}
+			name = relPath
+		}
+
+		fileCount++
+		return writeFile(tw, path, name, info)
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gw.Close()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "packaging of chaincode folder failed")
+	}
+	if fileCount == 0 {
+		return nil, errors.Errorf("no chaincode files found in [%s]", root)
+	}
+	return codePackage.Bytes(), nil
+}
+
+func writeFile(tw *tar.Writer, path string, name string, info os.FileInfo) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Use a deterministic "zero-time" for all date fields
+	header := &tar.Header{
+		Name:    name,
+		Size:    info.Size(),
+		Mode:    int64(info.Mode().Perm()),
+		ModTime: time.Time{},
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
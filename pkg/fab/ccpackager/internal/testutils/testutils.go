@@ -0,0 +1,54 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package testutils creates chaincode folders and reads chaincode packages in the tests of the packagers.
+package testutils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// WriteFiles creates the files, given relative to the root folder, and their parent folders
+func WriteFiles(t *testing.T, root string, files []string) {
+	for _, file := range files {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("error from os.MkdirAll %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(path), 0644); err != nil {
+			t.Fatalf("error from ioutil.WriteFile %v", err)
+		}
+	}
+}
+
+// PackagedFiles returns the names of the files in the .tar.gz code of a chaincode package
+func PackagedFiles(t *testing.T, code []byte) []string {
+	gzf, err := gzip.NewReader(bytes.NewReader(code))
+	if err != nil {
+		t.Fatalf("error from gzip.NewReader %v", err)
+	}
+	tarReader := tar.NewReader(gzf)
+
+	var names []string
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("error from tarReader.Next() %v", err)
+		}
+		names = append(names, header.Name)
+	}
+	return names
+}
@@ -0,0 +1,36 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package javapackager
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/internal/folder"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Top level build output folders and compiled files are not packaged,
+// since the peer builds the chaincode from source
+var excludeDirs = []string{"target", "build", "out"}
+var excludeFileTypes = []string{".class"}
+
+// NewCCPackage creates new Java chaincode package from the chaincode project folder,
+// which is also the path of the install request
+func NewCCPackage(chaincodePath string) (*api.CCPackage, error) {
+	if chaincodePath == "" {
+		return nil, errors.New("chaincode path must be provided")
+	}
+
+	tarBytes, err := folder.NewTarGz(chaincodePath, excludeDirs, excludeFileTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.CCPackage{Type: pb.ChaincodeSpec_JAVA, Code: tarBytes}, nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package javapackager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/internal/testutils"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Test Java chaincode packaging
+func TestNewCCPackage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "javacc")
+	if err != nil {
+		t.Fatalf("error from ioutil.TempDir %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []string{
+		"build.gradle",
+		"src/main/java/example/Chaincode.java",
+		"src/main/resources/Example.class",
+		"target/chaincode.jar",
+		"build/libs/chaincode.jar",
+		"out/Chaincode.class",
+		".git/HEAD",
+	}
+	testutils.WriteFiles(t, dir, files)
+
+	ccPackage, err := NewCCPackage(dir)
+	if err != nil {
+		t.Fatalf("error from NewCCPackage %v", err)
+	}
+	assert.Equal(t, pb.ChaincodeSpec_JAVA, ccPackage.Type)
+
+	expected := []string{
+		"src/build.gradle",
+		"src/src/main/java/example/Chaincode.java",
+	}
+	assert.Equal(t, expected, testutils.PackagedFiles(t, ccPackage.Code))
+
+	_, err = NewCCPackage("")
+	assert.NotNil(t, err, "expected error for missing path")
+
+	_, err = NewCCPackage(filepath.Join(dir, "out"))
+	assert.NotNil(t, err, "expected error for folder without chaincode files")
+}
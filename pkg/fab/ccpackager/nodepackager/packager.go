@@ -0,0 +1,35 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nodepackager
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/internal/folder"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Top level folders which are not packaged. The peer installs the node modules
+// when it builds the chaincode.
+var excludeDirs = []string{"node_modules"}
+
+// NewCCPackage creates new Node.js chaincode package from the chaincode folder,
+// which is also the path of the install request
+func NewCCPackage(chaincodePath string) (*api.CCPackage, error) {
+	if chaincodePath == "" {
+		return nil, errors.New("chaincode path must be provided")
+	}
+
+	tarBytes, err := folder.NewTarGz(chaincodePath, excludeDirs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.CCPackage{Type: pb.ChaincodeSpec_NODE, Code: tarBytes}, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nodepackager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/internal/testutils"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Test Node.js chaincode packaging
+func TestNewCCPackage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nodecc")
+	if err != nil {
+		t.Fatalf("error from ioutil.TempDir %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []string{
+		"package.json",
+		"chaincode.js",
+		"lib/util.js",
+		"node_modules/fabric-shim/index.js",
+		"lib/node_modules/dep.js",
+		".git/HEAD",
+		"META-INF/statedb/couchdb/indexes/indexOwner.json",
+		"META-INF/statedb/couchdb/indexes/.hidden",
+	}
+	testutils.WriteFiles(t, dir, files)
+
+	ccPackage, err := NewCCPackage(dir)
+	if err != nil {
+		t.Fatalf("error from NewCCPackage %v", err)
+	}
+	assert.Equal(t, pb.ChaincodeSpec_NODE, ccPackage.Type)
+
+	expected := []string{
+		"META-INF/statedb/couchdb/indexes/indexOwner.json",
+		"src/chaincode.js",
+		"src/lib/node_modules/dep.js",
+		"src/lib/util.js",
+		"src/package.json",
+	}
+	assert.Equal(t, expected, testutils.PackagedFiles(t, ccPackage.Code))
+
+	_, err = NewCCPackage("")
+	assert.NotNil(t, err, "expected error for missing path")
+
+	_, err = NewCCPackage(filepath.Join(dir, "missing"))
+	assert.NotNil(t, err, "expected error for missing folder")
+
+	_, err = NewCCPackage(filepath.Join(dir, "node_modules"))
+	assert.Nil(t, err, "node_modules is only excluded at the top level of the chaincode")
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"crypto/x509"
 	"encoding/pem"
 	"strings"
@@ -18,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
@@ -67,7 +69,7 @@ func New(ctx context.Context, cfg fab.ChannelCfg) (*Channel, error) {
 
 	mspManager := msp.NewMSPManager()
 	if len(cfg.Msps()) > 0 {
-		msps, err := loadMSPs(cfg.Msps(), ctx.CryptoSuite())
+		msps, err := LoadMSPs(cfg.Msps(), ctx.CryptoSuite())
 		if err != nil {
 			return nil, errors.WithMessage(err, "load MSPs from config failed")
 		}
@@ -100,9 +102,9 @@ func New(ctx context.Context, cfg fab.ChannelCfg) (*Channel, error) {
 
 		var o *orderer.Orderer
 		if oCfg == nil {
-			o, err = orderer.New(ctx.Config(), orderer.WithURL(name), orderer.WithServerName(resolveOrdererAddress(name)))
+			o, err = orderer.New(ctx.Config(), orderer.WithURL(name), orderer.WithServerName(resolveOrdererAddress(name)), orderer.WithConnector(comm.ContextConnector(ctx)))
 		} else {
-			o, err = orderer.New(ctx.Config(), orderer.FromOrdererConfig(oCfg))
+			o, err = orderer.New(ctx.Config(), orderer.FromOrdererConfig(oCfg), orderer.WithConnector(comm.ContextConnector(ctx)))
 		}
 
 		if err != nil {
@@ -490,7 +492,7 @@ func (c *Channel) SendInstantiateProposal(chaincodeName string,
 		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "creation of chaincode proposal failed")
 	}
 
-	tpr, err := txn.SendProposal(c.clientContext, tp, targets)
+	tpr, err := txn.SendProposal(reqContext.Background(), c.clientContext, tp, targets)
 	return tpr, tp.TxnID, err
 }
 
@@ -533,7 +535,7 @@ func (c *Channel) SendUpgradeProposal(chaincodeName string,
 		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "creation of chaincode proposal failed")
 	}
 
-	tpr, err := txn.SendProposal(c.clientContext, tp, targets)
+	tpr, err := txn.SendProposal(reqContext.Background(), c.clientContext, tp, targets)
 	return tpr, tp.TxnID, err
 }
 
@@ -80,8 +80,9 @@ func createConfigEnvelope(data []byte) (*common.ConfigEnvelope, error) {
 	return configEnvelope, nil
 }
 
-func loadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite) ([]msp.MSP, error) {
-	logger.Debugf("loadMSPs - start number of msps=%d", len(mspConfigs))
+// LoadMSPs creates the verifying MSPs of a channel from their configurations
+func LoadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite) ([]msp.MSP, error) {
+	logger.Debugf("LoadMSPs - start number of msps=%d", len(mspConfigs))
 
 	msps := []msp.MSP{}
 	for _, config := range mspConfigs {
@@ -114,7 +115,7 @@ func loadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite) ([]msp.MSP, error
 		var orgs []string
 		orgUnits := fabricConfig.OrganizationalUnitIdentifiers
 		for _, orgUnit := range orgUnits {
-			logger.Debugf("loadMSPs - found org of :: %s", orgUnit.OrganizationalUnitIdentifier)
+			logger.Debugf("LoadMSPs - found org of :: %s", orgUnit.OrganizationalUnitIdentifier)
 			orgs = append(orgs, orgUnit.OrganizationalUnitIdentifier)
 		}
 
@@ -130,12 +131,12 @@ func loadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite) ([]msp.MSP, error
 		}
 
 		mspID, _ := newMSP.GetIdentifier()
-		logger.Debugf("loadMSPs - adding msp=%s", mspID)
+		logger.Debugf("LoadMSPs - adding msp=%s", mspID)
 
 		msps = append(msps, newMSP)
 	}
 
-	logger.Debugf("loadMSPs - loaded %d MSPs", len(msps))
+	logger.Debugf("LoadMSPs - loaded %d MSPs", len(msps))
 	return msps, nil
 }
 
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"net/http"
 	"strconv"
 
@@ -299,7 +300,7 @@ func queryChaincode(ctx context.Context, channelID string, request fab.Chaincode
 	if err != nil {
 		return nil, errors.WithMessage(err, "NewProposal failed")
 	}
-	tprs, errs := txn.SendProposal(ctx, tp, targets)
+	tprs, errs := txn.SendProposal(reqContext.Background(), ctx, tp, targets)
 
 	return filterResponses(tprs, errs)
 }
@@ -7,15 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
+
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/urlutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
 // Transactor enables sending transactions and transaction proposals on the channel.
@@ -59,7 +63,7 @@ func orderersFromChannelCfg(ctx context.Context, cfg fab.ChannelCfg) ([]fab.Orde
 
 		if !ok {
 			// TODO: need default options
-			o, err := orderer.New(ctx.Config(), orderer.WithURL(target))
+			o, err := orderer.New(ctx.Config(), orderer.WithURL(target), orderer.WithConnector(comm.ContextConnector(ctx)))
 			// TODO: should we fail hard if we cannot configure a default orderer?
 			//if err != nil {
 			//	return nil, errors.WithMessage(err, "failed to create orderer from defaults")
@@ -68,7 +72,7 @@ func orderersFromChannelCfg(ctx context.Context, cfg fab.ChannelCfg) ([]fab.Orde
 				orderers = append(orderers, o)
 			}
 		} else {
-			o, err := orderer.New(ctx.Config(), orderer.FromOrdererConfig(&oCfg))
+			o, err := orderer.New(ctx.Config(), orderer.FromOrdererConfig(&oCfg), orderer.WithConnector(comm.ContextConnector(ctx)))
 			if err != nil {
 				return nil, errors.WithMessage(err, "failed to create orderer from config")
 			}
@@ -104,8 +108,21 @@ func (t *Transactor) CreateTransactionHeader() (fab.TransactionHeader, error) {
 }
 
 // SendTransactionProposal sends a TransactionProposal to the target peers.
-func (t *Transactor) SendTransactionProposal(proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
-	return txn.SendProposal(t.ctx, proposal, targets)
+func (t *Transactor) SendTransactionProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	return txn.SendProposal(reqCtx, t.ctx, proposal, targets)
+}
+
+// SignTransactionProposal signs a TransactionProposal with the identity of the current context.
+func (t *Transactor) SignTransactionProposal(proposal *fab.TransactionProposal) (*pb.SignedProposal, error) {
+	if proposal == nil {
+		return nil, errors.New("proposal is required")
+	}
+	return txn.SignProposal(t.ctx, proposal.Proposal)
+}
+
+// SendSignedTransactionProposal sends a TransactionProposal, which has been signed outside of the SDK, to the target peers.
+func (t *Transactor) SendSignedTransactionProposal(reqCtx reqContext.Context, proposal *pb.SignedProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	return txn.SendSignedProposal(reqCtx, proposal, targets)
 }
 
 // CreateTransaction create a transaction with proposal response.
@@ -115,6 +132,11 @@ func (t *Transactor) CreateTransaction(request fab.TransactionRequest) (*fab.Tra
 }
 
 // SendTransaction send a transaction to the chain’s orderer service (one or more orderer endpoints) for consensus and committing to the ledger.
-func (t *Transactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
-	return txn.Send(t.ctx, tx, t.orderers)
+func (t *Transactor) SendTransaction(reqCtx reqContext.Context, tx *fab.Transaction) (*fab.TransactionResponse, error) {
+	return txn.Send(reqCtx, t.ctx, tx, t.orderers)
+}
+
+// SendSignedTransaction sends a transaction envelope, which has been signed outside of the SDK, to the chain's orderer service.
+func (t *Transactor) SendSignedTransaction(reqCtx reqContext.Context, envelope *fab.SignedEnvelope) (*fab.TransactionResponse, error) {
+	return txn.SendEnvelope(reqCtx, envelope, t.orderers)
 }
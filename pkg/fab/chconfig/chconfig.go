@@ -7,10 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package chconfig
 
 import (
+	"strings"
+
 	"github.com/golang/protobuf/proto"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
@@ -58,6 +61,7 @@ type ChannelConfig struct {
 type ChannelCfg struct {
 	name        string
 	msps        []*msp.MSPConfig
+	ordererMsps []string
 	anchorPeers []*fab.OrgAnchorPeer
 	orderers    []string
 	versions    *fab.Versions
@@ -79,6 +83,11 @@ func (cfg *ChannelCfg) Msps() []*msp.MSPConfig {
 	return cfg.msps
 }
 
+// OrdererMsps returns the IDs of the MSPs of the orderer organisations
+func (cfg *ChannelCfg) OrdererMsps() []string {
+	return cfg.ordererMsps
+}
+
 // AnchorPeers returns anchor peers
 func (cfg *ChannelCfg) AnchorPeers() []*fab.OrgAnchorPeer {
 	return cfg.anchorPeers
@@ -131,7 +140,7 @@ func (c *ChannelConfig) queryPeers() (*ChannelCfg, error) {
 		}
 
 		for _, p := range chPeers {
-			newPeer, err := peer.New(c.ctx.Config(), peer.FromPeerConfig(&p.NetworkPeer))
+			newPeer, err := peer.New(c.ctx.Config(), peer.FromPeerConfig(&p.NetworkPeer), peer.WithConnector(comm.ContextConnector(c.ctx)))
 			if err != nil || newPeer == nil {
 				return nil, errors.WithMessage(err, "NewPeer failed")
 			}
@@ -373,6 +382,14 @@ func loadConfigValue(configItems *ChannelCfg, key string, versionsValue *common.
 		}
 
 		configItems.msps = append(configItems.msps, mspConfig)
+
+		if strings.HasPrefix(groupName, "base."+channelConfig.OrdererGroupKey+".") {
+			fabricMSPConfig := &msp.FabricMSPConfig{}
+			if err := proto.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+				return errors.Wrap(err, "unmarshal FabricMSPConfig from config failed")
+			}
+			configItems.ordererMsps = append(configItems.ordererMsps, fabricMSPConfig.Name)
+		}
 		break
 
 	case channelConfig.ConsensusTypeKey:
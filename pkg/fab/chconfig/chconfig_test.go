@@ -37,6 +37,10 @@ func TestChannelConfigWithPeer(t *testing.T) {
 	if cfg.Name() != channelID {
 		t.Fatalf("Channel name error. Expecting %s, got %s ", channelID, cfg.Name())
 	}
+
+	if len(cfg.OrdererMsps()) != 1 || cfg.OrdererMsps()[0] != "OrdererMSP" {
+		t.Fatalf("Orderer MSPs error. Expecting [OrdererMSP], got %v", cfg.OrdererMsps())
+	}
 }
 
 func TestChannelConfigWithPeerError(t *testing.T) {
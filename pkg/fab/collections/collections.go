@@ -0,0 +1,171 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package collections loads private data collection configurations in the
+// collections_config.json format of the peer CLI, e.g.
+//
+//	[
+//	  {
+//	    "name": "collectionMarbles",
+//	    "policy": "OR('Org1MSP.member', 'Org2MSP.member')",
+//	    "requiredPeerCount": 0,
+//	    "maxPeerCount": 3,
+//	    "blockToLive": 1000000
+//	  }
+//	]
+package collections
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/policydsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+// nameRegExp matches the collection names accepted by Fabric
+var nameRegExp = regexp.MustCompile("^[A-Za-z0-9_-]+$")
+
+// Collection is a collection in the collections_config.json format
+type Collection struct {
+	Name              string `json:"name"`
+	Policy            string `json:"policy"`
+	RequiredPeerCount int32  `json:"requiredPeerCount"`
+	MaxPeerCount      int32  `json:"maxPeerCount"`
+	BlockToLive       uint64 `json:"blockToLive"`
+}
+
+// FromFile loads the collection configurations from the JSON file. If MSP IDs are given
+// (typically the MSPs of the channel), the member organisations of the collections must be
+// among them.
+func FromFile(path string, mspIDs ...string) ([]*common.CollectionConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading collection configuration failed")
+	}
+	return FromJSON(data, mspIDs...)
+}
+
+// FromJSON loads the collection configurations from JSON. If MSP IDs are given (typically
+// the MSPs of the channel), the member organisations of the collections must be among them.
+func FromJSON(data []byte, mspIDs ...string) ([]*common.CollectionConfig, error) {
+	var collections []Collection
+	if err := json.Unmarshal(data, &collections); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of collection configuration failed")
+	}
+	return New(collections, mspIDs...)
+}
+
+// New validates the collections and returns their configurations, which may be passed
+// to the instantiate and upgrade requests of a chaincode
+func New(collections []Collection, mspIDs ...string) ([]*common.CollectionConfig, error) {
+	names := make(map[string]bool)
+	var configs []*common.CollectionConfig
+	for i, collection := range collections {
+		if !nameRegExp.MatchString(collection.Name) {
+			return nil, errors.Errorf("collection %d has invalid name '%s'", i, collection.Name)
+		}
+		if names[collection.Name] {
+			return nil, errors.Errorf("collection '%s' is defined more than once", collection.Name)
+		}
+		names[collection.Name] = true
+
+		config, err := newCollectionConfig(collection, mspIDs)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("invalid collection '%s'", collection.Name))
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// MemberOrgs returns the sorted MSP IDs of the member organisations of the collection
+func MemberOrgs(config *common.CollectionConfig) ([]string, error) {
+	staticConfig := config.GetStaticCollectionConfig()
+	if staticConfig == nil {
+		return nil, errors.New("collection configuration is not static")
+	}
+	policy := staticConfig.GetMemberOrgsPolicy().GetSignaturePolicy()
+	if policy == nil {
+		return nil, errors.Errorf("collection '%s' has no member orgs policy", staticConfig.Name)
+	}
+
+	members := make(map[string]bool)
+	for _, identity := range policy.Identities {
+		if identity.PrincipalClassification != msp.MSPPrincipal_ROLE {
+			return nil, errors.Errorf("unsupported principal classification %s", identity.PrincipalClassification)
+		}
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(identity.Principal, role); err != nil {
+			return nil, errors.Wrap(err, "unmarshal of MSP role failed")
+		}
+		members[role.MspIdentifier] = true
+	}
+
+	var mspIDs []string
+	for mspID := range members {
+		mspIDs = append(mspIDs, mspID)
+	}
+	sort.Strings(mspIDs)
+	return mspIDs, nil
+}
+
+func newCollectionConfig(collection Collection, mspIDs []string) (*common.CollectionConfig, error) {
+	if collection.RequiredPeerCount < 0 {
+		return nil, errors.Errorf("required peer count %d is negative", collection.RequiredPeerCount)
+	}
+	if collection.MaxPeerCount < collection.RequiredPeerCount {
+		return nil, errors.Errorf("maximum peer count %d is less than required peer count %d", collection.MaxPeerCount, collection.RequiredPeerCount)
+	}
+
+	policy, err := policydsl.FromString(collection.Policy)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid policy")
+	}
+
+	config := &common.CollectionConfig{
+		Payload: &common.CollectionConfig_StaticCollectionConfig{
+			StaticCollectionConfig: &common.StaticCollectionConfig{
+				Name: collection.Name,
+				MemberOrgsPolicy: &common.CollectionPolicyConfig{
+					Payload: &common.CollectionPolicyConfig_SignaturePolicy{SignaturePolicy: policy},
+				},
+				RequiredPeerCount: collection.RequiredPeerCount,
+				MaximumPeerCount:  collection.MaxPeerCount,
+				BlockToLive:       collection.BlockToLive,
+			},
+		},
+	}
+
+	if len(mspIDs) > 0 {
+		members, err := MemberOrgs(config)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if !contains(mspIDs, member) {
+				return nil, errors.Errorf("member organisation [%s] is not a member of the channel", member)
+			}
+		}
+	}
+	return config, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package collections
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+)
+
+func TestFromFile(t *testing.T) {
+	configs, err := FromFile("testdata/collections_config.json", "Org1MSP", "Org2MSP")
+	if err != nil {
+		t.Fatalf("Failed to load collection configuration: %s", err)
+	}
+	if !assert.Len(t, configs, 2) {
+		t.FailNow()
+	}
+
+	marbles := configs[0].GetStaticCollectionConfig()
+	assert.Equal(t, "collectionMarbles", marbles.Name)
+	assert.Equal(t, int32(0), marbles.RequiredPeerCount)
+	assert.Equal(t, int32(3), marbles.MaximumPeerCount)
+	assert.Equal(t, uint64(1000000), marbles.BlockToLive)
+	expected := cauthdsl.SignedByAnyMember([]string{"Org1MSP", "Org2MSP"})
+	assert.True(t, proto.Equal(expected, marbles.MemberOrgsPolicy.GetSignaturePolicy()), "unexpected member orgs policy")
+
+	members, err := MemberOrgs(configs[1])
+	if err != nil {
+		t.Fatalf("Failed to get member orgs: %s", err)
+	}
+	assert.Equal(t, []string{"Org1MSP"}, members)
+
+	// Member orgs are not validated without MSP IDs
+	_, err = FromFile("testdata/collections_config.json")
+	assert.Nil(t, err)
+
+	_, err = FromFile("testdata/collections_config.json", "Org1MSP")
+	assert.NotNil(t, err, "expected error for member org which is not a member of the channel")
+
+	_, err = FromFile("testdata/missing.json")
+	assert.NotNil(t, err, "expected error for missing file")
+}
+
+func TestFromJSONErrors(t *testing.T) {
+	collections := map[string]string{
+		`{"name": "coll"}`: "unmarshal of collection configuration failed",
+		`[{"name": "", "policy": "OR('Org1MSP.member')"}]`:       "collection 0 has invalid name ''",
+		`[{"name": "coll.1", "policy": "OR('Org1MSP.member')"}]`: "invalid name 'coll.1'",
+		`[{"name": "coll", "policy": "OR('Org1MSP.member')", "maxPeerCount": 1},
+		  {"name": "coll", "policy": "OR('Org1MSP.member')", "maxPeerCount": 1}]`: "collection 'coll' is defined more than once",
+		`[{"name": "coll", "policy": "OR('Org1MSP.member'"}]`:                                             "invalid policy",
+		`[{"name": "coll", "policy": "OR('Org1MSP.member')", "requiredPeerCount": -1}]`:                   "required peer count -1 is negative",
+		`[{"name": "coll", "policy": "OR('Org1MSP.member')", "requiredPeerCount": 2, "maxPeerCount": 1}]`: "maximum peer count 1 is less than required peer count 2",
+		`[{"name": "coll", "policy": "OR('Org3MSP.member')", "maxPeerCount": 1}]`:                         "member organisation [Org3MSP] is not a member of the channel",
+	}
+
+	for collection, expected := range collections {
+		_, err := FromJSON([]byte(collection), "Org1MSP", "Org2MSP")
+		if assert.NotNil(t, err, "expected error for collection %s", collection) {
+			assert.Contains(t, err.Error(), expected)
+		}
+	}
+}
@@ -8,6 +8,7 @@ package comm
 
 import (
 	"context"
+	"crypto/tls"
 	"sync/atomic"
 
 	"github.com/pkg/errors"
@@ -24,14 +25,17 @@ import (
 
 var logger = logging.NewLogger("fabric_sdk_go")
 
-// StreamProvider creates a GRPC stream
-type StreamProvider func(conn *grpc.ClientConn) (grpc.ClientStream, error)
+// StreamProvider creates a GRPC stream. The returned cancel function
+// terminates the stream.
+type StreamProvider func(conn *grpc.ClientConn) (grpc.ClientStream, func(), error)
 
 // GRPCConnection manages the GRPC connection and client stream
 type GRPCConnection struct {
 	channelID   string
 	conn        *grpc.ClientConn
+	connector   *CachingConnector
 	stream      grpc.ClientStream
+	cancel      func()
 	context     fabcontext.Context
 	tlsCertHash []byte
 	done        int32
@@ -46,36 +50,50 @@ func NewConnection(ctx fabcontext.Context, channelID string, streamProvider Stre
 	params := defaultParams()
 	options.Apply(params, opts)
 
-	dialOpts, err := newDialOpts(ctx.Config(), url, params)
+	tlsConfig, err := newTLSConfig(ctx.Config(), url, params)
 	if err != nil {
 		return nil, err
 	}
+	creds := grpc.WithInsecure()
+	if tlsConfig != nil {
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
 
 	grpcctx := context.Background()
 	grpcctx, cancel := context.WithTimeout(grpcctx, params.connectTimeout)
 	defer cancel()
 
-	grpcconn, err := grpc.DialContext(grpcctx, urlutil.ToAddress(url), dialOpts...)
+	connector := ContextConnector(ctx)
+	key := NewConnectionKey(urlutil.ToAddress(url), params.certificate, clientCerts(tlsConfig), params.hostOverride, tlsConfig != nil,
+		DialOpts{FailFast: params.failFast, KeepAlive: params.keepAliveParams})
+	grpcconn, err := connector.DialContext(grpcctx, key, creds)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not connect to %s", url)
 	}
 
-	stream, err := streamProvider(grpcconn)
+	stream, cancel, err := streamProvider(grpcconn)
 	if err != nil {
-		if err := grpcconn.Close(); err != nil {
-			logger.Warnf("error closing GRPC connection: %s", err)
+		if cancel != nil {
+			cancel()
 		}
+		connector.ReleaseConn(grpcconn)
 		return nil, errors.Wrapf(err, "could not create stream to %s", url)
 	}
 
 	if stream == nil {
+		if cancel != nil {
+			cancel()
+		}
+		connector.ReleaseConn(grpcconn)
 		return nil, errors.New("unexpected nil stream received from provider")
 	}
 
 	return &GRPCConnection{
 		channelID:   channelID,
 		conn:        grpcconn,
+		connector:   connector,
 		stream:      stream,
+		cancel:      cancel,
 		context:     ctx,
 		tlsCertHash: comm.TLSCertHash(ctx.Config()),
 	}, nil
@@ -97,11 +115,12 @@ func (c *GRPCConnection) Close() {
 	if err := c.stream.CloseSend(); err != nil {
 		logger.Warnf("error closing GRPC stream: %s", err)
 	}
-
-	logger.Debugf("Closing connection....")
-	if err := c.conn.Close(); err != nil {
-		logger.Warnf("error closing GRPC connection: %s", err)
+	if c.cancel != nil {
+		c.cancel()
 	}
+
+	logger.Debugf("Releasing connection....")
+	c.connector.ReleaseConn(c.conn)
 }
 
 // Closed returns true if the connection has been closed
@@ -128,26 +147,19 @@ func (c *GRPCConnection) Context() fabcontext.Context {
 	return c.context
 }
 
-func newDialOpts(config core.Config, url string, params *params) ([]grpc.DialOption, error) {
-	var dialOpts []grpc.DialOption
-
-	if params.keepAliveParams.Time > 0 || params.keepAliveParams.Timeout > 0 {
-		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(params.keepAliveParams))
-	}
-
-	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.FailFast(params.failFast)))
-
-	if urlutil.IsTLSEnabled(url) {
-		tlsConfig, err := comm.TLSConfig(params.certificate, params.hostOverride, config)
-		if err != nil {
-			return nil, err
-		}
-		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
-		logger.Debugf("Creating a secure connection to [%s] with TLS HostOverride [%s]", url, params.hostOverride)
-	} else {
+// newTLSConfig returns the TLS config of the connection, or nil for an insecure connection
+func newTLSConfig(config core.Config, url string, params *params) (*tls.Config, error) {
+	if !urlutil.IsTLSEnabled(url) {
 		logger.Debugf("Creating an insecure connection [%s]", url)
-		dialOpts = append(dialOpts, grpc.WithInsecure())
+		return nil, nil
 	}
+	logger.Debugf("Creating a secure connection to [%s] with TLS HostOverride [%s]", url, params.hostOverride)
+	return comm.TLSConfig(params.certificate, params.hostOverride, config)
+}
 
-	return dialOpts, nil
+func clientCerts(tlsConfig *tls.Config) []tls.Certificate {
+	if tlsConfig == nil {
+		return nil
+	}
+	return tlsConfig.Certificates
 }
@@ -31,12 +31,14 @@ const (
 	peerURL     = "grpc://" + peerAddress
 )
 
-var testStream = func(grpcconn *grpc.ClientConn) (grpc.ClientStream, error) {
-	return pb.NewDeliverClient(grpcconn).Deliver(context.Background())
+var testStream = func(grpcconn *grpc.ClientConn) (grpc.ClientStream, func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := pb.NewDeliverClient(grpcconn).Deliver(ctx)
+	return stream, cancel, err
 }
 
-var invalidStream = func(grpcconn *grpc.ClientConn) (grpc.ClientStream, error) {
-	return nil, errors.New("simulated error creating stream")
+var invalidStream = func(grpcconn *grpc.ClientConn) (grpc.ClientStream, func(), error) {
+	return nil, nil, errors.New("simulated error creating stream")
 }
 
 func TestConnection(t *testing.T) {
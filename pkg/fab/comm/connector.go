@@ -0,0 +1,324 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+)
+
+const (
+	// DefaultConnIdleTime is the period after which an unused cached connection is closed
+	DefaultConnIdleTime = 30 * time.Second
+	// DefaultConnSweepTime is the interval at which the cache is checked for idle connections
+	DefaultConnSweepTime = 15 * time.Second
+)
+
+// ConnectionKey identifies a cached GRPC connection. Two dials share a connection
+// only if they target the same address with the same TLS credentials and dial options.
+type ConnectionKey struct {
+	Target         string
+	CertHash       string
+	ClientCertHash string
+	HostOverride   string
+	Secured        bool
+	DialOpts
+}
+
+// DialOpts are the dial options of a connection. They are part of the ConnectionKey and
+// are applied by DialContext.
+type DialOpts struct {
+	FailFast  bool
+	Blocking  bool
+	KeepAlive keepalive.ClientParameters
+}
+
+// NewConnectionKey returns the cache key for a connection to the given target. The key
+// includes the client TLS certificates of a secured connection, so that clients which
+// authenticate with different certificates never share a connection.
+func NewConnectionKey(target string, cert *x509.Certificate, clientCerts []tls.Certificate, hostOverride string, secured bool, dialOpts DialOpts) ConnectionKey {
+	key := ConnectionKey{
+		Target:       target,
+		HostOverride: hostOverride,
+		Secured:      secured,
+		DialOpts:     dialOpts,
+	}
+	if cert != nil {
+		h := sha256.Sum256(cert.Raw)
+		key.CertHash = hex.EncodeToString(h[:])
+	}
+	if secured && len(clientCerts) > 0 {
+		h := sha256.New()
+		for _, clientCert := range clientCerts {
+			for _, der := range clientCert.Certificate {
+				h.Write(der)
+			}
+		}
+		key.ClientCertHash = hex.EncodeToString(h.Sum(nil))
+	}
+	return key
+}
+
+// grpcOptions returns the GRPC dial options of the key
+func (o DialOpts) grpcOptions() []grpc.DialOption {
+	var opts []grpc.DialOption
+	if o.KeepAlive.Time > 0 || o.KeepAlive.Timeout > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(o.KeepAlive))
+	}
+	opts = append(opts, grpc.WithDefaultCallOptions(grpc.FailFast(o.FailFast)))
+	if o.Blocking {
+		opts = append(opts, grpc.WithBlock())
+	}
+	return opts
+}
+
+// ConnectorProvider is implemented by contexts which supply the connection cache of an SDK
+type ConnectorProvider interface {
+	Connector() *CachingConnector
+}
+
+// ContextConnector returns the connection cache supplied by the context, or nil if the
+// context doesn't supply one
+func ContextConnector(ctx interface{}) *CachingConnector {
+	if p, ok := ctx.(ConnectorProvider); ok {
+		return p.Connector()
+	}
+	return nil
+}
+
+type cachedConn struct {
+	key      ConnectionKey
+	conn     *grpc.ClientConn
+	refCount int
+	lastUsed time.Time
+	evicted  bool
+}
+
+// CachingConnector maintains a reference-counted cache of GRPC connections.
+// A connection is shared by all callers dialing the same ConnectionKey and is
+// closed once it has been unused for longer than the configured idle time.
+//
+// Each SDK instance has its own connector. A nil connector doesn't cache: every
+// dial creates a new connection, which is closed when it is released.
+type CachingConnector struct {
+	sweepTime time.Duration
+	idleTime  time.Duration
+
+	lock    sync.Mutex
+	byKey   map[ConnectionKey]*cachedConn
+	byConn  map[*grpc.ClientConn]*cachedConn
+	janitor chan struct{}
+	closed  bool
+}
+
+// NewCachingConnector creates a connection cache. Idle connections are checked for
+// every sweepTime and closed when they have not been used for idleTime.
+func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration) *CachingConnector {
+	return &CachingConnector{
+		sweepTime: sweepTime,
+		idleTime:  idleTime,
+		byKey:     make(map[ConnectionKey]*cachedConn),
+		byConn:    make(map[*grpc.ClientConn]*cachedConn),
+	}
+}
+
+// DialContext returns a cached connection for the given key, establishing a new
+// connection if none exists or the cached one is no longer healthy. The dial options
+// of the key are applied, so opts should only supply the transport credentials. Callers
+// must hand the connection back with ReleaseConn rather than closing it.
+func (cc *CachingConnector) DialContext(ctx context.Context, key ConnectionKey, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append(key.grpcOptions(), opts...)
+	if cc == nil {
+		return grpc.DialContext(ctx, key.Target, opts...)
+	}
+
+	cc.lock.Lock()
+	if cc.closed {
+		cc.lock.Unlock()
+		return nil, errors.New("connector is closed")
+	}
+
+	if c, ok := cc.byKey[key]; ok {
+		if healthy(c.conn) {
+			c.refCount++
+			c.lastUsed = time.Now()
+			cc.lock.Unlock()
+			logger.Debugf("Reusing cached connection to [%s]", key.Target)
+			return c.conn, nil
+		}
+		logger.Debugf("Cached connection to [%s] is in state [%s] - evicting", key.Target, c.conn.GetState())
+		cc.evict(c)
+	}
+	cc.lock.Unlock()
+
+	// Dial outside of the lock so that a slow target doesn't block other callers
+	conn, err := grpc.DialContext(ctx, key.Target, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	if cc.closed {
+		closeConn(conn)
+		return nil, errors.New("connector is closed")
+	}
+
+	if c, ok := cc.byKey[key]; ok && healthy(c.conn) {
+		// Another caller established the connection while we were dialing
+		closeConn(conn)
+		c.refCount++
+		c.lastUsed = time.Now()
+		return c.conn, nil
+	}
+
+	c := &cachedConn{key: key, conn: conn, refCount: 1, lastUsed: time.Now()}
+	cc.byKey[key] = c
+	cc.byConn[conn] = c
+	cc.ensureJanitor()
+
+	logger.Debugf("Cached new connection to [%s]", key.Target)
+	return conn, nil
+}
+
+// ReleaseConn hands a connection obtained from DialContext back to the cache.
+// The connection stays open for reuse until it becomes idle.
+func (cc *CachingConnector) ReleaseConn(conn *grpc.ClientConn) {
+	if cc == nil {
+		closeConn(conn)
+		return
+	}
+
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	c, ok := cc.byConn[conn]
+	if !ok {
+		logger.Debugf("Connection is not cached - closing")
+		closeConn(conn)
+		return
+	}
+
+	c.refCount--
+	c.lastUsed = time.Now()
+	if c.refCount <= 0 && (c.evicted || cc.closed) {
+		cc.remove(c)
+	}
+}
+
+// Close stops the idle sweeper and closes all cached connections that are not in use.
+// Connections that are still in use are closed when they are released.
+func (cc *CachingConnector) Close() {
+	if cc == nil {
+		return
+	}
+
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	if cc.closed {
+		return
+	}
+	cc.closed = true
+
+	if cc.janitor != nil {
+		close(cc.janitor)
+		cc.janitor = nil
+	}
+
+	for _, c := range cc.byConn {
+		delete(cc.byKey, c.key)
+		if c.refCount <= 0 {
+			cc.remove(c)
+		}
+	}
+	logger.Debugf("Connector closed")
+}
+
+// evict removes the connection from the key index so that it is no longer handed out.
+// It is closed immediately if unused, or otherwise once it has been released.
+func (cc *CachingConnector) evict(c *cachedConn) {
+	c.evicted = true
+	if cc.byKey[c.key] == c {
+		delete(cc.byKey, c.key)
+	}
+	if c.refCount <= 0 {
+		cc.remove(c)
+	}
+}
+
+func (cc *CachingConnector) remove(c *cachedConn) {
+	if cc.byKey[c.key] == c {
+		delete(cc.byKey, c.key)
+	}
+	delete(cc.byConn, c.conn)
+	closeConn(c.conn)
+}
+
+func (cc *CachingConnector) ensureJanitor() {
+	if cc.janitor != nil || cc.sweepTime <= 0 {
+		return
+	}
+	cc.janitor = make(chan struct{})
+	go cc.sweep(cc.janitor, cc.sweepTime)
+}
+
+func (cc *CachingConnector) sweep(done chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			cc.removeIdle()
+		}
+	}
+}
+
+func (cc *CachingConnector) removeIdle() {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	now := time.Now()
+	for _, c := range cc.byConn {
+		if c.refCount > 0 {
+			continue
+		}
+		if now.Sub(c.lastUsed) >= cc.idleTime || !healthy(c.conn) {
+			logger.Debugf("Closing idle connection to [%s]", c.key.Target)
+			cc.remove(c)
+		}
+	}
+}
+
+func healthy(conn *grpc.ClientConn) bool {
+	switch conn.GetState() {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return false
+	default:
+		return true
+	}
+}
+
+func closeConn(conn *grpc.ClientConn) {
+	if err := conn.Close(); err != nil {
+		logger.Debugf("error closing GRPC connection: %s", err)
+	}
+}
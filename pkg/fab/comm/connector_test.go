@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+
+	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/context"
+)
+
+func TestConnectorShared(t *testing.T) {
+	connector := NewCachingConnector(time.Second, time.Minute)
+	defer connector.Close()
+
+	key := NewConnectionKey(peerAddress, nil, nil, "", false, DialOpts{FailFast: true})
+
+	conn1 := dial(t, connector, key)
+	conn2 := dial(t, connector, key)
+	if conn1 != conn2 {
+		t.Fatalf("expected connections with the same key to be shared")
+	}
+
+	otherKey := NewConnectionKey(peerAddress, nil, nil, "peer0.org1.example.com", false, DialOpts{FailFast: true})
+	conn3 := dial(t, connector, otherKey)
+	if conn3 == conn1 {
+		t.Fatalf("expected connections with different keys not to be shared")
+	}
+
+	connector.ReleaseConn(conn1)
+	connector.ReleaseConn(conn2)
+	connector.ReleaseConn(conn3)
+
+	if conn1.GetState() == connectivity.Shutdown {
+		t.Fatalf("expected released connection to remain open until idle")
+	}
+}
+
+func TestConnectorIdle(t *testing.T) {
+	connector := NewCachingConnector(50*time.Millisecond, 100*time.Millisecond)
+	defer connector.Close()
+
+	key := NewConnectionKey(peerAddress, nil, nil, "", false, DialOpts{FailFast: true})
+
+	conn := dial(t, connector, key)
+	time.Sleep(300 * time.Millisecond)
+	if conn.GetState() == connectivity.Shutdown {
+		t.Fatalf("expected connection in use not to be closed")
+	}
+
+	connector.ReleaseConn(conn)
+	time.Sleep(300 * time.Millisecond)
+	if conn.GetState() != connectivity.Shutdown {
+		t.Fatalf("expected idle connection to be closed")
+	}
+
+	conn2 := dial(t, connector, key)
+	if conn2 == conn {
+		t.Fatalf("expected a new connection after the idle one was closed")
+	}
+	connector.ReleaseConn(conn2)
+}
+
+func TestConnectorClose(t *testing.T) {
+	connector := NewCachingConnector(time.Second, time.Minute)
+
+	key := NewConnectionKey(peerAddress, nil, nil, "", false, DialOpts{FailFast: true})
+
+	idleConn := dial(t, connector, key)
+	connector.ReleaseConn(idleConn)
+
+	otherKey := NewConnectionKey(peerAddress, nil, nil, "", false, DialOpts{})
+	busyConn := dial(t, connector, otherKey)
+
+	connector.Close()
+
+	if idleConn.GetState() != connectivity.Shutdown {
+		t.Fatalf("expected idle connection to be closed")
+	}
+	if busyConn.GetState() == connectivity.Shutdown {
+		t.Fatalf("expected connection in use to stay open until released")
+	}
+
+	connector.ReleaseConn(busyConn)
+	if busyConn.GetState() != connectivity.Shutdown {
+		t.Fatalf("expected connection to be closed on release")
+	}
+
+	if _, err := connector.DialContext(context.Background(), key, grpc.WithInsecure()); err == nil {
+		t.Fatalf("expected error dialing a closed connector")
+	}
+
+	// Calling close again should be ignored
+	connector.Close()
+}
+
+func TestConnectionKey(t *testing.T) {
+	key := NewConnectionKey(peerAddress, nil, nil, "", true, DialOpts{FailFast: true})
+	if key != NewConnectionKey(peerAddress, nil, nil, "", true, DialOpts{FailFast: true}) {
+		t.Fatalf("expected equal keys for the same settings")
+	}
+
+	// clients authenticating with different certificates must not share connections
+	client1 := NewConnectionKey(peerAddress, nil, clientCert("client1"), "", true, DialOpts{FailFast: true})
+	client2 := NewConnectionKey(peerAddress, nil, clientCert("client2"), "", true, DialOpts{FailFast: true})
+	if client1 == client2 || client1 == key {
+		t.Fatalf("expected different keys for different client certificates")
+	}
+
+	blocking := NewConnectionKey(peerAddress, nil, nil, "", true, DialOpts{FailFast: true, Blocking: true})
+	if blocking == key {
+		t.Fatalf("expected different keys for different dial options")
+	}
+	keepAlive := NewConnectionKey(peerAddress, nil, nil, "", true, DialOpts{FailFast: true, KeepAlive: keepalive.ClientParameters{Time: time.Minute}})
+	if keepAlive == key {
+		t.Fatalf("expected different keys for different keep-alive parameters")
+	}
+}
+
+func TestNilConnector(t *testing.T) {
+	var connector *CachingConnector
+
+	key := NewConnectionKey(peerAddress, nil, nil, "", false, DialOpts{FailFast: true})
+	conn1 := dial(t, connector, key)
+	conn2 := dial(t, connector, key)
+	if conn1 == conn2 {
+		t.Fatalf("expected a nil connector not to share connections")
+	}
+
+	connector.ReleaseConn(conn1)
+	if conn1.GetState() != connectivity.Shutdown {
+		t.Fatalf("expected connection to be closed on release")
+	}
+	connector.ReleaseConn(conn2)
+	connector.Close()
+}
+
+func TestContextConnector(t *testing.T) {
+	connector := NewCachingConnector(time.Second, time.Minute)
+	defer connector.Close()
+
+	if ContextConnector(newMockContext()) != nil {
+		t.Fatalf("expected no connector for a context which doesn't supply one")
+	}
+	ctx := &connectorContext{Context: newMockContext(), connector: connector}
+	if ContextConnector(ctx) != connector {
+		t.Fatalf("expected the connector of the context")
+	}
+}
+
+func clientCert(der string) []tls.Certificate {
+	return []tls.Certificate{{Certificate: [][]byte{[]byte(der)}}}
+}
+
+// connectorContext supplies a connection cache
+type connectorContext struct {
+	fabcontext.Context
+	connector *CachingConnector
+}
+
+func (c *connectorContext) Connector() *CachingConnector {
+	return c.connector
+}
+
+func dial(t *testing.T, connector *CachingConnector, key ConnectionKey) *grpc.ClientConn {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	conn, err := connector.DialContext(ctx, key, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("error dialing [%s]: %s", key.Target, err)
+	}
+	return conn
+}
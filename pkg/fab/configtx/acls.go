@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+// aclsProto is the ACLs message of fabric's peer/resources.proto, which is stored in the
+// application group. The message is not part of the protos vendored by the SDK.
+type aclsProto struct {
+	Acls map[string]*apiResourceProto `protobuf:"bytes,1,rep,name=acls" json:"acls,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *aclsProto) Reset()         { *m = aclsProto{} }
+func (m *aclsProto) String() string { return proto.CompactTextString(m) }
+func (*aclsProto) ProtoMessage()    {}
+
+// apiResourceProto is the APIResource message of fabric's peer/resources.proto
+type apiResourceProto struct {
+	PolicyRef string `protobuf:"bytes,1,opt,name=policy_ref,json=policyRef" json:"policy_ref,omitempty"`
+}
+
+func (m *apiResourceProto) Reset()         { *m = apiResourceProto{} }
+func (m *apiResourceProto) String() string { return proto.CompactTextString(m) }
+func (*apiResourceProto) ProtoMessage()    {}
@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// signedUpdateVersion is the version of the signed update bundle format
+const signedUpdateVersion = 1
+
+// SignedUpdate is a config update together with the signatures collected so far in a portable
+// format. It is passed between the admins of the organisations, who sign it with their own SDK
+// instance, until the signatures satisfy the modification policies of the channel.
+type SignedUpdate struct {
+	Version      int                `json:"version"`
+	ChannelID    string             `json:"channelId"`
+	Description  string             `json:"description,omitempty"`
+	Created      time.Time          `json:"created"`
+	ConfigUpdate []byte             `json:"configUpdate"`
+	Signatures   []*UpdateSignature `json:"signatures,omitempty"`
+}
+
+// UpdateSignature is the marshalled ConfigSignature of a single admin
+type UpdateSignature struct {
+	MSPID     string    `json:"mspId"`
+	Signed    time.Time `json:"signed"`
+	Signature []byte    `json:"signature"`
+}
+
+// NewSignedUpdate returns a bundle without signatures for the marshalled config update
+func NewSignedUpdate(configUpdate []byte, description string) (*SignedUpdate, error) {
+	update := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdate, update); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of config update failed")
+	}
+	if update.ChannelId == "" {
+		return nil, errors.New("config update does not contain a channel ID")
+	}
+
+	return &SignedUpdate{
+		Version:      signedUpdateVersion,
+		ChannelID:    update.ChannelId,
+		Description:  description,
+		Created:      time.Now().UTC(),
+		ConfigUpdate: configUpdate,
+	}, nil
+}
+
+// ParseSignedUpdate parses a signed update bundle from its portable format
+func ParseSignedUpdate(b []byte) (*SignedUpdate, error) {
+	u := &SignedUpdate{}
+	if err := json.Unmarshal(b, u); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of signed update failed")
+	}
+	if u.Version != signedUpdateVersion {
+		return nil, errors.Errorf("unsupported signed update version: %d", u.Version)
+	}
+
+	update, err := u.Update()
+	if err != nil {
+		return nil, err
+	}
+	if update.ChannelId != u.ChannelID {
+		return nil, errors.Errorf("config update is for channel [%s] but bundle is for channel [%s]", update.ChannelId, u.ChannelID)
+	}
+	return u, nil
+}
+
+// Bytes returns the signed update in its portable (JSON) format
+func (u *SignedUpdate) Bytes() ([]byte, error) {
+	return json.MarshalIndent(u, "", "  ")
+}
+
+// Update returns the config update of the bundle
+func (u *SignedUpdate) Update() (*common.ConfigUpdate, error) {
+	update := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(u.ConfigUpdate, update); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of config update failed")
+	}
+	return update, nil
+}
+
+// AddSignature adds the signature of an admin to the bundle. A signature by an identity which
+// already signed the bundle is rejected.
+func (u *SignedUpdate) AddSignature(signature *common.ConfigSignature) error {
+	s, err := signatureSigner(signature)
+	if err != nil {
+		return err
+	}
+
+	signatures, err := u.ConfigSignatures()
+	if err != nil {
+		return err
+	}
+	for _, existing := range signatures {
+		existingSigner, err := signatureSigner(existing)
+		if err != nil {
+			return err
+		}
+		if existingSigner.mspID == s.mspID && bytes.Equal(existingSigner.cert, s.cert) {
+			return errors.Errorf("update has already been signed by this identity of [%s]", s.mspID)
+		}
+	}
+
+	signatureBytes, err := proto.Marshal(signature)
+	if err != nil {
+		return errors.Wrap(err, "marshal of config signature failed")
+	}
+	u.Signatures = append(u.Signatures, &UpdateSignature{
+		MSPID:     s.mspID,
+		Signed:    time.Now().UTC(),
+		Signature: signatureBytes,
+	})
+	return nil
+}
+
+// ConfigSignatures returns the signatures collected in the bundle
+func (u *SignedUpdate) ConfigSignatures() ([]*common.ConfigSignature, error) {
+	var signatures []*common.ConfigSignature
+	for _, s := range u.Signatures {
+		signature := &common.ConfigSignature{}
+		if err := proto.Unmarshal(s.Signature, signature); err != nil {
+			return nil, errors.Wrapf(err, "unmarshal of config signature of [%s] failed", s.MSPID)
+		}
+		signatures = append(signatures, signature)
+	}
+	return signatures, nil
+}
+
+// Evaluate checks whether the signatures of the bundle satisfy the modification policies of the
+// current channel configuration (see Config.EvaluateSignatures)
+func (u *SignedUpdate) Evaluate(config *Config) (*SignatureStatus, error) {
+	update, err := u.Update()
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := u.ConfigSignatures()
+	if err != nil {
+		return nil, err
+	}
+	return config.EvaluateSignatures(update, signatures)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestSignedUpdate(t *testing.T) {
+	original := newPolicyTestConfig(t)
+	updated := original.Clone()
+	if err := updated.RemoveApplicationOrg("Org2MSP"); err != nil {
+		t.Fatalf("Failed to remove org: %s", err)
+	}
+	update, err := ComputeUpdate(original, updated)
+	if err != nil {
+		t.Fatalf("Failed to compute update: %s", err)
+	}
+
+	bundle, err := NewSignedUpdate(marshalOrFail(t, update), "remove Org2MSP")
+	if err != nil {
+		t.Fatalf("Failed to create signed update: %s", err)
+	}
+	assert.Equal(t, testChannelID, bundle.ChannelID)
+
+	// The first admin signs and passes the bundle on
+	if err := bundle.AddSignature(newTestSignature(t, "Org1MSP", adminCert(t, org1MSPDir))); err != nil {
+		t.Fatalf("Failed to add signature: %s", err)
+	}
+	assert.NotNil(t, bundle.AddSignature(newTestSignature(t, "Org1MSP", adminCert(t, org1MSPDir))), "expected error for duplicate signer")
+
+	b, err := bundle.Bytes()
+	if err != nil {
+		t.Fatalf("Failed to marshal signed update: %s", err)
+	}
+	bundle, err = ParseSignedUpdate(b)
+	if err != nil {
+		t.Fatalf("Failed to parse signed update: %s", err)
+	}
+	assert.Equal(t, "remove Org2MSP", bundle.Description)
+
+	status, err := bundle.Evaluate(original)
+	if err != nil {
+		t.Fatalf("Failed to evaluate signed update: %s", err)
+	}
+	assert.False(t, status.Satisfied)
+
+	// The second admin adds a signature, which satisfies the majority
+	if err := bundle.AddSignature(newTestSignature(t, "Org2MSP", adminCert(t, org2MSPDir))); err != nil {
+		t.Fatalf("Failed to add signature: %s", err)
+	}
+	status, err = bundle.Evaluate(original)
+	if err != nil {
+		t.Fatalf("Failed to evaluate signed update: %s", err)
+	}
+	assert.True(t, status.Satisfied)
+
+	signatures, err := bundle.ConfigSignatures()
+	if err != nil {
+		t.Fatalf("Failed to get signatures: %s", err)
+	}
+	assert.Len(t, signatures, 2)
+	assert.Equal(t, "Org2MSP", bundle.Signatures[1].MSPID)
+}
+
+func TestSignedUpdateErrors(t *testing.T) {
+	_, err := NewSignedUpdate([]byte("invalid"), "")
+	assert.NotNil(t, err, "expected error for invalid config update")
+
+	_, err = NewSignedUpdate(marshalOrFail(t, &common.ConfigUpdate{}), "")
+	assert.NotNil(t, err, "expected error for missing channel ID")
+
+	_, err = ParseSignedUpdate([]byte(`{"version": 2}`))
+	assert.NotNil(t, err, "expected error for unsupported version")
+
+	configUpdate, err := proto.Marshal(&common.ConfigUpdate{ChannelId: "otherchannel"})
+	if err != nil {
+		t.Fatalf("Failed to marshal config update: %s", err)
+	}
+	bundle := &SignedUpdate{Version: signedUpdateVersion, ChannelID: testChannelID, ConfigUpdate: configUpdate}
+	b, err := bundle.Bytes()
+	if err != nil {
+		t.Fatalf("Failed to marshal signed update: %s", err)
+	}
+	_, err = ParseSignedUpdate(b)
+	assert.NotNil(t, err, "expected error for mismatched channel")
+
+	assert.NotNil(t, bundle.AddSignature(&common.ConfigSignature{SignatureHeader: []byte("invalid")}), "expected error for invalid signature")
+}
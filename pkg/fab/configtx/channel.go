@@ -0,0 +1,128 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// ChannelProfile describes a new application channel like a channel profile of configtx.yaml
+type ChannelProfile struct {
+	// Consortium of the ordering service which the channel is created for
+	Consortium string
+	// Member organisations of the channel, which must be members of the consortium
+	Orgs []*OrgProfile
+	// Policies of the application group. The policies generated by configtxgen are used
+	// if none are given: any reader, any writer and a majority of admins.
+	Policies map[string]*common.Policy
+	// Application capabilities, e.g. "V1_1"
+	Capabilities []string
+}
+
+// OrgProfile describes a member organisation of a new channel
+type OrgProfile struct {
+	// Name of the organisation in the consortium (defaults to the MSP ID)
+	Name string
+	// MSP configuration of the organisation, which provides the MSP ID if no name is given
+	MSPConfig *mspproto.MSPConfig
+}
+
+// DefaultApplicationPolicies returns the application policies generated by configtxgen
+func DefaultApplicationPolicies() map[string]*common.Policy {
+	return map[string]*common.Policy{
+		channelConfig.ReadersPolicyKey: implicitMetaPolicy(channelConfig.ReadersPolicyKey, common.ImplicitMetaPolicy_ANY),
+		channelConfig.WritersPolicyKey: implicitMetaPolicy(channelConfig.WritersPolicyKey, common.ImplicitMetaPolicy_ANY),
+		channelConfig.AdminsPolicyKey:  implicitMetaPolicy(channelConfig.AdminsPolicyKey, common.ImplicitMetaPolicy_MAJORITY),
+	}
+}
+
+// NewChannelCreationUpdate returns the config update which creates the channel from the profile.
+// This is the config update contained in the channel creation transaction generated by configtxgen.
+// The orderer takes the definitions of the organisations from the consortium, so the update only
+// references the organisations with empty groups and anchor peers have to be set with a config
+// update once the channel exists.
+func NewChannelCreationUpdate(channelID string, profile *ChannelProfile) (*common.ConfigUpdate, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+	if profile == nil || profile.Consortium == "" {
+		return nil, errors.New("profile must provide consortium")
+	}
+	if len(profile.Orgs) == 0 {
+		return nil, errors.New("profile must provide at least one organisation")
+	}
+
+	readSet := newConfigGroup()
+	if err := setGroupValue(readSet, &common.Consortium{Name: profile.Consortium}, channelConfig.ConsortiumKey); err != nil {
+		return nil, err
+	}
+	readSet.Values[channelConfig.ConsortiumKey].ModPolicy = ""
+	readApplication := newConfigGroup()
+	readSet.Groups[ApplicationGroupKey] = readApplication
+
+	application := newConfigGroup()
+	application.Version = 1
+	application.ModPolicy = channelConfig.AdminsPolicyKey
+
+	for _, org := range profile.Orgs {
+		if org == nil {
+			return nil, errors.New("organisation profile is required")
+		}
+		name := org.Name
+		if name == "" {
+			var err error
+			if name, err = MSPID(org.MSPConfig); err != nil {
+				return nil, errors.WithMessage(err, "organisation name or MSP config is required")
+			}
+		}
+		if _, ok := application.Groups[name]; ok {
+			return nil, errors.Errorf("organisation [%s] is defined more than once", name)
+		}
+		// The groups of the organisations are at version 0 in the channel template of the orderer
+		readApplication.Groups[name] = newConfigGroup()
+		application.Groups[name] = newConfigGroup()
+	}
+
+	policies := profile.Policies
+	if len(policies) == 0 {
+		policies = DefaultApplicationPolicies()
+	}
+	for name, policy := range policies {
+		if policy == nil {
+			return nil, errors.Errorf("policy [%s] is required", name)
+		}
+		application.Policies[name] = &common.ConfigPolicy{Policy: policy, ModPolicy: channelConfig.AdminsPolicyKey}
+	}
+
+	if len(profile.Capabilities) > 0 {
+		capabilities := &common.Capabilities{Capabilities: make(map[string]*common.Capability)}
+		for _, capability := range profile.Capabilities {
+			capabilities.Capabilities[capability] = &common.Capability{}
+		}
+		if err := setGroupValue(application, capabilities, channelConfig.CapabilitiesKey); err != nil {
+			return nil, err
+		}
+	}
+
+	writeSet := proto.Clone(readSet).(*common.ConfigGroup)
+	writeSet.Groups[ApplicationGroupKey] = application
+
+	return &common.ConfigUpdate{ChannelId: channelID, ReadSet: readSet, WriteSet: writeSet}, nil
+}
+
+func implicitMetaPolicy(subPolicy string, rule common.ImplicitMetaPolicy_Rule) *common.Policy {
+	return &common.Policy{
+		Type:  int32(common.Policy_IMPLICIT_META),
+		Value: utils.MarshalOrPanic(&common.ImplicitMetaPolicy{SubPolicy: subPolicy, Rule: rule}),
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestNewChannelCreationUpdate(t *testing.T) {
+	org1MSP, err := NewMSPConfigFromDir("Org1MSP", org1MSPDir)
+	if err != nil {
+		t.Fatalf("Failed to read MSP directory: %s", err)
+	}
+	org2MSP, err := NewMSPConfigFromDir("Org2MSP", org2MSPDir)
+	if err != nil {
+		t.Fatalf("Failed to read MSP directory: %s", err)
+	}
+
+	profile := &ChannelProfile{
+		Consortium:   "SampleConsortium",
+		Orgs:         []*OrgProfile{{MSPConfig: org1MSP}, {Name: "Org2", MSPConfig: org2MSP}},
+		Capabilities: []string{"V1_1"},
+	}
+	update, err := NewChannelCreationUpdate("newchannel", profile)
+	if err != nil {
+		t.Fatalf("Failed to create channel creation update: %s", err)
+	}
+	assert.Equal(t, "newchannel", update.ChannelId)
+
+	// The read set references the consortium and its member organisations
+	consortium := &common.Consortium{}
+	if err := proto.Unmarshal(update.ReadSet.Values[channelConfig.ConsortiumKey].Value, consortium); err != nil {
+		t.Fatalf("Failed to unmarshal consortium: %s", err)
+	}
+	assert.Equal(t, "SampleConsortium", consortium.Name)
+	readApplication := update.ReadSet.Groups[ApplicationGroupKey]
+	if !assert.NotNil(t, readApplication) {
+		t.FailNow()
+	}
+	assert.Equal(t, uint64(0), readApplication.Version)
+	assert.Len(t, readApplication.Groups, 2)
+	assert.NotNil(t, readApplication.Groups["Org2"])
+
+	// The write set adds the application group
+	assert.Equal(t, update.ReadSet.Values, update.WriteSet.Values)
+	application := update.WriteSet.Groups[ApplicationGroupKey]
+	if !assert.NotNil(t, application) {
+		t.FailNow()
+	}
+	assert.Equal(t, uint64(1), application.Version)
+	assert.Equal(t, channelConfig.AdminsPolicyKey, application.ModPolicy)
+	assert.Len(t, application.Policies, 3)
+	assert.Equal(t, int32(common.Policy_IMPLICIT_META), application.Policies[channelConfig.AdminsPolicyKey].Policy.Type)
+
+	// The organisations are taken from the consortium, so their groups are empty in both sets
+	assert.Len(t, application.Groups, 2)
+	for name, org := range application.Groups {
+		assert.Equal(t, readApplication.Groups[name], org)
+		assert.Equal(t, uint64(0), org.Version)
+		assert.Empty(t, org.Values)
+		assert.Empty(t, org.Policies)
+	}
+
+	capabilities := &common.Capabilities{}
+	if err := proto.Unmarshal(application.Values[channelConfig.CapabilitiesKey].Value, capabilities); err != nil {
+		t.Fatalf("Failed to unmarshal capabilities: %s", err)
+	}
+	assert.Contains(t, capabilities.Capabilities, "V1_1")
+
+	// Custom policies replace the default policies
+	profile.Policies = map[string]*common.Policy{
+		channelConfig.AdminsPolicyKey: {Type: int32(common.Policy_SIGNATURE), Value: marshalOrFail(t, cauthdsl.SignedByMspAdmin("Org1MSP"))},
+	}
+	update, err = NewChannelCreationUpdate("newchannel", profile)
+	if err != nil {
+		t.Fatalf("Failed to create channel creation update: %s", err)
+	}
+	assert.Len(t, update.WriteSet.Groups[ApplicationGroupKey].Policies, 1)
+}
+
+func TestNewChannelCreationUpdateErrors(t *testing.T) {
+	org1MSP, err := NewMSPConfigFromDir("Org1MSP", org1MSPDir)
+	if err != nil {
+		t.Fatalf("Failed to read MSP directory: %s", err)
+	}
+
+	_, err = NewChannelCreationUpdate("", &ChannelProfile{Consortium: "SampleConsortium", Orgs: []*OrgProfile{{MSPConfig: org1MSP}}})
+	assert.NotNil(t, err, "expected error for missing channel ID")
+
+	_, err = NewChannelCreationUpdate("newchannel", &ChannelProfile{Orgs: []*OrgProfile{{MSPConfig: org1MSP}}})
+	assert.NotNil(t, err, "expected error for missing consortium")
+
+	_, err = NewChannelCreationUpdate("newchannel", &ChannelProfile{Consortium: "SampleConsortium"})
+	assert.NotNil(t, err, "expected error for missing organisations")
+
+	_, err = NewChannelCreationUpdate("newchannel", &ChannelProfile{Consortium: "SampleConsortium", Orgs: []*OrgProfile{{}}})
+	assert.NotNil(t, err, "expected error for missing organisation name and MSP config")
+
+	_, err = NewChannelCreationUpdate("newchannel", &ChannelProfile{Consortium: "SampleConsortium", Orgs: []*OrgProfile{{MSPConfig: org1MSP}, {MSPConfig: org1MSP}}})
+	assert.NotNil(t, err, "expected error for duplicate organisation")
+}
@@ -0,0 +1,316 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package configtx provides a modifiable, typed view of a channel configuration and
+// computes the config update which applies the modifications to the channel.
+package configtx
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	// ApplicationGroupKey is the group name for the application config
+	ApplicationGroupKey = "Application"
+
+	// ACLsKey is the key name for the ACLs ConfigValue of the application group
+	ACLsKey = "ACLs"
+)
+
+// Config is a modifiable copy of the configuration of a channel
+type Config struct {
+	channelID string
+	config    *common.Config
+}
+
+// New returns a modifiable copy of the given channel configuration
+func New(channelID string, config *common.Config) (*Config, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+	if config == nil || config.ChannelGroup == nil {
+		return nil, errors.New("config does not contain a channel group")
+	}
+
+	return &Config{channelID: channelID, config: proto.Clone(config).(*common.Config)}, nil
+}
+
+// FromConfigEnvelope returns a modifiable copy of the configuration contained in the envelope,
+// e.g. the configuration returned by QueryConfigBlock
+func FromConfigEnvelope(channelID string, configEnvelope *common.ConfigEnvelope) (*Config, error) {
+	if configEnvelope == nil {
+		return nil, errors.New("config envelope is required")
+	}
+	return New(channelID, configEnvelope.Config)
+}
+
+// ChannelID returns the ID of the channel
+func (c *Config) ChannelID() string {
+	return c.channelID
+}
+
+// Config returns the underlying configuration
+func (c *Config) Config() *common.Config {
+	return c.config
+}
+
+// Clone returns a copy of the configuration which may be modified independently
+func (c *Config) Clone() *Config {
+	return &Config{channelID: c.channelID, config: proto.Clone(c.config).(*common.Config)}
+}
+
+// BatchSize returns the batch size of the orderer
+func (c *Config) BatchSize() (*orderer.BatchSize, error) {
+	batchSize := &orderer.BatchSize{}
+	if err := c.getValue(batchSize, channelConfig.BatchSizeKey, channelConfig.OrdererGroupKey); err != nil {
+		return nil, err
+	}
+	return batchSize, nil
+}
+
+// SetBatchSize sets the batch size of the orderer
+func (c *Config) SetBatchSize(batchSize *orderer.BatchSize) error {
+	if batchSize == nil || batchSize.MaxMessageCount == 0 || batchSize.AbsoluteMaxBytes == 0 || batchSize.PreferredMaxBytes == 0 {
+		return errors.New("max message count, absolute max bytes and preferred max bytes are required")
+	}
+	if batchSize.PreferredMaxBytes > batchSize.AbsoluteMaxBytes {
+		return errors.New("preferred max bytes must not exceed absolute max bytes")
+	}
+	return c.setValue(batchSize, channelConfig.BatchSizeKey, channelConfig.OrdererGroupKey)
+}
+
+// BatchTimeout returns the batch timeout of the orderer
+func (c *Config) BatchTimeout() (time.Duration, error) {
+	batchTimeout := &orderer.BatchTimeout{}
+	if err := c.getValue(batchTimeout, channelConfig.BatchTimeoutKey, channelConfig.OrdererGroupKey); err != nil {
+		return 0, err
+	}
+	timeout, err := time.ParseDuration(batchTimeout.Timeout)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid batch timeout")
+	}
+	return timeout, nil
+}
+
+// SetBatchTimeout sets the batch timeout of the orderer
+func (c *Config) SetBatchTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return errors.New("batch timeout must be positive")
+	}
+	return c.setValue(&orderer.BatchTimeout{Timeout: timeout.String()}, channelConfig.BatchTimeoutKey, channelConfig.OrdererGroupKey)
+}
+
+// ApplicationOrgs returns the names of the organisations that are members of the channel
+func (c *Config) ApplicationOrgs() []string {
+	application := c.group(ApplicationGroupKey)
+	if application == nil {
+		return nil
+	}
+
+	var orgs []string
+	for name := range application.Groups {
+		orgs = append(orgs, name)
+	}
+	sort.Strings(orgs)
+	return orgs
+}
+
+// ApplicationOrg returns the configuration group of the organisation or nil if the
+// organisation is not a member of the channel
+func (c *Config) ApplicationOrg(org string) *common.ConfigGroup {
+	return c.group(ApplicationGroupKey, org)
+}
+
+// SetApplicationOrg adds the organisation to the channel or replaces its configuration group
+func (c *Config) SetApplicationOrg(org string, group *common.ConfigGroup) error {
+	if org == "" || group == nil {
+		return errors.New("organisation name and config group are required")
+	}
+	application := c.group(ApplicationGroupKey)
+	if application == nil {
+		return errors.New("config does not contain an application group")
+	}
+	if application.Groups == nil {
+		application.Groups = make(map[string]*common.ConfigGroup)
+	}
+	application.Groups[org] = group
+	return nil
+}
+
+// RemoveApplicationOrg removes the organisation from the channel
+func (c *Config) RemoveApplicationOrg(org string) error {
+	application := c.group(ApplicationGroupKey)
+	if application == nil || application.Groups[org] == nil {
+		return errors.Errorf("organisation [%s] is not a member of the channel", org)
+	}
+	delete(application.Groups, org)
+	return nil
+}
+
+// AnchorPeers returns the anchor peers of the organisation
+func (c *Config) AnchorPeers(org string) ([]*pb.AnchorPeer, error) {
+	if c.ApplicationOrg(org) == nil {
+		return nil, errors.Errorf("organisation [%s] is not a member of the channel", org)
+	}
+
+	anchorPeers := &pb.AnchorPeers{}
+	if err := c.getValue(anchorPeers, channelConfig.AnchorPeersKey, ApplicationGroupKey, org); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return anchorPeers.AnchorPeers, nil
+}
+
+// SetAnchorPeers sets the anchor peers of the organisation. The anchor peers are
+// removed if none are given.
+func (c *Config) SetAnchorPeers(org string, anchorPeers []*pb.AnchorPeer) error {
+	group := c.ApplicationOrg(org)
+	if group == nil {
+		return errors.Errorf("organisation [%s] is not a member of the channel", org)
+	}
+	for _, anchorPeer := range anchorPeers {
+		if anchorPeer.Host == "" || anchorPeer.Port <= 0 {
+			return errors.New("anchor peer host and port are required")
+		}
+	}
+
+	if len(anchorPeers) == 0 {
+		delete(group.Values, channelConfig.AnchorPeersKey)
+		return nil
+	}
+	return c.setValue(&pb.AnchorPeers{AnchorPeers: anchorPeers}, channelConfig.AnchorPeersKey, ApplicationGroupKey, org)
+}
+
+// ACLs returns the policy reference of each resource with an access control list
+func (c *Config) ACLs() (map[string]string, error) {
+	acls := &aclsProto{}
+	if err := c.getValue(acls, ACLsKey, ApplicationGroupKey); err != nil {
+		if isNotFound(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+
+	policyRefs := make(map[string]string)
+	for resource, apiResource := range acls.Acls {
+		if apiResource != nil {
+			policyRefs[resource] = apiResource.PolicyRef
+		}
+	}
+	return policyRefs, nil
+}
+
+// SetACL sets the policy, e.g. "/Channel/Application/Writers", which controls access to the resource
+func (c *Config) SetACL(resource string, policyRef string) error {
+	if resource == "" || policyRef == "" {
+		return errors.New("resource and policy reference are required")
+	}
+	return c.updateACLs(func(acls map[string]string) {
+		acls[resource] = policyRef
+	})
+}
+
+// RemoveACL removes the access control list of the resource, so that the default policy applies
+func (c *Config) RemoveACL(resource string) error {
+	return c.updateACLs(func(acls map[string]string) {
+		delete(acls, resource)
+	})
+}
+
+func (c *Config) updateACLs(update func(acls map[string]string)) error {
+	if c.group(ApplicationGroupKey) == nil {
+		return errors.New("config does not contain an application group")
+	}
+
+	policyRefs, err := c.ACLs()
+	if err != nil {
+		return err
+	}
+	update(policyRefs)
+
+	acls := &aclsProto{Acls: make(map[string]*apiResourceProto)}
+	for resource, policyRef := range policyRefs {
+		acls.Acls[resource] = &apiResourceProto{PolicyRef: policyRef}
+	}
+	return c.setValue(acls, ACLsKey, ApplicationGroupKey)
+}
+
+// group returns the group with the given path below the channel group or nil if it does not exist
+func (c *Config) group(path ...string) *common.ConfigGroup {
+	group := c.config.ChannelGroup
+	for _, name := range path {
+		if group == nil {
+			return nil
+		}
+		group = group.Groups[name]
+	}
+	return group
+}
+
+// notFoundError is returned if a config value does not exist
+type notFoundError struct {
+	key string
+}
+
+func (e notFoundError) Error() string {
+	return "config value [" + e.key + "] not found"
+}
+
+func isNotFound(err error) bool {
+	_, ok := errors.Cause(err).(notFoundError)
+	return ok
+}
+
+func (c *Config) getValue(msg proto.Message, key string, path ...string) error {
+	group := c.group(path...)
+	if group == nil {
+		return errors.Errorf("config group %v not found", path)
+	}
+	value, ok := group.Values[key]
+	if !ok {
+		return notFoundError{key: key}
+	}
+	if err := proto.Unmarshal(value.Value, msg); err != nil {
+		return errors.Wrapf(err, "unmarshal of config value [%s] failed", key)
+	}
+	return nil
+}
+
+// setValue sets the value in the group with the given path. The mod policy of an existing
+// value is retained; new values are modified by the admins of the group.
+func (c *Config) setValue(msg proto.Message, key string, path ...string) error {
+	group := c.group(path...)
+	if group == nil {
+		return errors.Errorf("config group %v not found", path)
+	}
+
+	valueBytes, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrapf(err, "marshal of config value [%s] failed", key)
+	}
+
+	if group.Values == nil {
+		group.Values = make(map[string]*common.ConfigValue)
+	}
+	value, ok := group.Values[key]
+	if !ok {
+		value = &common.ConfigValue{ModPolicy: channelConfig.AdminsPolicyKey}
+		group.Values[key] = value
+	}
+	value.Value = valueBytes
+	return nil
+}
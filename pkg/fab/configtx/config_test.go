@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+const testChannelID = "mychannel"
+
+func TestBatchSize(t *testing.T) {
+	config := newTestConfig(t)
+
+	batchSize, err := config.BatchSize()
+	if err != nil {
+		t.Fatalf("Failed to get batch size: %s", err)
+	}
+	assert.NotZero(t, batchSize.MaxMessageCount)
+
+	err = config.SetBatchSize(&orderer.BatchSize{MaxMessageCount: 50, AbsoluteMaxBytes: 1000, PreferredMaxBytes: 2000})
+	assert.NotNil(t, err, "expected error for preferred max bytes exceeding absolute max bytes")
+
+	err = config.SetBatchSize(&orderer.BatchSize{MaxMessageCount: 50, AbsoluteMaxBytes: 2000, PreferredMaxBytes: 1000})
+	if err != nil {
+		t.Fatalf("Failed to set batch size: %s", err)
+	}
+	batchSize, err = config.BatchSize()
+	if err != nil {
+		t.Fatalf("Failed to get batch size: %s", err)
+	}
+	assert.Equal(t, uint32(50), batchSize.MaxMessageCount)
+
+	err = config.SetBatchTimeout(2 * time.Second)
+	if err != nil {
+		t.Fatalf("Failed to set batch timeout: %s", err)
+	}
+	timeout, err := config.BatchTimeout()
+	if err != nil {
+		t.Fatalf("Failed to get batch timeout: %s", err)
+	}
+	assert.Equal(t, 2*time.Second, timeout)
+}
+
+func TestApplicationOrgs(t *testing.T) {
+	config := newTestConfig(t)
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, config.ApplicationOrgs())
+
+	org3 := proto.Clone(config.ApplicationOrg("Org1MSP")).(*common.ConfigGroup)
+	if err := config.SetApplicationOrg("Org3MSP", org3); err != nil {
+		t.Fatalf("Failed to add org: %s", err)
+	}
+	if err := config.RemoveApplicationOrg("Org1MSP"); err != nil {
+		t.Fatalf("Failed to remove org: %s", err)
+	}
+	assert.Equal(t, []string{"Org2MSP", "Org3MSP"}, config.ApplicationOrgs())
+
+	assert.NotNil(t, config.RemoveApplicationOrg("Org1MSP"), "expected error removing an org which is not a member")
+}
+
+func TestApplicationMSPIDs(t *testing.T) {
+	config := newTestConfig(t)
+	mspIDs, err := config.ApplicationMSPIDs()
+	if err != nil {
+		t.Fatalf("Failed to get MSP IDs: %s", err)
+	}
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, mspIDs)
+}
+
+func TestAnchorPeers(t *testing.T) {
+	config := newTestConfig(t)
+
+	anchorPeers, err := config.AnchorPeers("Org1MSP")
+	if err != nil {
+		t.Fatalf("Failed to get anchor peers: %s", err)
+	}
+	assert.Empty(t, anchorPeers)
+
+	err = config.SetAnchorPeers("Org1MSP", []*pb.AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}})
+	if err != nil {
+		t.Fatalf("Failed to set anchor peers: %s", err)
+	}
+	anchorPeers, err = config.AnchorPeers("Org1MSP")
+	if err != nil {
+		t.Fatalf("Failed to get anchor peers: %s", err)
+	}
+	if !assert.Len(t, anchorPeers, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "peer0.org1.example.com", anchorPeers[0].Host)
+
+	assert.NotNil(t, config.SetAnchorPeers("Org1MSP", []*pb.AnchorPeer{{Host: "peer0.org1.example.com"}}), "expected error for missing port")
+	assert.NotNil(t, config.SetAnchorPeers("Org9MSP", nil), "expected error for unknown org")
+}
+
+func TestACLs(t *testing.T) {
+	config := newTestConfig(t)
+
+	acls, err := config.ACLs()
+	if err != nil {
+		t.Fatalf("Failed to get ACLs: %s", err)
+	}
+	assert.Empty(t, acls)
+
+	if err := config.SetACL("peer/Propose", "/Channel/Application/Writers"); err != nil {
+		t.Fatalf("Failed to set ACL: %s", err)
+	}
+	if err := config.SetACL("qscc/GetBlockByNumber", "/Channel/Application/Readers"); err != nil {
+		t.Fatalf("Failed to set ACL: %s", err)
+	}
+	if err := config.RemoveACL("qscc/GetBlockByNumber"); err != nil {
+		t.Fatalf("Failed to remove ACL: %s", err)
+	}
+
+	acls, err = config.ACLs()
+	if err != nil {
+		t.Fatalf("Failed to get ACLs: %s", err)
+	}
+	assert.Equal(t, map[string]string{"peer/Propose": "/Channel/Application/Writers"}, acls)
+}
+
+func TestClone(t *testing.T) {
+	config := newTestConfig(t)
+	clone := config.Clone()
+
+	if err := clone.RemoveApplicationOrg("Org1MSP"); err != nil {
+		t.Fatalf("Failed to remove org: %s", err)
+	}
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, config.ApplicationOrgs(), "expected original to be unchanged")
+	assert.Equal(t, testChannelID, clone.ChannelID())
+}
+
+func newTestConfig(t *testing.T) *Config {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy: "Admins",
+			MSPNames:  []string{"Org1MSP", "Org2MSP"},
+		},
+	}
+	block := builder.Build()
+
+	envelope, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		t.Fatalf("Failed to extract envelope: %s", err)
+	}
+	payload, err := utils.ExtractPayload(envelope)
+	if err != nil {
+		t.Fatalf("Failed to extract payload: %s", err)
+	}
+	configEnvelope := &common.ConfigEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configEnvelope); err != nil {
+		t.Fatalf("Failed to unmarshal config envelope: %s", err)
+	}
+
+	config, err := FromConfigEnvelope(testChannelID, configEnvelope)
+	if err != nil {
+		t.Fatalf("Failed to create config: %s", err)
+	}
+	return config
+}
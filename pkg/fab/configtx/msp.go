@@ -0,0 +1,327 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	fabricMSP "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	cacerts              = "cacerts"
+	admincerts           = "admincerts"
+	intermediatecerts    = "intermediatecerts"
+	crlsfolder           = "crls"
+	configfilename       = "config.yaml"
+	tlscacerts           = "tlscacerts"
+	tlsintermediatecerts = "tlsintermediatecerts"
+
+	// fabricMSPType is the type of an X.509 based MSP
+	fabricMSPType = 0
+)
+
+// NewMSPConfigFromDir returns the verifying MSP configuration of the organisation, which is read from
+// an MSP directory with the layout used by fabric (cacerts, admincerts, tlscacerts, config.yaml, ...).
+// Signing material in the directory is ignored.
+func NewMSPConfigFromDir(mspID string, dir string) (*mspproto.MSPConfig, error) {
+	if mspID == "" {
+		return nil, errors.New("MSP ID is required")
+	}
+
+	rootCerts, err := pemMaterialFromDir(filepath.Join(dir, cacerts))
+	if err != nil {
+		return nil, err
+	}
+	if len(rootCerts) == 0 {
+		return nil, errors.Errorf("MSP directory [%s] does not contain CA certificates", dir)
+	}
+
+	fabricMSPConfig := &mspproto.FabricMSPConfig{
+		Name:      mspID,
+		RootCerts: rootCerts,
+		CryptoConfig: &mspproto.FabricCryptoConfig{
+			SignatureHashFamily:            "SHA2",
+			IdentityIdentifierHashFunction: "SHA256",
+		},
+	}
+
+	folders := []struct {
+		name  string
+		certs *[][]byte
+	}{
+		{admincerts, &fabricMSPConfig.Admins},
+		{intermediatecerts, &fabricMSPConfig.IntermediateCerts},
+		{crlsfolder, &fabricMSPConfig.RevocationList},
+		{tlscacerts, &fabricMSPConfig.TlsRootCerts},
+		{tlsintermediatecerts, &fabricMSPConfig.TlsIntermediateCerts},
+	}
+	for _, folder := range folders {
+		if *folder.certs, err = pemMaterialFromDir(filepath.Join(dir, folder.name)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := readMSPConfigFile(dir, fabricMSPConfig); err != nil {
+		return nil, err
+	}
+
+	configBytes, err := proto.Marshal(fabricMSPConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of fabric MSP config failed")
+	}
+	return &mspproto.MSPConfig{Type: fabricMSPType, Config: configBytes}, nil
+}
+
+// NewOrgGroup returns the configuration group of an application organisation with the given MSP.
+// The members of the organisation are the readers and writers of the group and its admins
+// may modify the group.
+func NewOrgGroup(mspConfig *mspproto.MSPConfig, anchorPeers []*pb.AnchorPeer) (*common.ConfigGroup, error) {
+	mspID, err := MSPID(mspConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &common.ConfigGroup{
+		Groups:    make(map[string]*common.ConfigGroup),
+		Values:    make(map[string]*common.ConfigValue),
+		Policies:  make(map[string]*common.ConfigPolicy),
+		ModPolicy: channelConfig.AdminsPolicyKey,
+	}
+
+	policies := map[string]*common.SignaturePolicyEnvelope{
+		channelConfig.ReadersPolicyKey: cauthdsl.SignedByMspMember(mspID),
+		channelConfig.WritersPolicyKey: cauthdsl.SignedByMspMember(mspID),
+		channelConfig.AdminsPolicyKey:  cauthdsl.SignedByMspAdmin(mspID),
+	}
+	for name, policy := range policies {
+		policyBytes, err := proto.Marshal(policy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "marshal of policy [%s] failed", name)
+		}
+		group.Policies[name] = &common.ConfigPolicy{
+			Policy:    &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: policyBytes},
+			ModPolicy: channelConfig.AdminsPolicyKey,
+		}
+	}
+
+	if err := setGroupValue(group, mspConfig, channelConfig.MSPKey); err != nil {
+		return nil, err
+	}
+	if len(anchorPeers) > 0 {
+		if err := setGroupValue(group, &pb.AnchorPeers{AnchorPeers: anchorPeers}, channelConfig.AnchorPeersKey); err != nil {
+			return nil, err
+		}
+	}
+	return group, nil
+}
+
+// MSPID returns the MSP ID defined by the MSP configuration
+func MSPID(mspConfig *mspproto.MSPConfig) (string, error) {
+	if mspConfig == nil {
+		return "", errors.New("MSP config is required")
+	}
+	if mspConfig.Type != fabricMSPType {
+		return "", errors.Errorf("unsupported MSP type [%d]", mspConfig.Type)
+	}
+
+	fabricMSPConfig := &mspproto.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+		return "", errors.Wrap(err, "unmarshal of fabric MSP config failed")
+	}
+	if fabricMSPConfig.Name == "" {
+		return "", errors.New("MSP config does not contain an MSP ID")
+	}
+	return fabricMSPConfig.Name, nil
+}
+
+// ApplicationOrgMSP returns the MSP configuration of the organisation
+func (c *Config) ApplicationOrgMSP(org string) (*mspproto.MSPConfig, error) {
+	if c.ApplicationOrg(org) == nil {
+		return nil, errors.Errorf("organisation [%s] is not a member of the channel", org)
+	}
+
+	mspConfig := &mspproto.MSPConfig{}
+	if err := c.getValue(mspConfig, channelConfig.MSPKey, ApplicationGroupKey, org); err != nil {
+		return nil, err
+	}
+	return mspConfig, nil
+}
+
+// SetApplicationOrgMSP replaces the MSP configuration of the organisation, e.g. to rotate its
+// CA certificates. The policies and anchor peers of the organisation are retained.
+func (c *Config) SetApplicationOrgMSP(org string, mspConfig *mspproto.MSPConfig) error {
+	if c.ApplicationOrg(org) == nil {
+		return errors.Errorf("organisation [%s] is not a member of the channel", org)
+	}
+
+	mspID, err := MSPID(mspConfig)
+	if err != nil {
+		return err
+	}
+	current, err := c.ApplicationOrgMSP(org)
+	if err != nil {
+		return err
+	}
+	currentMSPID, err := MSPID(current)
+	if err != nil {
+		return err
+	}
+	if mspID != currentMSPID {
+		return errors.Errorf("MSP ID [%s] does not match MSP ID [%s] of organisation [%s]", mspID, currentMSPID, org)
+	}
+
+	return c.setValue(mspConfig, channelConfig.MSPKey, ApplicationGroupKey, org)
+}
+
+func setGroupValue(group *common.ConfigGroup, msg proto.Message, key string) error {
+	valueBytes, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrapf(err, "marshal of config value [%s] failed", key)
+	}
+	group.Values[key] = &common.ConfigValue{Value: valueBytes, ModPolicy: channelConfig.AdminsPolicyKey}
+	return nil
+}
+
+// readMSPConfigFile adds the organisational units defined in the optional config.yaml
+// of the MSP directory to the MSP configuration
+func readMSPConfigFile(dir string, fabricMSPConfig *mspproto.FabricMSPConfig) error {
+	configFile := filepath.Join(dir, configfilename)
+	raw, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "reading MSP config file [%s] failed", configFile)
+	}
+
+	configuration := fabricMSP.Configuration{}
+	if err := yaml.Unmarshal(raw, &configuration); err != nil {
+		return errors.Wrapf(err, "parsing MSP config file [%s] failed", configFile)
+	}
+
+	for _, ou := range configuration.OrganizationalUnitIdentifiers {
+		ouIdentifier, err := ouIdentifierFromConfig(dir, ou)
+		if err != nil {
+			return err
+		}
+		fabricMSPConfig.OrganizationalUnitIdentifiers = append(fabricMSPConfig.OrganizationalUnitIdentifiers, ouIdentifier)
+	}
+
+	if configuration.NodeOUs != nil {
+		nodeOUs := &mspproto.FabricNodeOUs{Enable: configuration.NodeOUs.Enable}
+		if nodeOUs.ClientOUIdentifier, err = ouIdentifierFromConfig(dir, configuration.NodeOUs.ClientOUIdentifier); err != nil {
+			return err
+		}
+		if nodeOUs.PeerOUIdentifier, err = ouIdentifierFromConfig(dir, configuration.NodeOUs.PeerOUIdentifier); err != nil {
+			return err
+		}
+		if nodeOUs.OrdererOUIdentifier, err = ouIdentifierFromConfig(dir, configuration.NodeOUs.OrdererOUIdentifier); err != nil {
+			return err
+		}
+		fabricMSPConfig.FabricNodeOUs = nodeOUs
+	}
+	return nil
+}
+
+func ouIdentifierFromConfig(dir string, ou *fabricMSP.OrganizationalUnitIdentifiersConfiguration) (*mspproto.FabricOUIdentifier, error) {
+	if ou == nil {
+		return nil, nil
+	}
+
+	ouIdentifier := &mspproto.FabricOUIdentifier{OrganizationalUnitIdentifier: ou.OrganizationalUnitIdentifier}
+	if ou.Certificate != "" {
+		certFile := filepath.Join(dir, ou.Certificate)
+		raw, err := ioutil.ReadFile(certFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading OU certificate [%s] failed", certFile)
+		}
+		ouIdentifier.Certificate = raw
+	}
+	return ouIdentifier, nil
+}
+
+// pemMaterialFromDir returns the PEM blocks of all files in the directory. A missing
+// directory contains no material.
+func pemMaterialFromDir(dir string) ([][]byte, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "reading MSP folder [%s] failed", dir)
+	}
+
+	var material [][]byte
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		raw, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading MSP file [%s] failed", file.Name())
+		}
+
+		for {
+			var block *pem.Block
+			block, raw = pem.Decode(raw)
+			if block == nil {
+				break
+			}
+			material = append(material, pem.EncodeToMemory(block))
+		}
+	}
+	return material, nil
+}
+
+// ApplicationMSPIDs returns the MSP IDs of the organisations that are members of the channel
+func (c *Config) ApplicationMSPIDs() ([]string, error) {
+	var mspIDs []string
+	for _, org := range c.ApplicationOrgs() {
+		mspConfig, err := c.ApplicationOrgMSP(org)
+		if err != nil {
+			return nil, err
+		}
+		mspID, err := MSPID(mspConfig)
+		if err != nil {
+			return nil, err
+		}
+		mspIDs = append(mspIDs, mspID)
+	}
+	return mspIDs, nil
+}
+
+// ApplicationOrgByMSPID returns the name of the application organisation with the given MSP ID
+func (c *Config) ApplicationOrgByMSPID(mspID string) (string, error) {
+	for _, org := range c.ApplicationOrgs() {
+		mspConfig, err := c.ApplicationOrgMSP(org)
+		if err != nil {
+			return "", err
+		}
+		orgMSPID, err := MSPID(mspConfig)
+		if err != nil {
+			return "", err
+		}
+		if orgMSPID == mspID {
+			return org, nil
+		}
+	}
+	return "", errors.Errorf("no organisation with MSP ID [%s] is a member of the channel", mspID)
+}
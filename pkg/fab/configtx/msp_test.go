@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	mspproto "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const org1MSPDir = "../../../test/fixtures/fabric/v1/crypto-config/peerOrganizations/org1.example.com/msp"
+
+func TestNewMSPConfigFromDir(t *testing.T) {
+	mspConfig, err := NewMSPConfigFromDir("Org1MSP", org1MSPDir)
+	if err != nil {
+		t.Fatalf("Failed to read MSP directory: %s", err)
+	}
+
+	fabricMSPConfig := &mspproto.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+		t.Fatalf("Failed to unmarshal MSP config: %s", err)
+	}
+	assert.Equal(t, "Org1MSP", fabricMSPConfig.Name)
+	assert.Len(t, fabricMSPConfig.RootCerts, 1)
+	assert.Len(t, fabricMSPConfig.Admins, 1)
+	assert.Len(t, fabricMSPConfig.TlsRootCerts, 1)
+	assert.Empty(t, fabricMSPConfig.IntermediateCerts)
+
+	_, err = NewMSPConfigFromDir("", org1MSPDir)
+	assert.NotNil(t, err, "expected error for missing MSP ID")
+
+	_, err = NewMSPConfigFromDir("Org1MSP", "./invalid")
+	assert.NotNil(t, err, "expected error for missing CA certificates")
+}
+
+func TestNewMSPConfigFromDirWithConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "msp")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	caCert, err := ioutil.ReadFile(filepath.Join(org1MSPDir, cacerts, "ca.org1.example.com-cert.pem"))
+	if err != nil {
+		t.Fatalf("Failed to read CA certificate: %s", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, cacerts), 0755); err != nil {
+		t.Fatalf("Failed to create cacerts folder: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, cacerts, "ca.pem"), caCert, 0644); err != nil {
+		t.Fatalf("Failed to write CA certificate: %s", err)
+	}
+
+	configYaml := `
+OrganizationalUnitIdentifiers:
+  - Certificate: cacerts/ca.pem
+    OrganizationalUnitIdentifier: COP
+NodeOUs:
+  Enable: true
+  ClientOUIdentifier:
+    Certificate: cacerts/ca.pem
+    OrganizationalUnitIdentifier: client
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, configfilename), []byte(configYaml), 0644); err != nil {
+		t.Fatalf("Failed to write MSP config file: %s", err)
+	}
+
+	mspConfig, err := NewMSPConfigFromDir("Org1MSP", dir)
+	if err != nil {
+		t.Fatalf("Failed to read MSP directory: %s", err)
+	}
+
+	fabricMSPConfig := &mspproto.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+		t.Fatalf("Failed to unmarshal MSP config: %s", err)
+	}
+	if !assert.Len(t, fabricMSPConfig.OrganizationalUnitIdentifiers, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "COP", fabricMSPConfig.OrganizationalUnitIdentifiers[0].OrganizationalUnitIdentifier)
+	assert.Equal(t, caCert, fabricMSPConfig.OrganizationalUnitIdentifiers[0].Certificate)
+	if !assert.NotNil(t, fabricMSPConfig.FabricNodeOUs) {
+		t.FailNow()
+	}
+	assert.True(t, fabricMSPConfig.FabricNodeOUs.Enable)
+	assert.Equal(t, "client", fabricMSPConfig.FabricNodeOUs.ClientOUIdentifier.OrganizationalUnitIdentifier)
+	assert.Nil(t, fabricMSPConfig.FabricNodeOUs.PeerOUIdentifier)
+}
+
+func TestNewOrgGroup(t *testing.T) {
+	mspConfig, err := NewMSPConfigFromDir("Org3MSP", org1MSPDir)
+	if err != nil {
+		t.Fatalf("Failed to read MSP directory: %s", err)
+	}
+
+	group, err := NewOrgGroup(mspConfig, []*pb.AnchorPeer{{Host: "peer0.org3.example.com", Port: 7051}})
+	if err != nil {
+		t.Fatalf("Failed to create org group: %s", err)
+	}
+	assert.Equal(t, channelConfig.AdminsPolicyKey, group.ModPolicy)
+	assert.Len(t, group.Policies, 3)
+	assert.NotNil(t, group.Values[channelConfig.MSPKey])
+	assert.NotNil(t, group.Values[channelConfig.AnchorPeersKey])
+
+	config := newTestConfig(t)
+	if err := config.SetApplicationOrg("Org3MSP", group); err != nil {
+		t.Fatalf("Failed to add org: %s", err)
+	}
+	orgMSP, err := config.ApplicationOrgMSP("Org3MSP")
+	if err != nil {
+		t.Fatalf("Failed to get org MSP: %s", err)
+	}
+	mspID, err := MSPID(orgMSP)
+	if err != nil {
+		t.Fatalf("Failed to get MSP ID: %s", err)
+	}
+	assert.Equal(t, "Org3MSP", mspID)
+
+	_, err = NewOrgGroup(&mspproto.MSPConfig{Type: 1}, nil)
+	assert.NotNil(t, err, "expected error for unsupported MSP type")
+}
+
+func TestSetApplicationOrgMSP(t *testing.T) {
+	original := newTestConfig(t)
+	updated := original.Clone()
+
+	mspConfig, err := NewMSPConfigFromDir("Org1MSP", org1MSPDir)
+	if err != nil {
+		t.Fatalf("Failed to read MSP directory: %s", err)
+	}
+	if err := updated.SetApplicationOrgMSP("Org1MSP", mspConfig); err != nil {
+		t.Fatalf("Failed to replace org MSP: %s", err)
+	}
+
+	update, err := ComputeUpdate(original, updated)
+	if err != nil {
+		t.Fatalf("Failed to compute update: %s", err)
+	}
+	org := update.WriteSet.Groups[ApplicationGroupKey].Groups["Org1MSP"]
+	if !assert.NotNil(t, org) {
+		t.FailNow()
+	}
+	assert.Equal(t, uint64(1), org.Values[channelConfig.MSPKey].Version)
+	assert.Len(t, org.Values, 1, "expected only the MSP to be written")
+
+	otherMSP, err := NewMSPConfigFromDir("Org2MSP", org1MSPDir)
+	if err != nil {
+		t.Fatalf("Failed to read MSP directory: %s", err)
+	}
+	assert.NotNil(t, updated.SetApplicationOrgMSP("Org1MSP", otherMSP), "expected error for different MSP ID")
+	assert.NotNil(t, updated.SetApplicationOrgMSP("Org9MSP", mspConfig), "expected error for unknown org")
+}
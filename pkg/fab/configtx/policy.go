@@ -0,0 +1,364 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+// channelGroupName is the name of the root group in policy paths, e.g. /Channel/Application/Admins
+const channelGroupName = "Channel"
+
+// SignatureStatus describes to which extent the signatures of a config update satisfy the
+// modification policies of the modified configuration elements
+type SignatureStatus struct {
+	// MSP IDs of the signers
+	Signers []string
+	// Status of each modification policy which applies to the update
+	Policies []*PolicyStatus
+	// Satisfied is true if the signatures satisfy all modification policies
+	Satisfied bool
+}
+
+// PolicyStatus describes whether the signatures satisfy a modification policy
+type PolicyStatus struct {
+	// Path of the policy, e.g. /Channel/Application/Admins
+	Path string
+	// Rule of the policy, e.g. "MAJORITY Admins" for an implicit meta policy
+	Rule string
+	// Satisfied is true if the signatures satisfy the policy
+	Satisfied bool
+	// For implicit meta policies, the sub-groups (organisations) whose policy is satisfied
+	SignedGroups []string
+	// For implicit meta policies, the sub-groups (organisations) whose policy is not satisfied
+	UnsignedGroups []string
+}
+
+// signer is the identity which created a config signature
+type signer struct {
+	mspID string
+	cert  []byte
+}
+
+// EvaluateSignatures checks whether the signatures satisfy the modification policies, which the
+// current configuration defines for the elements modified by the config update. Signatures are
+// matched to policy principals by the MSP ID of their creator and, for the admin role, by the
+// admin certificates of the MSP. The signatures themselves are not verified; the orderer verifies
+// them when the update is submitted.
+func (c *Config) EvaluateSignatures(configUpdate *common.ConfigUpdate, signatures []*common.ConfigSignature) (*SignatureStatus, error) {
+	if configUpdate == nil || configUpdate.WriteSet == nil {
+		return nil, errors.New("config update does not contain a write set")
+	}
+	if configUpdate.ChannelId != c.channelID {
+		return nil, errors.Errorf("config update is for channel [%s] but config is for channel [%s]", configUpdate.ChannelId, c.channelID)
+	}
+
+	var signers []*signer
+	signerMSPs := make(map[string]bool)
+	for _, signature := range signatures {
+		s, err := signatureSigner(signature)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, s)
+		signerMSPs[s.mspID] = true
+	}
+
+	policyPaths := make(map[string]bool)
+	if err := c.modPolicies([]string{channelGroupName}, c.config.ChannelGroup, configUpdate.WriteSet, policyPaths); err != nil {
+		return nil, err
+	}
+
+	status := &SignatureStatus{Satisfied: true}
+	for mspID := range signerMSPs {
+		status.Signers = append(status.Signers, mspID)
+	}
+	sort.Strings(status.Signers)
+
+	var paths []string
+	for path := range policyPaths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		policyStatus, err := c.evaluatePolicy(path, signers)
+		if err != nil {
+			return nil, err
+		}
+		status.Policies = append(status.Policies, policyStatus)
+		status.Satisfied = status.Satisfied && policyStatus.Satisfied
+	}
+	return status, nil
+}
+
+// modPolicies collects the paths of the modification policies of the existing elements, whose
+// version is incremented by the write set. New elements are authorized by the modification
+// policy of their parent group, whose version is incremented as well.
+func (c *Config) modPolicies(path []string, current, writeSet *common.ConfigGroup, policyPaths map[string]bool) error {
+	if current == nil {
+		return nil
+	}
+
+	if writeSet.Version != current.Version {
+		if err := addPolicyPath(policyPaths, path, current.ModPolicy); err != nil {
+			return err
+		}
+	}
+	for key, value := range writeSet.Values {
+		if existing, ok := current.Values[key]; ok && value.Version != existing.Version {
+			if err := addPolicyPath(policyPaths, path, existing.ModPolicy); err != nil {
+				return err
+			}
+		}
+	}
+	for key, policy := range writeSet.Policies {
+		if existing, ok := current.Policies[key]; ok && policy.Version != existing.Version {
+			if err := addPolicyPath(policyPaths, path, existing.ModPolicy); err != nil {
+				return err
+			}
+		}
+	}
+	for key, group := range writeSet.Groups {
+		if err := c.modPolicies(append(append([]string{}, path...), key), current.Groups[key], group, policyPaths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addPolicyPath adds the absolute path of the modification policy, which is relative to the given group path
+func addPolicyPath(policyPaths map[string]bool, groupPath []string, modPolicy string) error {
+	if modPolicy == "" {
+		return errors.Errorf("config element of group [/%s] has an empty mod policy", strings.Join(groupPath, "/"))
+	}
+	if strings.HasPrefix(modPolicy, "/") {
+		policyPaths[modPolicy] = true
+		return nil
+	}
+	policyPaths["/"+strings.Join(append(append([]string{}, groupPath...), modPolicy), "/")] = true
+	return nil
+}
+
+func (c *Config) evaluatePolicy(path string, signers []*signer) (*PolicyStatus, error) {
+	elements := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(elements) < 2 || elements[0] != channelGroupName {
+		return nil, errors.Errorf("invalid policy path [%s]", path)
+	}
+
+	group := c.group(elements[1 : len(elements)-1]...)
+	if group == nil {
+		return nil, errors.Errorf("group of policy [%s] not found", path)
+	}
+
+	status := &PolicyStatus{Path: path}
+	policy, ok := group.Policies[elements[len(elements)-1]]
+	if !ok || policy.Policy == nil {
+		return nil, errors.Errorf("policy [%s] not found", path)
+	}
+
+	switch common.Policy_PolicyType(policy.Policy.Type) {
+	case common.Policy_IMPLICIT_META:
+		implicitMeta := &common.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(policy.Policy.Value, implicitMeta); err != nil {
+			return nil, errors.Wrapf(err, "unmarshal of implicit meta policy [%s] failed", path)
+		}
+		status.Rule = fmt.Sprintf("%s %s", implicitMeta.Rule, implicitMeta.SubPolicy)
+
+		var names []string
+		for name := range group.Groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			subPath := "/" + strings.Join(append(elements[:len(elements)-1:len(elements)-1], name, implicitMeta.SubPolicy), "/")
+			subStatus, err := c.evaluatePolicy(subPath, signers)
+			if err != nil {
+				return nil, err
+			}
+			if subStatus.Satisfied {
+				status.SignedGroups = append(status.SignedGroups, name)
+			} else {
+				status.UnsignedGroups = append(status.UnsignedGroups, name)
+			}
+		}
+
+		status.Satisfied = len(status.SignedGroups) >= implicitMetaThreshold(implicitMeta.Rule, len(names))
+	case common.Policy_SIGNATURE:
+		envelope := &common.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(policy.Policy.Value, envelope); err != nil {
+			return nil, errors.Wrapf(err, "unmarshal of signature policy [%s] failed", path)
+		}
+		status.Rule = "SIGNATURE"
+
+		satisfied, err := c.evaluateSignaturePolicy(envelope.Rule, envelope.Identities, signers, make([]bool, len(signers)))
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("evaluation of signature policy [%s] failed", path))
+		}
+		status.Satisfied = satisfied
+	default:
+		return nil, errors.Errorf("policy [%s] has unsupported type [%d]", path, policy.Policy.Type)
+	}
+
+	return status, nil
+}
+
+// implicitMetaThreshold returns the number of sub-policies which must be satisfied, using the same
+// rules as fabric: ANY requires one sub-policy and MAJORITY more than half of them. A policy
+// without sub-policies is satisfied by any rule.
+func implicitMetaThreshold(rule common.ImplicitMetaPolicy_Rule, subPolicies int) int {
+	if subPolicies == 0 {
+		return 0
+	}
+
+	switch rule {
+	case common.ImplicitMetaPolicy_ANY:
+		return 1
+	case common.ImplicitMetaPolicy_ALL:
+		return subPolicies
+	default:
+		return subPolicies/2 + 1
+	}
+}
+
+// evaluateSignaturePolicy evaluates the signature policy rule in the same way as fabric's cauthdsl,
+// where each signer satisfies at most one principal
+func (c *Config) evaluateSignaturePolicy(rule *common.SignaturePolicy, identities []*mspproto.MSPPrincipal, signers []*signer, used []bool) (bool, error) {
+	switch t := rule.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(identities) {
+			return false, errors.Errorf("identity index %d out of range", t.SignedBy)
+		}
+		for i, s := range signers {
+			if used[i] {
+				continue
+			}
+			matched, err := c.matchesPrincipal(s, identities[t.SignedBy])
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				used[i] = true
+				return true, nil
+			}
+		}
+		return false, nil
+	case *common.SignaturePolicy_NOutOf_:
+		verified := int32(0)
+		tentative := append([]bool{}, used...)
+		for _, subRule := range t.NOutOf.Rules {
+			satisfied, err := c.evaluateSignaturePolicy(subRule, identities, signers, tentative)
+			if err != nil {
+				return false, err
+			}
+			if satisfied {
+				verified++
+			}
+		}
+		if verified >= t.NOutOf.N {
+			copy(used, tentative)
+			return true, nil
+		}
+		return false, nil
+	default:
+		return false, errors.Errorf("unsupported signature policy rule %T", rule.Type)
+	}
+}
+
+// matchesPrincipal returns true if the signer satisfies the principal. Roles other than
+// the admin role are matched by MSP ID only.
+func (c *Config) matchesPrincipal(s *signer, principal *mspproto.MSPPrincipal) (bool, error) {
+	switch principal.PrincipalClassification {
+	case mspproto.MSPPrincipal_ROLE:
+		role := &mspproto.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return false, errors.Wrap(err, "unmarshal of MSP role failed")
+		}
+		if role.MspIdentifier != s.mspID {
+			return false, nil
+		}
+		if role.Role != mspproto.MSPRole_ADMIN {
+			return true, nil
+		}
+		return c.isAdmin(s), nil
+	case mspproto.MSPPrincipal_IDENTITY:
+		identity := &mspproto.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, identity); err != nil {
+			return false, errors.Wrap(err, "unmarshal of identity principal failed")
+		}
+		return identity.Mspid == s.mspID && bytes.Equal(identity.IdBytes, s.cert), nil
+	default:
+		return false, nil
+	}
+}
+
+// isAdmin returns true if the certificate of the signer is an admin certificate of its MSP
+func (c *Config) isAdmin(s *signer) bool {
+	for _, mspConfig := range c.mspConfigs() {
+		if mspConfig.Name != s.mspID {
+			continue
+		}
+		for _, admin := range mspConfig.Admins {
+			if bytes.Equal(bytes.TrimSpace(admin), bytes.TrimSpace(s.cert)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mspConfigs returns the MSP configurations of all organisations of the channel
+func (c *Config) mspConfigs() []*mspproto.FabricMSPConfig {
+	var mspConfigs []*mspproto.FabricMSPConfig
+	var collect func(group *common.ConfigGroup)
+	collect = func(group *common.ConfigGroup) {
+		if value, ok := group.Values[channelConfig.MSPKey]; ok {
+			mspConfig := &mspproto.MSPConfig{}
+			fabricMSPConfig := &mspproto.FabricMSPConfig{}
+			if proto.Unmarshal(value.Value, mspConfig) == nil && mspConfig.Type == fabricMSPType &&
+				proto.Unmarshal(mspConfig.Config, fabricMSPConfig) == nil {
+				mspConfigs = append(mspConfigs, fabricMSPConfig)
+			}
+		}
+		for _, child := range group.Groups {
+			collect(child)
+		}
+	}
+	collect(c.config.ChannelGroup)
+	return mspConfigs
+}
+
+// signatureSigner returns the identity which created the config signature
+func signatureSigner(signature *common.ConfigSignature) (*signer, error) {
+	if signature == nil {
+		return nil, errors.New("config signature is nil")
+	}
+
+	signatureHeader := &common.SignatureHeader{}
+	if err := proto.Unmarshal(signature.SignatureHeader, signatureHeader); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of signature header failed")
+	}
+	identity := &mspproto.SerializedIdentity{}
+	if err := proto.Unmarshal(signatureHeader.Creator, identity); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of signature creator failed")
+	}
+	if identity.Mspid == "" {
+		return nil, errors.New("signature creator does not contain an MSP ID")
+	}
+	return &signer{mspID: identity.Mspid, cert: identity.IdBytes}, nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+)
+
+const org2MSPDir = "../../../test/fixtures/fabric/v1/crypto-config/peerOrganizations/org2.example.com/msp"
+
+func TestEvaluateSignatures(t *testing.T) {
+	original := newPolicyTestConfig(t)
+	updated := original.Clone()
+
+	org3 := proto.Clone(original.ApplicationOrg("Org1MSP")).(*common.ConfigGroup)
+	if err := updated.SetApplicationOrg("Org3MSP", org3); err != nil {
+		t.Fatalf("Failed to add org: %s", err)
+	}
+	update, err := ComputeUpdate(original, updated)
+	if err != nil {
+		t.Fatalf("Failed to compute update: %s", err)
+	}
+
+	org1Admin := newTestSignature(t, "Org1MSP", adminCert(t, org1MSPDir))
+	org2Admin := newTestSignature(t, "Org2MSP", adminCert(t, org2MSPDir))
+	org2Member := newTestSignature(t, "Org2MSP", []byte("member"))
+
+	// A majority of two organisations requires the admins of both organisations
+	status, err := original.EvaluateSignatures(update, []*common.ConfigSignature{org1Admin})
+	if err != nil {
+		t.Fatalf("Failed to evaluate signatures: %s", err)
+	}
+	assert.False(t, status.Satisfied)
+	assert.Equal(t, []string{"Org1MSP"}, status.Signers)
+	if !assert.Len(t, status.Policies, 1) {
+		t.FailNow()
+	}
+	policy := status.Policies[0]
+	assert.Equal(t, "/Channel/Application/Admins", policy.Path)
+	assert.Equal(t, "MAJORITY Admins", policy.Rule)
+	assert.Equal(t, []string{"Org1MSP"}, policy.SignedGroups)
+	assert.Equal(t, []string{"Org2MSP"}, policy.UnsignedGroups)
+
+	status, err = original.EvaluateSignatures(update, []*common.ConfigSignature{org1Admin, org2Member})
+	if err != nil {
+		t.Fatalf("Failed to evaluate signatures: %s", err)
+	}
+	assert.False(t, status.Satisfied, "expected member signature not to satisfy admin policy")
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, status.Signers)
+
+	status, err = original.EvaluateSignatures(update, []*common.ConfigSignature{org1Admin, org2Admin})
+	if err != nil {
+		t.Fatalf("Failed to evaluate signatures: %s", err)
+	}
+	assert.True(t, status.Satisfied)
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, status.Policies[0].SignedGroups)
+}
+
+func TestImplicitMetaThreshold(t *testing.T) {
+	assert.Equal(t, 1, implicitMetaThreshold(common.ImplicitMetaPolicy_ANY, 3))
+	assert.Equal(t, 3, implicitMetaThreshold(common.ImplicitMetaPolicy_ALL, 3))
+	assert.Equal(t, 2, implicitMetaThreshold(common.ImplicitMetaPolicy_MAJORITY, 3))
+	assert.Equal(t, 3, implicitMetaThreshold(common.ImplicitMetaPolicy_MAJORITY, 4))
+
+	for _, rule := range []common.ImplicitMetaPolicy_Rule{common.ImplicitMetaPolicy_ANY, common.ImplicitMetaPolicy_ALL, common.ImplicitMetaPolicy_MAJORITY} {
+		assert.Equal(t, 0, implicitMetaThreshold(rule, 0), "expected a policy without sub-policies to be satisfied by rule %s", rule)
+	}
+}
+
+func TestEvaluateSignaturesModifiedValue(t *testing.T) {
+	original := newPolicyTestConfig(t)
+	updated := original.Clone()
+
+	// The orderer group contains only the orderer organisation, whose admins sign with a member policy
+	if err := updated.SetBatchSize(&orderer.BatchSize{MaxMessageCount: 50, AbsoluteMaxBytes: 2000, PreferredMaxBytes: 1000}); err != nil {
+		t.Fatalf("Failed to set batch size: %s", err)
+	}
+	update, err := ComputeUpdate(original, updated)
+	if err != nil {
+		t.Fatalf("Failed to compute update: %s", err)
+	}
+
+	status, err := original.EvaluateSignatures(update, []*common.ConfigSignature{newTestSignature(t, "Org1MSP", []byte("member"))})
+	if err != nil {
+		t.Fatalf("Failed to evaluate signatures: %s", err)
+	}
+	assert.False(t, status.Satisfied)
+	assert.Equal(t, "/Channel/Orderer/Admins", status.Policies[0].Path)
+
+	status, err = original.EvaluateSignatures(update, []*common.ConfigSignature{newTestSignature(t, "OrdererMSP", []byte("member"))})
+	if err != nil {
+		t.Fatalf("Failed to evaluate signatures: %s", err)
+	}
+	assert.True(t, status.Satisfied)
+
+	_, err = original.EvaluateSignatures(update, []*common.ConfigSignature{{SignatureHeader: []byte("invalid")}})
+	assert.NotNil(t, err, "expected error for invalid signature header")
+
+	update.ChannelId = "otherchannel"
+	_, err = original.EvaluateSignatures(update, nil)
+	assert.NotNil(t, err, "expected error for update of another channel")
+}
+
+// newPolicyTestConfig returns a test config with the policies generated by configtxgen for the
+// application group and its organisations
+func newPolicyTestConfig(t *testing.T) *Config {
+	config := newTestConfig(t)
+
+	implicitMeta := &common.ConfigPolicy{
+		ModPolicy: channelConfig.AdminsPolicyKey,
+		Policy: &common.Policy{
+			Type:  int32(common.Policy_IMPLICIT_META),
+			Value: marshalOrFail(t, &common.ImplicitMetaPolicy{SubPolicy: channelConfig.AdminsPolicyKey, Rule: common.ImplicitMetaPolicy_MAJORITY}),
+		},
+	}
+	config.group(ApplicationGroupKey).Policies[channelConfig.AdminsPolicyKey] = implicitMeta
+	config.group(channelConfig.OrdererGroupKey).Policies[channelConfig.AdminsPolicyKey] = implicitMeta
+
+	for org, dir := range map[string]string{"Org1MSP": org1MSPDir, "Org2MSP": org2MSPDir} {
+		mspConfig, err := NewMSPConfigFromDir(org, dir)
+		if err != nil {
+			t.Fatalf("Failed to read MSP directory: %s", err)
+		}
+		if err := config.SetApplicationOrgMSP(org, mspConfig); err != nil {
+			t.Fatalf("Failed to set org MSP: %s", err)
+		}
+		config.ApplicationOrg(org).Policies[channelConfig.AdminsPolicyKey] = signatureConfigPolicy(t, cauthdsl.SignedByMspAdmin(org))
+	}
+	config.group(channelConfig.OrdererGroupKey, "OrdererMSP").Policies[channelConfig.AdminsPolicyKey] = signatureConfigPolicy(t, cauthdsl.SignedByMspMember("OrdererMSP"))
+
+	return config
+}
+
+func signatureConfigPolicy(t *testing.T, envelope *common.SignaturePolicyEnvelope) *common.ConfigPolicy {
+	return &common.ConfigPolicy{
+		ModPolicy: channelConfig.AdminsPolicyKey,
+		Policy:    &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: marshalOrFail(t, envelope)},
+	}
+}
+
+func newTestSignature(t *testing.T, mspID string, cert []byte) *common.ConfigSignature {
+	creator := marshalOrFail(t, &mspproto.SerializedIdentity{Mspid: mspID, IdBytes: cert})
+	return &common.ConfigSignature{
+		SignatureHeader: marshalOrFail(t, &common.SignatureHeader{Creator: creator, Nonce: []byte(mspID)}),
+		Signature:       []byte("signature"),
+	}
+}
+
+func adminCert(t *testing.T, mspDir string) []byte {
+	files, err := ioutil.ReadDir(filepath.Join(mspDir, admincerts))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Failed to find admin certificate in [%s]: %v", mspDir, err)
+	}
+	cert, err := ioutil.ReadFile(filepath.Join(mspDir, admincerts, files[0].Name()))
+	if err != nil {
+		t.Fatalf("Failed to read admin certificate: %s", err)
+	}
+	return cert
+}
+
+func marshalOrFail(t *testing.T, msg proto.Message) []byte {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Failed to marshal %T: %s", msg, err)
+	}
+	return b
+}
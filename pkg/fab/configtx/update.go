@@ -0,0 +1,230 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// ComputeUpdate computes the ConfigUpdate which changes the original configuration into the
+// updated one. The read set contains the versions of the elements the update depends on and
+// the write set contains the modified elements, whose versions are incremented.
+func ComputeUpdate(original, updated *Config) (*common.ConfigUpdate, error) {
+	if original == nil || updated == nil {
+		return nil, errors.New("original and updated config are required")
+	}
+	if original.channelID != updated.channelID {
+		return nil, errors.Errorf("original config is for channel [%s] but updated config is for channel [%s]", original.channelID, updated.channelID)
+	}
+	if original.config.ChannelGroup == nil {
+		return nil, errors.New("original config does not contain a channel group")
+	}
+	if updated.config.ChannelGroup == nil {
+		return nil, errors.New("updated config does not contain a channel group")
+	}
+
+	readSet, writeSet, groupUpdated := computeGroupUpdate(original.config.ChannelGroup, updated.config.ChannelGroup)
+	if !groupUpdated {
+		return nil, errors.New("no differences detected between original and updated config")
+	}
+
+	return &common.ConfigUpdate{
+		ChannelId: original.channelID,
+		ReadSet:   readSet,
+		WriteSet:  writeSet,
+	}, nil
+}
+
+// computeGroupUpdate returns the read and write set of a group and whether the group was modified
+func computeGroupUpdate(original, updated *common.ConfigGroup) (readSet, writeSet *common.ConfigGroup, groupUpdated bool) {
+	readSetPolicies, writeSetPolicies, sameSetPolicies, policiesMembersUpdated := computePoliciesMapUpdate(original.Policies, updated.Policies)
+	readSetValues, writeSetValues, sameSetValues, valuesMembersUpdated := computeValuesMapUpdate(original.Values, updated.Values)
+	readSetGroups, writeSetGroups, sameSetGroups, groupsMembersUpdated := computeGroupsMapUpdate(original.Groups, updated.Groups)
+
+	// The version of the group itself only changes if elements are added or removed or the mod policy changes
+	if !(policiesMembersUpdated || valuesMembersUpdated || groupsMembersUpdated || original.ModPolicy != updated.ModPolicy) {
+		if len(readSetPolicies) == 0 && len(writeSetPolicies) == 0 &&
+			len(readSetValues) == 0 && len(writeSetValues) == 0 &&
+			len(readSetGroups) == 0 && len(writeSetGroups) == 0 {
+			return &common.ConfigGroup{Version: original.Version}, &common.ConfigGroup{Version: original.Version}, false
+		}
+
+		return &common.ConfigGroup{
+			Version:  original.Version,
+			Policies: readSetPolicies,
+			Values:   readSetValues,
+			Groups:   readSetGroups,
+		}, &common.ConfigGroup{
+			Version:  original.Version,
+			Policies: writeSetPolicies,
+			Values:   writeSetValues,
+			Groups:   writeSetGroups,
+		}, true
+	}
+
+	// The unchanged elements must be part of both sets since the write set replaces the whole group
+	for k, samePolicy := range sameSetPolicies {
+		readSetPolicies[k] = samePolicy
+		writeSetPolicies[k] = samePolicy
+	}
+	for k, sameValue := range sameSetValues {
+		readSetValues[k] = sameValue
+		writeSetValues[k] = sameValue
+	}
+	for k, sameGroup := range sameSetGroups {
+		readSetGroups[k] = sameGroup
+		writeSetGroups[k] = sameGroup
+	}
+
+	return &common.ConfigGroup{
+		Version:  original.Version,
+		Policies: readSetPolicies,
+		Values:   readSetValues,
+		Groups:   readSetGroups,
+	}, &common.ConfigGroup{
+		Version:   original.Version + 1,
+		Policies:  writeSetPolicies,
+		Values:    writeSetValues,
+		Groups:    writeSetGroups,
+		ModPolicy: updated.ModPolicy,
+	}, true
+}
+
+func computePoliciesMapUpdate(original, updated map[string]*common.ConfigPolicy) (readSet, writeSet, sameSet map[string]*common.ConfigPolicy, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigPolicy)
+	writeSet = make(map[string]*common.ConfigPolicy)
+	sameSet = make(map[string]*common.ConfigPolicy)
+
+	for name, originalPolicy := range original {
+		updatedPolicy, ok := updated[name]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		if originalPolicy.ModPolicy == updatedPolicy.ModPolicy && proto.Equal(originalPolicy.Policy, updatedPolicy.Policy) {
+			sameSet[name] = &common.ConfigPolicy{Version: originalPolicy.Version}
+			continue
+		}
+
+		writeSet[name] = &common.ConfigPolicy{
+			Version:   originalPolicy.Version + 1,
+			ModPolicy: updatedPolicy.ModPolicy,
+			Policy:    updatedPolicy.Policy,
+		}
+	}
+
+	for name, updatedPolicy := range updated {
+		if _, ok := original[name]; ok {
+			continue
+		}
+
+		updatedMembers = true
+		writeSet[name] = &common.ConfigPolicy{
+			Version:   0,
+			ModPolicy: updatedPolicy.ModPolicy,
+			Policy:    updatedPolicy.Policy,
+		}
+	}
+
+	return
+}
+
+func computeValuesMapUpdate(original, updated map[string]*common.ConfigValue) (readSet, writeSet, sameSet map[string]*common.ConfigValue, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigValue)
+	writeSet = make(map[string]*common.ConfigValue)
+	sameSet = make(map[string]*common.ConfigValue)
+
+	for name, originalValue := range original {
+		updatedValue, ok := updated[name]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		if originalValue.ModPolicy == updatedValue.ModPolicy && bytes.Equal(originalValue.Value, updatedValue.Value) {
+			sameSet[name] = &common.ConfigValue{Version: originalValue.Version}
+			continue
+		}
+
+		writeSet[name] = &common.ConfigValue{
+			Version:   originalValue.Version + 1,
+			ModPolicy: updatedValue.ModPolicy,
+			Value:     updatedValue.Value,
+		}
+	}
+
+	for name, updatedValue := range updated {
+		if _, ok := original[name]; ok {
+			continue
+		}
+
+		updatedMembers = true
+		writeSet[name] = &common.ConfigValue{
+			Version:   0,
+			ModPolicy: updatedValue.ModPolicy,
+			Value:     updatedValue.Value,
+		}
+	}
+
+	return
+}
+
+func computeGroupsMapUpdate(original, updated map[string]*common.ConfigGroup) (readSet, writeSet, sameSet map[string]*common.ConfigGroup, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigGroup)
+	writeSet = make(map[string]*common.ConfigGroup)
+	sameSet = make(map[string]*common.ConfigGroup)
+
+	for name, originalGroup := range original {
+		updatedGroup, ok := updated[name]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		groupReadSet, groupWriteSet, groupUpdated := computeGroupUpdate(originalGroup, updatedGroup)
+		if !groupUpdated {
+			sameSet[name] = groupReadSet
+			continue
+		}
+
+		readSet[name] = groupReadSet
+		writeSet[name] = groupWriteSet
+	}
+
+	for name, updatedGroup := range updated {
+		if _, ok := original[name]; ok {
+			continue
+		}
+
+		updatedMembers = true
+		// A new group is written in full
+		_, groupWriteSet, _ := computeGroupUpdate(newConfigGroup(), updatedGroup)
+		writeSet[name] = &common.ConfigGroup{
+			Version:   0,
+			ModPolicy: updatedGroup.ModPolicy,
+			Policies:  groupWriteSet.Policies,
+			Values:    groupWriteSet.Values,
+			Groups:    groupWriteSet.Groups,
+		}
+	}
+
+	return
+}
+
+func newConfigGroup() *common.ConfigGroup {
+	return &common.ConfigGroup{
+		Groups:   make(map[string]*common.ConfigGroup),
+		Values:   make(map[string]*common.ConfigValue),
+		Policies: make(map[string]*common.ConfigPolicy),
+	}
+}
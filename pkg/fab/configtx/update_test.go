@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+)
+
+func TestComputeUpdateModifiedValue(t *testing.T) {
+	original := newTestConfig(t)
+	updated := original.Clone()
+
+	err := updated.SetBatchSize(&orderer.BatchSize{MaxMessageCount: 50, AbsoluteMaxBytes: 2000, PreferredMaxBytes: 1000})
+	if err != nil {
+		t.Fatalf("Failed to set batch size: %s", err)
+	}
+
+	update, err := ComputeUpdate(original, updated)
+	if err != nil {
+		t.Fatalf("Failed to compute update: %s", err)
+	}
+	assert.Equal(t, testChannelID, update.ChannelId)
+
+	// Only the modified value is written; the groups keep their versions
+	ordererWriteSet := update.WriteSet.Groups[channelConfig.OrdererGroupKey]
+	if !assert.NotNil(t, ordererWriteSet) {
+		t.FailNow()
+	}
+	assert.Equal(t, uint64(0), update.WriteSet.Version)
+	assert.Equal(t, uint64(0), ordererWriteSet.Version)
+	if !assert.Len(t, ordererWriteSet.Values, 1) {
+		t.FailNow()
+	}
+	batchSize := ordererWriteSet.Values[channelConfig.BatchSizeKey]
+	assert.Equal(t, uint64(1), batchSize.Version)
+	assert.Equal(t, "Admins", batchSize.ModPolicy)
+
+	assert.Empty(t, update.ReadSet.Groups[channelConfig.OrdererGroupKey].Values)
+	assert.Nil(t, update.WriteSet.Groups[ApplicationGroupKey], "expected unmodified group to be omitted")
+}
+
+func TestComputeUpdateAddAndRemoveOrg(t *testing.T) {
+	original := newTestConfig(t)
+	updated := original.Clone()
+
+	org3 := proto.Clone(original.ApplicationOrg("Org1MSP")).(*common.ConfigGroup)
+	if err := updated.SetApplicationOrg("Org3MSP", org3); err != nil {
+		t.Fatalf("Failed to add org: %s", err)
+	}
+	if err := updated.RemoveApplicationOrg("Org2MSP"); err != nil {
+		t.Fatalf("Failed to remove org: %s", err)
+	}
+
+	update, err := ComputeUpdate(original, updated)
+	if err != nil {
+		t.Fatalf("Failed to compute update: %s", err)
+	}
+
+	// The membership of the application group changed, so its version is incremented
+	// and the write set contains every member of the group
+	application := update.WriteSet.Groups[ApplicationGroupKey]
+	if !assert.NotNil(t, application) {
+		t.FailNow()
+	}
+	assert.Equal(t, uint64(1), application.Version)
+	assert.Equal(t, "Admins", application.ModPolicy)
+	assert.Len(t, application.Groups, 2)
+	assert.NotNil(t, application.Groups["Org1MSP"])
+	assert.Nil(t, application.Groups["Org2MSP"])
+	assert.Len(t, application.Policies, 3)
+
+	newOrg := application.Groups["Org3MSP"]
+	if !assert.NotNil(t, newOrg) {
+		t.FailNow()
+	}
+	assert.Equal(t, uint64(0), newOrg.Version)
+	assert.NotEmpty(t, newOrg.Values[channelConfig.MSPKey].Value, "expected new org to be written in full")
+
+	readSet := update.ReadSet.Groups[ApplicationGroupKey]
+	assert.Equal(t, uint64(0), readSet.Version)
+	assert.NotNil(t, readSet.Groups["Org1MSP"])
+}
+
+func TestComputeUpdateErrors(t *testing.T) {
+	original := newTestConfig(t)
+
+	_, err := ComputeUpdate(original, original.Clone())
+	assert.NotNil(t, err, "expected error if nothing changed")
+
+	other, err := New("otherchannel", original.Config())
+	if err != nil {
+		t.Fatalf("Failed to create config: %s", err)
+	}
+	_, err = ComputeUpdate(original, other)
+	assert.NotNil(t, err, "expected error for different channels")
+
+	_, err = ComputeUpdate(nil, original)
+	assert.NotNil(t, err, "expected error for missing config")
+}
@@ -41,18 +41,23 @@ type DeliverConnection struct {
 	comm.GRPCConnection
 }
 
-// StreamProvider creates a deliver stream
-type StreamProvider func(pb.DeliverClient) (deliverStream, error)
+// StreamProvider creates a deliver stream. The returned cancel function
+// terminates the stream.
+type StreamProvider func(pb.DeliverClient) (deliverStream, func(), error)
 
 var (
 	// Deliver creates a Deliver stream
-	Deliver = func(client pb.DeliverClient) (deliverStream, error) {
-		return client.Deliver(context.Background())
+	Deliver = func(client pb.DeliverClient) (deliverStream, func(), error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := client.Deliver(ctx)
+		return stream, cancel, err
 	}
 
 	// DeliverFiltered creates a DeliverFiltered stream
-	DeliverFiltered = func(client pb.DeliverClient) (deliverStream, error) {
-		return client.DeliverFiltered(context.Background())
+	DeliverFiltered = func(client pb.DeliverClient) (deliverStream, func(), error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := client.DeliverFiltered(ctx)
+		return stream, cancel, err
 	}
 )
 
@@ -64,7 +69,7 @@ func New(ctx fabcontext.Context, channelID string, streamProvider StreamProvider
 
 	connect, err := comm.NewConnection(
 		ctx, channelID,
-		func(grpcconn *grpc.ClientConn) (grpc.ClientStream, error) {
+		func(grpcconn *grpc.ClientConn) (grpc.ClientStream, func(), error) {
 			return streamProvider(pb.NewDeliverClient(grpcconn))
 		},
 		url, opts...,
@@ -7,16 +7,20 @@ SPDX-License-Identifier: Apache-2.0
 package deliverclient
 
 import (
+	"sync"
 	"testing"
 	"time"
 
 	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
 	clientmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/mocks"
 	delivermocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/endpoint"
 	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
 	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
 	fabclientmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
@@ -266,6 +270,101 @@ func testReconnect(t *testing.T, reconnect bool, maxReconnectAttempts uint, expe
 	}
 }
 
+// TestFailover tests that the event client connects to another event source of the
+// organization, which is discovered from the channel configuration, when an event source
+// is unavailable. This applies to the initial connection as well as to reconnects.
+func TestFailover(t *testing.T) {
+	eventSource1 := "grpc://peer1.example.com:7051"
+	eventSource2 := "grpc://peer2.example.com:7051"
+	discovery, err := endpoint.NewDiscoveryService(
+		fabclientmocks.NewMockConfigWithChannelPeers(
+			newEventSource(eventSource1, "Org1MSP"),
+			newEventSource("grpc://peer1.org2.example.com:7051", "Org2MSP"),
+			newEventSource(eventSource2, "Org1MSP"),
+		),
+		"mychannel", "Org1MSP",
+	)
+	if err != nil {
+		t.Fatalf("error creating discovery service: %s", err)
+	}
+
+	var mutex sync.Mutex
+	var connected []string
+	var connection clientmocks.Connection
+	unavailable := eventSource1
+	ledger := servicemocks.NewMockLedger(servicemocks.BlockEventFactory)
+	connectionProvider := func(channelID string, ctx fabcontext.Context, peer fab.Peer) (api.Connection, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if peer.URL() == unavailable {
+			return nil, errors.Errorf("event source %s is unavailable", peer.URL())
+		}
+		connected = append(connected, peer.URL())
+		connection = delivermocks.NewConnection(clientmocks.WithLedger(ledger))
+		return connection, nil
+	}
+
+	connectch := make(chan *fab.ConnectionEvent)
+	eventClient, err := newClient(
+		newMockContext(), "mychannel", discovery,
+		withConnectionProvider(connectionProvider, true),
+		esdispatcher.WithEventConsumerTimeout(3*time.Second),
+		client.WithReconnect(true),
+		client.WithReconnectInitialDelay(0),
+		client.WithMaxConnectAttempts(2),
+		client.WithMaxReconnectAttempts(2),
+		client.WithTimeBetweenConnectAttempts(time.Millisecond),
+		client.WithConnectionEvent(connectch),
+	)
+	if err != nil {
+		t.Fatalf("error creating channel event client: %s", err)
+	}
+	if err := eventClient.Connect(); err != nil {
+		t.Fatalf("error connecting channel event client: %s", err)
+	}
+	defer eventClient.Close()
+
+	outcomech := make(chan clientmocks.Outcome)
+	go listenConnection(connectch, outcomech)
+
+	// the first event source fails and the second one becomes available again
+	mutex.Lock()
+	if len(connected) != 1 || connected[0] != eventSource2 {
+		mutex.Unlock()
+		t.Fatalf("expecting connection to [%s] but got %v", eventSource2, connected)
+	}
+	unavailable = eventSource2
+	current := connection
+	mutex.Unlock()
+
+	current.ProduceEvent(dispatcher.NewDisconnectedEvent(errors.New("testing failover")))
+
+	select {
+	case outcome := <-outcomech:
+		if outcome != clientmocks.ReconnectedOutcome {
+			t.Fatalf("expecting outcome [%s] but got [%s]", clientmocks.ReconnectedOutcome, outcome)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for reconnect")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(connected) != 2 || connected[1] != eventSource1 {
+		t.Fatalf("expecting reconnection to [%s] but got %v", eventSource1, connected)
+	}
+}
+
+func newEventSource(url string, mspID string) core.ChannelPeer {
+	return core.ChannelPeer{
+		PeerChannelConfig: core.PeerChannelConfig{EventSource: true},
+		NetworkPeer: core.NetworkPeer{
+			PeerConfig: core.PeerConfig{URL: url},
+			MspID:      mspID,
+		},
+	}
+}
+
 // testReconnectRegistration tests the scenario when an events client is registered to receive events and the connection to the
 // event service is lost. After the connection is re-established, events should once again be received without the caller having to
 // re-register for those events.
@@ -48,6 +48,12 @@ func InfoFrom(fromBlock uint64) *ab.SeekInfo {
 	return newSeekInfo(seekFromPos(fromBlock), maxPos)
 }
 
+// InfoRange returns a SeekInfo struct that indicates to the deliver server
+// that we want the blocks from fromBlock up to and including toBlock
+func InfoRange(fromBlock uint64, toBlock uint64) *ab.SeekInfo {
+	return newSeekInfo(seekFromPos(fromBlock), seekFromPos(toBlock))
+}
+
 func seekFromPos(fromBlock uint64) *ab.SeekPosition {
 	return &ab.SeekPosition{
 		Type: &ab.SeekPosition_Specified{
@@ -0,0 +1,52 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endpoint
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/pkg/errors"
+)
+
+// DiscoveryService returns the peers of a channel that are configured as event sources.
+// The event client chooses among these peers when it connects, so that it can fail over
+// to another event source when the connection to a peer is lost.
+type DiscoveryService struct {
+	peers []fab.Peer
+}
+
+// NewDiscoveryService returns a discovery service containing the event-source peers of the given
+// channel which belong to the organization with the given MSP ID.
+func NewDiscoveryService(config core.Config, channelID string, mspID string) (*DiscoveryService, error) {
+	chPeers, err := config.ChannelPeers(channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "read configuration for channel peers failed")
+	}
+
+	var peers []fab.Peer
+	for _, p := range chPeers {
+		if !p.EventSource || p.MspID != mspID {
+			continue
+		}
+		ep, err := FromPeerConfig(config, p.NetworkPeer)
+		if err != nil {
+			return nil, errors.WithMessage(err, "unable to create event endpoint")
+		}
+		peers = append(peers, ep)
+	}
+
+	if len(peers) == 0 {
+		return nil, errors.Errorf("unable to find event source for channel [%s]", channelID)
+	}
+
+	return &DiscoveryService{peers: peers}, nil
+}
+
+// GetPeers returns the event-source peers
+func (s *DiscoveryService) GetPeers() ([]fab.Peer, error) {
+	return s.peers, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endpoint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+const testChannelID = "mychannel"
+
+func newChannelPeer(url string, mspID string, eventSource bool) core.ChannelPeer {
+	return core.ChannelPeer{
+		PeerChannelConfig: core.PeerChannelConfig{EventSource: eventSource},
+		NetworkPeer: core.NetworkPeer{
+			PeerConfig: core.PeerConfig{URL: url, EventURL: url + "/events"},
+			MspID:      mspID,
+		},
+	}
+}
+
+func TestDiscoveryService(t *testing.T) {
+	config := mocks.NewMockConfigWithChannelPeers(
+		newChannelPeer("peer1.org1.example.com:7051", "Org1MSP", true),
+		newChannelPeer("peer2.org1.example.com:7051", "Org1MSP", false),
+		newChannelPeer("peer3.org1.example.com:7051", "Org1MSP", true),
+		newChannelPeer("peer1.org2.example.com:7051", "Org2MSP", true),
+	)
+
+	discovery, err := NewDiscoveryService(config, testChannelID, "Org1MSP")
+	if err != nil {
+		t.Fatalf("error creating discovery service: %s", err)
+	}
+	peers, err := discovery.GetPeers()
+	if err != nil {
+		t.Fatalf("error getting peers: %s", err)
+	}
+
+	// the event client fails over among the event sources of its organization
+	var urls []string
+	for _, p := range peers {
+		urls = append(urls, p.URL())
+		endpoint, ok := p.(*EventEndpoint)
+		if assert.True(t, ok, "expected event endpoint") {
+			assert.Equal(t, p.URL()+"/events", endpoint.EventURL())
+		}
+	}
+	assert.Equal(t, []string{"peer1.org1.example.com:7051", "peer3.org1.example.com:7051"}, urls)
+
+	discovery, err = NewDiscoveryService(config, testChannelID, "Org2MSP")
+	if err != nil {
+		t.Fatalf("error creating discovery service: %s", err)
+	}
+	peers, _ = discovery.GetPeers()
+	if assert.Len(t, peers, 1) {
+		assert.Equal(t, "peer1.org2.example.com:7051", peers[0].URL())
+	}
+}
+
+func TestDiscoveryServiceNoEventSource(t *testing.T) {
+	config := mocks.NewMockConfigWithChannelPeers(
+		newChannelPeer("peer1.org1.example.com:7051", "Org1MSP", false),
+		newChannelPeer("peer1.org2.example.com:7051", "Org2MSP", true),
+	)
+	_, err := NewDiscoveryService(config, testChannelID, "Org1MSP")
+	assert.Error(t, err, "expected error without event source of the organization")
+
+	_, err = NewDiscoveryService(mocks.NewMockConfigCustomized(false, false, true), testChannelID, "Org1MSP")
+	assert.Error(t, err, "expected error reading channel peers")
+}
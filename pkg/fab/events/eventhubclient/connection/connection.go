@@ -42,8 +42,10 @@ func New(ctx fabcontext.Context, channelID string, url string, opts ...options.O
 
 	connect, err := comm.NewConnection(
 		ctx, channelID,
-		func(grpcconn *grpc.ClientConn) (grpc.ClientStream, error) {
-			return pb.NewEventsClient(grpcconn).Chat(context.Background())
+		func(grpcconn *grpc.ClientConn) (grpc.ClientStream, func(), error) {
+			ctx, cancel := context.WithCancel(context.Background())
+			stream, err := pb.NewEventsClient(grpcconn).Chat(ctx)
+			return stream, cancel, err
 		},
 		url, opts...,
 	)
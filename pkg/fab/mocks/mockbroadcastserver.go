@@ -11,6 +11,7 @@ import (
 
 	"fmt"
 	"net"
+	"time"
 
 	po "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
@@ -36,6 +37,7 @@ type MockBroadcastServer struct {
 	DeliverError                 error
 	BroadcastInternalServerError bool
 	DeliverResponse              *po.DeliverResponse
+	DeliverDelay                 time.Duration
 	BroadcastError               error
 }
 
@@ -66,11 +68,13 @@ func (m *MockBroadcastServer) Deliver(server po.AtomicBroadcast_DeliverServer) e
 
 	if m.DeliverResponse != nil {
 		server.Recv()
+		time.Sleep(m.DeliverDelay)
 		server.SendMsg(m.DeliverResponse)
 		return nil
 	}
 
 	server.Recv()
+	time.Sleep(m.DeliverDelay)
 	server.Send(TestBlock)
 
 	return nil
@@ -16,6 +16,7 @@ import (
 type MockChannelCfg struct {
 	MockName        string
 	MockMsps        []*msp.MSPConfig
+	MockOrdererMsps []string
 	MockAnchorPeers []*fab.OrgAnchorPeer
 	MockOrderers    []string
 	MockVersions    *fab.Versions
@@ -36,6 +37,11 @@ func (cfg *MockChannelCfg) Msps() []*msp.MSPConfig {
 	return cfg.MockMsps
 }
 
+// OrdererMsps returns the IDs of the orderer MSPs
+func (cfg *MockChannelCfg) OrdererMsps() []string {
+	return cfg.MockOrdererMsps
+}
+
 // AnchorPeers returns anchor peers
 func (cfg *MockChannelCfg) AnchorPeers() []*fab.OrgAnchorPeer {
 	return cfg.MockAnchorPeers
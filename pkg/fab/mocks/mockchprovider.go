@@ -17,6 +17,7 @@ type MockChannelProvider struct {
 	ctx        context.ProviderContext
 	channels   map[string]fab.Channel
 	transactor fab.Transactor
+	ledger     fab.ChannelLedger
 }
 
 // MockChannelService holds a mock channel service.
@@ -48,6 +49,11 @@ func (cp *MockChannelProvider) SetTransactor(transactor fab.Transactor) {
 	cp.transactor = transactor
 }
 
+// SetLedger sets the ledger returned by all mock channel services
+func (cp *MockChannelProvider) SetLedger(ledger fab.ChannelLedger) {
+	cp.ledger = ledger
+}
+
 // ChannelService returns a mock ChannelService
 func (cp *MockChannelProvider) ChannelService(ic context.IdentityContext, channelID string) (fab.ChannelService, error) {
 	cs := MockChannelService{
@@ -63,6 +69,11 @@ func (cs *MockChannelService) EventHub() (fab.EventHub, error) {
 	return NewMockEventHub(), nil
 }
 
+// EventService ...
+func (cs *MockChannelService) EventService() (fab.EventService, error) {
+	return NewMockEventService(), nil
+}
+
 // Channel ...
 func (cs *MockChannelService) Channel() (fab.Channel, error) {
 	ch, ok := cs.provider.channels[cs.channelID]
@@ -90,5 +101,5 @@ func (cs *MockChannelService) Config() (fab.ChannelConfig, error) {
 
 // Ledger ...
 func (cs *MockChannelService) Ledger() (fab.ChannelLedger, error) {
-	return nil, nil
+	return cs.provider.ledger, nil
 }
@@ -21,6 +21,7 @@ type MockConfig struct {
 	tlsEnabled       bool
 	mutualTLSEnabled bool
 	errorCase        bool
+	channelPeers     []config.ChannelPeer
 }
 
 // NewMockConfig ...
@@ -33,6 +34,11 @@ func NewMockConfigCustomized(tlsEnabled, mutualTLSEnabled, errorCase bool) confi
 	return &MockConfig{tlsEnabled: tlsEnabled, mutualTLSEnabled: mutualTLSEnabled, errorCase: errorCase}
 }
 
+// NewMockConfigWithChannelPeers returns a mock config which returns the given peers for every channel
+func NewMockConfigWithChannelPeers(channelPeers ...config.ChannelPeer) config.Config {
+	return &MockConfig{channelPeers: channelPeers}
+}
+
 // Client ...
 func (c *MockConfig) Client() (*config.ClientConfig, error) {
 	clientConfig := config.ClientConfig{}
@@ -211,7 +217,10 @@ func (c *MockConfig) ChannelConfig(name string) (*config.ChannelConfig, error) {
 
 // ChannelPeers returns the channel peers configuration
 func (c *MockConfig) ChannelPeers(name string) ([]config.ChannelPeer, error) {
-	return nil, nil
+	if c.errorCase {
+		return nil, errors.New("no channel peers")
+	}
+	return c.channelPeers, nil
 }
 
 // ChannelOrderers returns a list of channel orderers
@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/pkg/errors"
+)
+
+// TxStatusReg is a transaction status registration made with the MockEventService
+type TxStatusReg struct {
+	TxID    string
+	Eventch chan<- *fab.TxStatusEvent
+}
+
+// maxTxStatusRegistrations is the number of transaction status registrations which are
+// buffered by the MockEventService
+const maxTxStatusRegistrations = 100
+
+// MockEventService is a mock event service. Transaction status registrations are
+// reported to the TxStatusRegistrations channel so that tests can deliver the status,
+// and removed registrations are reported to the Unregistrations channel.
+type MockEventService struct {
+	TxStatusRegistrations chan *TxStatusReg
+	Unregistrations       chan fab.Registration
+}
+
+// NewMockEventService returns a new mock event service
+func NewMockEventService() *MockEventService {
+	return &MockEventService{
+		TxStatusRegistrations: make(chan *TxStatusReg, maxTxStatusRegistrations),
+		Unregistrations:       make(chan fab.Registration, maxTxStatusRegistrations),
+	}
+}
+
+// RegisterBlockEvent not implemented
+func (m *MockEventService) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+// RegisterFilteredBlockEvent not implemented
+func (m *MockEventService) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+// RegisterChaincodeEvent not implemented
+func (m *MockEventService) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+// RegisterTxStatusEvent registers for the status of the given transaction and reports
+// the registration to the TxStatusRegistrations channel. An error is returned if the
+// channel is full since nobody is reading the registrations.
+func (m *MockEventService) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	eventch := make(chan *fab.TxStatusEvent, 1)
+	reg := &TxStatusReg{TxID: txID, Eventch: eventch}
+	select {
+	case m.TxStatusRegistrations <- reg:
+	default:
+		return nil, nil, errors.New("too many pending transaction status registrations")
+	}
+	return reg, eventch, nil
+}
+
+// Unregister reports the registration to the Unregistrations channel. The registration
+// is dropped if the channel is full.
+func (m *MockEventService) Unregister(reg fab.Registration) {
+	select {
+	case m.Unregistrations <- reg:
+	default:
+	}
+}
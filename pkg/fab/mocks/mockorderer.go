@@ -81,7 +81,7 @@ func (o *mockOrderer) URL() string {
 
 // SendBroadcast accepts client broadcast calls and reports them to the listener channel
 // Returns the first enqueued error, or nil if there are no enqueued errors
-func (o *mockOrderer) SendBroadcast(envelope *fab.SignedEnvelope) (*common.Status, error) {
+func (o *mockOrderer) SendBroadcast(ctx context.Context, envelope *fab.SignedEnvelope) (*common.Status, error) {
 	// Report this call to the listener
 	if o.BroadcastListener != nil {
 		o.BroadcastQueue <- envelope
@@ -0,0 +1,147 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	ledger_util "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pp "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// MockEndorserTxBuilder is used to build a mock endorser transaction of version v1 of a chaincode.
+// The chaincode action of the transaction holds:
+//   - an empty read-write set of lscc
+//   - a read of key a at version 5:2, a write of key b, a delete of key c and a range query
+//     from k1 to k9 by the chaincode
+//   - a hashed write to collection coll1 of the chaincode
+//   - event event1 and a response with status 200 and payload "payload"
+type MockEndorserTxBuilder struct {
+	ChannelID    string
+	TxID         string
+	ChaincodeID  string
+	Timestamp    time.Time
+	Creator      []byte
+	Args         [][]byte
+	Endorsements []*pp.Endorsement
+}
+
+// NewMockSerializedIdentity returns a marshalled serialized identity
+func NewMockSerializedIdentity(mspID string, cert string) []byte {
+	return marshalOrPanic(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(cert)})
+}
+
+// NewMockBlockWithEnvelopes creates a mock block containing the envelopes. The transactions filter
+// holds the given validation code of each envelope.
+func NewMockBlockWithEnvelopes(number uint64, envelopes []*common.Envelope, txValidationCodes []pp.TxValidationCode) *common.Block {
+	block := newBlock(number, []byte{})
+	txsfltr := ledger_util.NewTxValidationFlags(len(envelopes))
+	for i, envelope := range envelopes {
+		block.Data.Data = append(block.Data.Data, marshalOrPanic(envelope))
+		txsfltr[i] = uint8(txValidationCodes[i])
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsfltr
+	return block
+}
+
+// BuildEnvelope creates a mock envelope of the endorser transaction
+func (b *MockEndorserTxBuilder) BuildEnvelope() *common.Envelope {
+	return b.BuildEnvelopeWithData(common.HeaderType_ENDORSER_TRANSACTION, marshalOrPanic(b.buildTransaction()))
+}
+
+// BuildEnvelopeWithData creates a mock envelope of the given type and data with the header of the transaction
+func (b *MockEndorserTxBuilder) BuildEnvelopeWithData(headerType common.HeaderType, data []byte) *common.Envelope {
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader:   marshalOrPanic(b.buildChannelHeader(headerType)),
+			SignatureHeader: marshalOrPanic(&common.SignatureHeader{Creator: b.Creator, Nonce: []byte("nonce")}),
+		},
+		Data: data,
+	}
+	return &common.Envelope{Payload: marshalOrPanic(payload)}
+}
+
+// BuildProposalResponsePayload creates the marshalled proposal response payload of the chaincode action
+func (b *MockEndorserTxBuilder) BuildProposalResponsePayload() []byte {
+	ccAction := &pp.ChaincodeAction{
+		Results:     marshalOrPanic(b.buildTxReadWriteSet()),
+		Events:      marshalOrPanic(&pp.ChaincodeEvent{ChaincodeId: b.ChaincodeID, EventName: "event1"}),
+		Response:    &pp.Response{Status: 200, Payload: []byte("payload")},
+		ChaincodeId: &pp.ChaincodeID{Name: b.ChaincodeID, Version: "v1"},
+	}
+	return marshalOrPanic(&pp.ProposalResponsePayload{Extension: marshalOrPanic(ccAction)})
+}
+
+func (b *MockEndorserTxBuilder) buildChannelHeader(headerType common.HeaderType) *common.ChannelHeader {
+	channelHeader := &common.ChannelHeader{Type: int32(headerType), ChannelId: b.ChannelID, TxId: b.TxID}
+	if !b.Timestamp.IsZero() {
+		timestamp, err := ptypes.TimestampProto(b.Timestamp)
+		if err != nil {
+			panic(err)
+		}
+		channelHeader.Timestamp = timestamp
+	}
+	if headerType == common.HeaderType_ENDORSER_TRANSACTION {
+		channelHeader.Extension = marshalOrPanic(&pp.ChaincodeHeaderExtension{ChaincodeId: &pp.ChaincodeID{Name: b.ChaincodeID}})
+	}
+	return channelHeader
+}
+
+func (b *MockEndorserTxBuilder) buildTransaction() *pp.Transaction {
+	invocationSpec := &pp.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pp.ChaincodeSpec{
+			ChaincodeId: &pp.ChaincodeID{Name: b.ChaincodeID},
+			Input:       &pp.ChaincodeInput{Args: b.Args},
+		},
+	}
+	ccActionPayload := &pp.ChaincodeActionPayload{
+		ChaincodeProposalPayload: marshalOrPanic(&pp.ChaincodeProposalPayload{Input: marshalOrPanic(invocationSpec)}),
+		Action: &pp.ChaincodeEndorsedAction{
+			ProposalResponsePayload: b.BuildProposalResponsePayload(),
+			Endorsements:            b.Endorsements,
+		},
+	}
+	return &pp.Transaction{
+		Actions: []*pp.TransactionAction{{
+			Header:  marshalOrPanic(&common.SignatureHeader{Creator: b.Creator}),
+			Payload: marshalOrPanic(ccActionPayload),
+		}},
+	}
+}
+
+func (b *MockEndorserTxBuilder) buildTxReadWriteSet() *rwset.TxReadWriteSet {
+	kvRWSet := &kvrwset.KVRWSet{
+		Reads: []*kvrwset.KVRead{{Key: "a", Version: &kvrwset.Version{BlockNum: 5, TxNum: 2}}},
+		Writes: []*kvrwset.KVWrite{
+			{Key: "b", Value: []byte("100")},
+			{Key: "c", IsDelete: true},
+		},
+		RangeQueriesInfo: []*kvrwset.RangeQueryInfo{{StartKey: "k1", EndKey: "k9", ItrExhausted: true}},
+	}
+	hashedRWSet := &kvrwset.HashedRWSet{
+		HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("keyhash"), ValueHash: []byte("valuehash")}},
+	}
+	return &rwset.TxReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsRwset: []*rwset.NsReadWriteSet{
+			{Namespace: "lscc", Rwset: marshalOrPanic(&kvrwset.KVRWSet{})},
+			{
+				Namespace: b.ChaincodeID,
+				Rwset:     marshalOrPanic(kvRWSet),
+				CollectionHashedRwset: []*rwset.CollectionHashedReadWriteSet{
+					{CollectionName: "coll1", HashedRwset: marshalOrPanic(hashedRWSet), PvtRwsetHash: []byte("pvthash")},
+				},
+			},
+		},
+	}
+}
@@ -12,8 +12,8 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/urlutil"
+	fabcomm "github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/spf13/cast"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 )
@@ -22,11 +22,12 @@ import (
 // Deprecated: use orderer.New() instead
 func NewOrderer(url string, certPath string, serverHostOverride string, config core.Config,
 	kap keepalive.ClientParameters) (*Orderer, error) {
-	var opts []grpc.DialOption
-
-	timeout := config.TimeoutOrDefault(core.OrdererConnection)
-	if kap.Time > 0 || kap.Timeout > 0 {
-		opts = append(opts, grpc.WithKeepaliveParams(kap))
+	orderer := &Orderer{
+		config:      config,
+		url:         urlutil.ToAddress(url),
+		serverName:  serverHostOverride,
+		dialOpts:    fabcomm.DialOpts{KeepAlive: kap},
+		dialTimeout: config.TimeoutOrDefault(core.OrdererConnection),
 	}
 	if urlutil.IsTLSEnabled(url) {
 		certConfig := core.TLSConfig{Path: certPath}
@@ -41,11 +42,12 @@ func NewOrderer(url string, certPath string, serverHostOverride string, config c
 			return nil, err
 		}
 
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
-	} else {
-		opts = append(opts, grpc.WithInsecure())
+		orderer.tlsCACert = certificate
+		orderer.transportCredentials = credentials.NewTLS(tlsConfig)
+		orderer.clientCerts = tlsConfig.Certificates
+		orderer.secured = true
 	}
-	return &Orderer{url: urlutil.ToAddress(url), grpcDialOption: opts, dialTimeout: timeout}, nil
+	return orderer, nil
 }
 
 // NewOrdererFromConfig returns an Orderer instance constructed from orderer config
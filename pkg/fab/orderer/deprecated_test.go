@@ -9,6 +9,7 @@ SPDX-License-Identifier: Apache-2.0
 package orderer
 
 import (
+	reqContext "context"
 	"strings"
 	"testing"
 	"time"
@@ -123,14 +124,14 @@ func TestDeprecatedSendBroadcast(t *testing.T) {
 	_, addr := startMockServer(t, grpcServer)
 
 	orderer, _ := NewOrderer("grpc://"+addr, "", "", mocks.NewMockConfig(), kap)
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 
 	if err != nil {
 		t.Fatalf("Test SendBroadcast was not supposed to fail")
 	}
 
 	orderer, _ = NewOrderer(testOrdererURL+"Test", "", "", mocks.NewMockConfig(), kap)
-	_, err = orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err = orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 
 	if err == nil || !strings.HasPrefix(err.Error(), "NewAtomicBroadcastClient") {
 		t.Fatalf("Test SendBroadcast was supposed to fail with expected error, instead it fail with [%s] error", err)
@@ -160,7 +161,8 @@ func TestDeprecatedSendDeliverServerBadResponse(t *testing.T) {
 	case block := <-blocks:
 		t.Fatalf("This usecase was not supposed to receive blocks : %#v", block)
 	case err := <-errors:
-		if err.Error() != "error status from ordering service BAD_REQUEST" {
+		statusError, ok := status.FromError(err)
+		if !ok || statusError.Group != status.OrdererServerStatus || statusError.Code != int32(common.Status_BAD_REQUEST) {
 			t.Fatalf("Ordering service error is not received as expected, %s", err)
 		}
 	case <-time.After(time.Second * 5):
@@ -236,7 +238,7 @@ func TestDeprecatedSendBroadcastServerBadResponse(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := NewOrderer("grpc://"+addr, "", "", mocks.NewMockConfig(), kap)
 
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 
 	if err == nil {
 		t.Fatalf("Expected error")
@@ -258,7 +260,7 @@ func TestDeprecatedSendBroadcastError(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := NewOrderer("grpc://"+addr, "", "", mocks.NewMockConfig(), kap)
 
-	status, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	status, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 
 	if err == nil || status != nil {
 		t.Fatalf("expected Send Broadcast to fail with error, but got %s", err)
@@ -8,6 +8,7 @@ package orderer
 
 import (
 	grpcContext "context"
+	"crypto/tls"
 	"time"
 
 	"google.golang.org/grpc"
@@ -18,6 +19,7 @@ import (
 	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	fabcomm "github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/spf13/cast"
 
@@ -38,11 +40,13 @@ type Orderer struct {
 	url                  string
 	tlsCACert            *x509.Certificate
 	serverName           string
-	grpcDialOption       []grpc.DialOption
+	connector            *fabcomm.CachingConnector
+	dialOpts             fabcomm.DialOpts
 	kap                  keepalive.ClientParameters
 	dialTimeout          time.Duration
 	failFast             bool
 	transportCredentials credentials.TransportCredentials
+	clientCerts          []tls.Certificate
 	secured              bool
 	allowInsecure        bool
 }
@@ -61,11 +65,7 @@ func New(config core.Config, opts ...Option) (*Orderer, error) {
 			return nil, err
 		}
 	}
-	var grpcOpts []grpc.DialOption
-	if orderer.kap.Time > 0 {
-		grpcOpts = append(grpcOpts, grpc.WithKeepaliveParams(orderer.kap))
-	}
-	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.FailFast(orderer.failFast)))
+	orderer.dialOpts = fabcomm.DialOpts{FailFast: orderer.failFast, KeepAlive: orderer.kap}
 	orderer.dialTimeout = config.TimeoutOrDefault(core.OrdererConnection)
 
 	//tls config
@@ -74,8 +74,8 @@ func New(config core.Config, opts ...Option) (*Orderer, error) {
 		return nil, err
 	}
 
-	orderer.grpcDialOption = grpcOpts
 	orderer.transportCredentials = credentials.NewTLS(tlsConfig)
+	orderer.clientCerts = tlsConfig.Certificates
 	orderer.secured = urlutil.AttemptSecured(orderer.url)
 	orderer.url = urlutil.ToAddress(orderer.url)
 
@@ -118,6 +118,16 @@ func WithInsecure() Option {
 	}
 }
 
+// WithConnector is a functional option for the orderer.New constructor that configures the cache of
+// the orderer's GRPC connections. Without a connector each request uses a new connection.
+func WithConnector(connector *fabcomm.CachingConnector) Option {
+	return func(o *Orderer) error {
+		o.connector = connector
+
+		return nil
+	}
+}
+
 // FromOrdererConfig is a functional option for the orderer.New constructor that configures a new orderer
 // from a apiconfig.OrdererConfig struct
 func FromOrdererConfig(ordererCfg *core.OrdererConfig) Option {
@@ -208,30 +218,25 @@ func (o *Orderer) URL() string {
 }
 
 // SendBroadcast Send the created transaction to Orderer.
-func (o *Orderer) SendBroadcast(envelope *fab.SignedEnvelope) (*common.Status, error) {
-	return o.sendBroadcast(envelope, o.secured)
+// The broadcast is abandoned if the given context is cancelled or its deadline expires.
+func (o *Orderer) SendBroadcast(reqCtx grpcContext.Context, envelope *fab.SignedEnvelope) (*common.Status, error) {
+	return o.sendBroadcast(reqCtx, envelope, o.secured)
 }
 
 // SendBroadcast Send the created transaction to Orderer.
-func (o *Orderer) sendBroadcast(envelope *fab.SignedEnvelope, secured bool) (*common.Status, error) {
-	var grpcOpts []grpc.DialOption
-	grpcOpts = append(grpcOpts, o.grpcDialOption...)
-	if secured {
-		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(o.transportCredentials))
-	} else {
-		grpcOpts = append(grpcOpts, grpc.WithInsecure())
-	}
-
-	ctx := grpcContext.Background()
-	ctx, cancel := grpcContext.WithTimeout(ctx, o.dialTimeout)
+func (o *Orderer) sendBroadcast(reqCtx grpcContext.Context, envelope *fab.SignedEnvelope, secured bool) (*common.Status, error) {
+	ctx, cancel := grpcContext.WithTimeout(reqCtx, o.dialTimeout)
 	defer cancel()
 
-	conn, err := grpc.DialContext(ctx, o.url, grpcOpts...)
-
+	connector := o.connector
+	conn, err := connector.DialContext(ctx, o.connectionKey(secured), o.credentials(secured))
 	if err != nil {
+		if ctxErr := reqCtx.Err(); ctxErr != nil {
+			return nil, status.NewFromContextError(status.OrdererClientStatus, ctxErr, []interface{}{o.url})
+		}
 		return nil, status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), nil)
 	}
-	defer conn.Close()
+	defer connector.ReleaseConn(conn)
 	broadcastStream, err := ab.NewAtomicBroadcastClient(conn).Broadcast(ctx)
 	if err != nil {
 		rpcStatus, ok := grpcstatus.FromError(err)
@@ -239,10 +244,13 @@ func (o *Orderer) sendBroadcast(envelope *fab.SignedEnvelope, secured bool) (*co
 			err = status.NewFromGRPCStatus(rpcStatus)
 		}
 		logger.Error("NewAtomicBroadcastClient failed, cause : ", err)
+		if ctxErr := reqCtx.Err(); ctxErr != nil {
+			return nil, status.NewFromContextError(status.OrdererClientStatus, ctxErr, []interface{}{o.url})
+		}
 		if secured && o.allowInsecure {
 			//If secured mode failed and allow insecure is enabled then retry in insecure mode
 			logger.Debug("Secured sendBroadcast failed, attempting insecured")
-			return o.sendBroadcast(envelope, false)
+			return o.sendBroadcast(reqCtx, envelope, false)
 		}
 		return nil, errors.Wrap(err, "NewAtomicBroadcastClient failed")
 	}
@@ -283,11 +291,29 @@ func (o *Orderer) sendBroadcast(envelope *fab.SignedEnvelope, secured bool) (*co
 	}
 	broadcastStream.CloseSend()
 	<-done
+	if broadcastErr != nil {
+		if ctxErr := reqCtx.Err(); ctxErr != nil {
+			return nil, status.NewFromContextError(status.OrdererClientStatus, ctxErr, []interface{}{o.url})
+		}
+	}
 	return broadcastStatus, broadcastErr
 }
 
+func (o *Orderer) connectionKey(secured bool) fabcomm.ConnectionKey {
+	return fabcomm.NewConnectionKey(o.url, o.tlsCACert, o.clientCerts, o.serverName, secured, o.dialOpts)
+}
+
+func (o *Orderer) credentials(secured bool) grpc.DialOption {
+	if secured {
+		return grpc.WithTransportCredentials(o.transportCredentials)
+	}
+	return grpc.WithInsecure()
+}
+
 // SendDeliver sends a deliver request to the ordering service and returns the
-// blocks requested
+// blocks requested. A status other than SUCCESS is reported as a status error of
+// the OrdererServerStatus group. The stream is not bounded by the dial timeout and
+// must be terminated with the returned cancel function.
 // envelope: contains the seek request for blocks
 func (o *Orderer) SendDeliver(envelope *fab.SignedEnvelope) (chan *common.Block, chan error, grpcContext.CancelFunc) {
 	return o.sendDeliver(envelope, o.secured)
@@ -300,19 +326,15 @@ func (o *Orderer) sendDeliver(envelope *fab.SignedEnvelope, secured bool) (chan
 	responses := make(chan *common.Block)
 	errs := make(chan error, 1)
 
-	// Establish connection to Ordering Service
-	var grpcOpts []grpc.DialOption
-	grpcOpts = append(grpcOpts, o.grpcDialOption...)
-	if secured {
-		grpcOpts = append(o.grpcDialOption, grpc.WithTransportCredentials(o.transportCredentials))
-	} else {
-		grpcOpts = append(o.grpcDialOption, grpc.WithInsecure())
-	}
-
-	ctx := grpcContext.Background()
-	ctx, cancel := grpcContext.WithTimeout(ctx, o.dialTimeout)
+	// Establish connection to Ordering Service. The dial timeout bounds the time until
+	// the request is sent but not the stream, which lasts until all blocks are received
+	// or it is canceled.
+	ctx, cancel := grpcContext.WithCancel(grpcContext.Background())
+	dialTimer := time.AfterFunc(o.dialTimeout, cancel)
+	defer dialTimer.Stop()
 
-	conn, err := grpc.DialContext(ctx, o.url, grpcOpts...)
+	connector := o.connector
+	conn, err := connector.DialContext(ctx, o.connectionKey(secured), o.credentials(secured))
 	if err != nil {
 		errs <- err
 		return responses, errs, cancel
@@ -326,9 +348,11 @@ func (o *Orderer) sendDeliver(envelope *fab.SignedEnvelope, secured bool) (chan
 			//If secured mode failed and allow insecure is enabled then retry in insecure mode
 			logger.Debug("Secured sendBroadcast failed, attempting insecured")
 
+			connector.ReleaseConn(conn)
 			cancel()
 			return o.sendDeliver(envelope, false)
 		}
+		connector.ReleaseConn(conn)
 		errs <- errors.Wrap(err, "NewAtomicBroadcastClient failed")
 		return responses, errs, cancel
 	}
@@ -338,13 +362,14 @@ func (o *Orderer) sendDeliver(envelope *fab.SignedEnvelope, secured bool) (chan
 		Payload:   envelope.Payload,
 		Signature: envelope.Signature,
 	}); err != nil {
+		connector.ReleaseConn(conn)
 		errs <- errors.Wrap(err, "failed to send block request to orderer")
 		return responses, errs, cancel
 	}
 
 	// Receive blocks from the GRPC stream and put them on the channel
 	go func() {
-		defer conn.Close()
+		defer connector.ReleaseConn(conn)
 		for {
 			response, err := broadcastStream.Recv()
 			if err != nil {
@@ -360,8 +385,7 @@ func (o *Orderer) sendDeliver(envelope *fab.SignedEnvelope, secured bool) (chan
 					return
 				}
 				if t.Status != common.Status_SUCCESS {
-					errs <- errors.Errorf("error status from ordering service %s",
-						t.Status)
+					errs <- status.New(status.OrdererServerStatus, int32(t.Status), "error status from ordering service", nil)
 					return
 				}
 
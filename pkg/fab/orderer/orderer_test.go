@@ -7,10 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package orderer
 
 import (
+	reqContext "context"
 	"crypto/x509"
 	"fmt"
 	"net"
-	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -196,7 +196,7 @@ func TestSendBroadcast(t *testing.T) {
 	_, addr := startMockServer(t, grpcServer)
 	ordererConfig := getGRPCOpts(addr, true, false)
 	orderer, _ := New(mocks.NewMockConfig(), WithURL(addr), FromOrdererConfig(ordererConfig), WithInsecure())
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 
 	if err != nil {
 		t.Fatalf("Test SendBroadcast was not supposed to fail")
@@ -204,7 +204,7 @@ func TestSendBroadcast(t *testing.T) {
 
 	orderer, _ = New(mocks.NewMockConfig(), WithURL(testOrdererURL+"Test"), FromOrdererConfig(ordererConfig))
 	orderer.dialTimeout = 15
-	_, err = orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err = orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 	if err == nil {
 		t.Fatalf("Expected error 'Orderer Client Status 2 context deadline exceeded'")
 	}
@@ -237,7 +237,8 @@ func TestSendDeliverServerBadResponse(t *testing.T) {
 	case block := <-blocks:
 		t.Fatalf("This usecase was not supposed to receive blocks : %#v", block)
 	case err := <-errors:
-		if err.Error() != "error status from ordering service BAD_REQUEST" {
+		statusError, ok := status.FromError(err)
+		if !ok || statusError.Group != status.OrdererServerStatus || statusError.Code != int32(common.Status_BAD_REQUEST) {
 			t.Fatalf("Ordering service error is not received as expected, %s", err)
 		}
 	case <-time.After(time.Second * 5):
@@ -245,6 +246,30 @@ func TestSendDeliverServerBadResponse(t *testing.T) {
 	}
 }
 
+func TestSendDeliverLongerThanDialTimeout(t *testing.T) {
+	broadcastServer := mocks.MockBroadcastServer{DeliverDelay: 500 * time.Millisecond}
+
+	grpcServer := grpc.NewServer()
+	defer grpcServer.Stop()
+	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
+	orderer, _ := New(mocks.NewMockConfig(), WithURL("grpc://"+addr), WithInsecure())
+	orderer.dialTimeout = 100 * time.Millisecond
+
+	blocks, errs, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	defer cancel()
+
+	select {
+	case block := <-blocks:
+		if string(block.Data.Data[0]) != "test" {
+			t.Fatalf("Expected test block but got %#v", block)
+		}
+	case err := <-errs:
+		t.Fatalf("Unexpected error from SendDeliver(): %s", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("Did not receive block from SendDeliver")
+	}
+}
+
 func TestSendDeliverServerSuccessResponse(t *testing.T) {
 
 	broadcastServer := mocks.MockBroadcastServer{
@@ -313,7 +338,7 @@ func TestSendBroadcastServerBadResponse(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := New(mocks.NewMockConfig(), WithURL("grpc://"+addr), WithInsecure())
 
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 
 	if err == nil {
 		t.Fatalf("Expected error")
@@ -335,7 +360,7 @@ func TestSendBroadcastError(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := New(mocks.NewMockConfig(), WithURL("grpc://"+addr), WithInsecure())
 
-	statusCode, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	statusCode, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 
 	if err == nil || statusCode != nil {
 		t.Fatalf("expected Send Broadcast to fail with error, but got %s", err)
@@ -356,10 +381,10 @@ func TestBroadcastBadDial(t *testing.T) {
 
 	orderer, err := New(config, WithURL("grpc://127.0.0.1:0"))
 	assert.Nil(t, err)
-	orderer.grpcDialOption = append(orderer.grpcDialOption, grpc.WithBlock())
+	orderer.dialOpts.Blocking = true
 	orderer.secured = true
 	orderer.allowInsecure = true
-	_, err = orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err = orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 	assert.NotNil(t, err)
 
 	if err == nil || !strings.Contains(err.Error(), "CONNECTION_FAILED") {
@@ -435,7 +460,7 @@ func getGRPCOpts(addr string, failFast bool, keepAliveOptions bool) *core.Ordere
 func TestForDeadlineExceeded(t *testing.T) {
 	orderer, _ := New(mocks.NewMockConfig(), WithURL(testOrdererURL+"Test"))
 	orderer.dialTimeout = 1 * time.Second
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 	if err == nil || !strings.HasPrefix(err.Error(), "NewAtomicBroadcastClient") {
 		t.Fatalf("Test SendBroadcast was supposed to fail with 'gRPC Transport Status Code: (4) DeadlineExceeded', instead it failed with [%s] error", err)
 	}
@@ -445,12 +470,8 @@ func TestSendDeliverDefaultOpts(t *testing.T) {
 	//keep alive option is not set and fail fast is false - invalid URL
 	orderer, _ := New(mocks.NewMockConfig(), WithURL("grpc://"+testOrdererURL+"Test"), WithInsecure())
 	orderer.dialTimeout = 5 * time.Second
-	fmt.Printf("GRPC opts%v \n", orderer.grpcDialOption)
-	for i, v := range orderer.grpcDialOption {
-		fmt.Printf("%v %v %v\n", i, &v, reflect.TypeOf(v))
-
-	}
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	fmt.Printf("GRPC opts%v \n", orderer.dialOpts)
+	_, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 	if err == nil {
 		t.Fatalf("Expected error 'Orderer Client Status 2 context deadline exceeded' %v", err)
 	}
@@ -483,7 +504,7 @@ func TestForGRPCErrorsWithKeepAliveOpts(t *testing.T) {
 	ordererConfig := getGRPCOpts("grpc://"+testOrdererURL+"Test", true, true)
 	orderer, _ := New(mocks.NewMockConfig(), WithURL(testOrdererURL+"Test"), FromOrdererConfig(ordererConfig))
 	orderer.dialTimeout = 5 * time.Second
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 	if err == nil {
 		t.Fatalf("Expected error 'Orderer Client Status 2 context deadline exceeded'")
 	}
@@ -496,7 +517,7 @@ func TestForGRPCErrorsWithKeepAliveOpts(t *testing.T) {
 	ordererConfig = getGRPCOpts(testOrdererURL+"Test", false, true)
 	orderer, _ = New(mocks.NewMockConfig(), WithURL(testOrdererURL+"Test"), FromOrdererConfig(ordererConfig))
 	orderer.dialTimeout = 5 * time.Second
-	_, err = orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err = orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
 	if err == nil {
 		t.Fatalf("Expected error 'Orderer Client Status 2 context deadline exceeded'")
 	}
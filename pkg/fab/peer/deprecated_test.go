@@ -9,6 +9,7 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	reqContext "context"
 	"encoding/pem"
 	"io/ioutil"
 	"reflect"
@@ -190,10 +191,10 @@ func TestDeprecatedProposalProcessorSendProposal(t *testing.T) {
 	tp := mockProcessProposalRequest()
 	tpr := fab.TransactionProposalResponse{Endorser: "example.com", Status: 99}
 
-	proc.EXPECT().ProcessTransactionProposal(tp).Return(&tpr, nil)
+	proc.EXPECT().ProcessTransactionProposal(gomock.Any(), tp).Return(&tpr, nil)
 
 	p := Peer{processor: proc, name: "", roles: nil}
-	tpr1, err := p.ProcessTransactionProposal(tp)
+	tpr1, err := p.ProcessTransactionProposal(reqContext.Background(), tp)
 
 	if err != nil || !reflect.DeepEqual(&tpr, tpr1) {
 		t.Fatalf("Peer didn't proxy proposal processing")
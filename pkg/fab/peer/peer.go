@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	reqContext "context"
 	"encoding/pem"
 	"fmt"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/urlutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
 	"github.com/spf13/cast"
 	"google.golang.org/grpc/keepalive"
@@ -42,6 +44,7 @@ type Peer struct {
 	kap                   keepalive.ClientParameters
 	failFast              bool
 	inSecure              bool
+	connector             *comm.CachingConnector
 }
 
 // Option describes a functional parameter for the New constructor
@@ -71,6 +74,7 @@ func New(config core.Config, opts ...Option) (*Peer, error) {
 			kap:                peer.kap,
 			failFast:           peer.failFast,
 			allowInsecure:      peer.inSecure,
+			connector:          peer.connector,
 		}
 		peer.processor, err = newPeerEndorser(&endorseRequest)
 
@@ -118,6 +122,16 @@ func WithInsecure() Option {
 	}
 }
 
+// WithConnector is a functional option for the peer.New constructor that configures the cache of
+// the peer's GRPC connections. Without a connector each request uses a new connection.
+func WithConnector(connector *comm.CachingConnector) Option {
+	return func(p *Peer) error {
+		p.connector = connector
+
+		return nil
+	}
+}
+
 // FromPeerConfig is a functional option for the peer.New constructor that configures a new peer
 // from a apiconfig.NetworkPeer struct
 func FromPeerConfig(peerCfg *core.NetworkPeer) Option {
@@ -250,8 +264,8 @@ func (p *Peer) URL() string {
 }
 
 // ProcessTransactionProposal sends the created proposal to peer for endorsement.
-func (p *Peer) ProcessTransactionProposal(proposal fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
-	return p.processor.ProcessTransactionProposal(proposal)
+func (p *Peer) ProcessTransactionProposal(ctx reqContext.Context, proposal fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	return p.processor.ProcessTransactionProposal(ctx, proposal)
 }
 
 func (p *Peer) String() string {
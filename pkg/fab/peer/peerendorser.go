@@ -8,6 +8,7 @@ package peer
 
 import (
 	grpccontext "context"
+	"crypto/tls"
 	"crypto/x509"
 	"time"
 
@@ -22,18 +23,22 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/urlutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	fabcomm "github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
 // peerEndorser enables access to a GRPC-based endorser for running transaction proposal simulations
 type peerEndorser struct {
-	grpcDialOption       []grpc.DialOption
+	connector            *fabcomm.CachingConnector
+	dialOpts             fabcomm.DialOpts
 	target               string
 	dialTimeout          time.Duration
-	failFast             bool
 	transportCredentials credentials.TransportCredentials
+	clientCerts          []tls.Certificate
 	secured              bool
 	allowInsecure        bool
+	certificate          *x509.Certificate
+	serverHostOverride   string
 }
 
 type peerEndorserRequest struct {
@@ -45,6 +50,7 @@ type peerEndorserRequest struct {
 	kap                keepalive.ClientParameters
 	failFast           bool
 	allowInsecure      bool
+	connector          *fabcomm.CachingConnector
 }
 
 func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
@@ -52,36 +58,35 @@ func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
 		return nil, errors.New("target is required")
 	}
 
-	// Construct dialer options for the connection
-	var opts []grpc.DialOption
-	if endorseReq.kap.Time > 0 || endorseReq.kap.Timeout > 0 {
-		opts = append(opts, grpc.WithKeepaliveParams(endorseReq.kap))
+	// Dial options of the connection
+	dialOpts := fabcomm.DialOpts{
+		FailFast:  endorseReq.failFast,
+		Blocking:  endorseReq.dialBlocking, // TODO: configurable?
+		KeepAlive: endorseReq.kap,
 	}
-	opts = append(opts, grpc.WithDefaultCallOptions(grpc.FailFast(endorseReq.failFast)))
 
 	timeout := endorseReq.config.TimeoutOrDefault(core.Endorser)
 
-	if endorseReq.dialBlocking { // TODO: configurable?
-		opts = append(opts, grpc.WithBlock())
-	}
-
 	tlsConfig, err := comm.TLSConfig(endorseReq.certificate, endorseReq.serverHostOverride, endorseReq.config)
 	if err != nil {
 		return nil, err
 	}
 
-	pc := &peerEndorser{grpcDialOption: opts, target: urlutil.ToAddress(endorseReq.target), dialTimeout: timeout,
-		transportCredentials: credentials.NewTLS(tlsConfig), secured: urlutil.AttemptSecured(endorseReq.target),
-		allowInsecure: endorseReq.allowInsecure}
+	pc := &peerEndorser{connector: endorseReq.connector, dialOpts: dialOpts,
+		target: urlutil.ToAddress(endorseReq.target), dialTimeout: timeout,
+		transportCredentials: credentials.NewTLS(tlsConfig), clientCerts: tlsConfig.Certificates, secured: urlutil.AttemptSecured(endorseReq.target),
+		allowInsecure: endorseReq.allowInsecure, certificate: endorseReq.certificate,
+		serverHostOverride: endorseReq.serverHostOverride}
 
 	return pc, nil
 }
 
 // ProcessTransactionProposal sends the transaction proposal to a peer and returns the response.
-func (p *peerEndorser) ProcessTransactionProposal(request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+// The request is abandoned if the given context is cancelled or its deadline expires.
+func (p *peerEndorser) ProcessTransactionProposal(ctx grpccontext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
 	logger.Debugf("Processing proposal using endorser: %s", p.target)
 
-	proposalResponse, err := p.sendProposal(request, p.secured)
+	proposalResponse, err := p.sendProposal(ctx, request, p.secured)
 	if err != nil {
 		tpr := fab.TransactionProposalResponse{Endorser: p.target}
 		return &tpr, errors.Wrapf(err, "Transaction processing for endorser [%s]", p.target)
@@ -95,46 +100,50 @@ func (p *peerEndorser) ProcessTransactionProposal(request fab.ProcessProposalReq
 	return &tpr, nil
 }
 
-func (p *peerEndorser) conn(secured bool) (*grpc.ClientConn, error) {
+func (p *peerEndorser) conn(reqCtx grpccontext.Context, secured bool) (*grpc.ClientConn, error) {
 	// Establish connection to Ordering Service
-	var grpcOpts []grpc.DialOption
+	creds := grpc.WithInsecure()
 	if secured {
-		grpcOpts = append(p.grpcDialOption, grpc.WithTransportCredentials(p.transportCredentials))
-	} else {
-		grpcOpts = append(p.grpcDialOption, grpc.WithInsecure())
+		creds = grpc.WithTransportCredentials(p.transportCredentials)
 	}
 
-	ctx := grpccontext.Background()
-	ctx, cancel := grpccontext.WithTimeout(ctx, p.dialTimeout)
+	ctx, cancel := grpccontext.WithTimeout(reqCtx, p.dialTimeout)
 	defer cancel()
 
-	return grpc.DialContext(ctx, p.target, grpcOpts...)
+	key := fabcomm.NewConnectionKey(p.target, p.certificate, p.clientCerts, p.serverHostOverride, secured, p.dialOpts)
+	return p.connector.DialContext(ctx, key, creds)
 }
 
 func (p *peerEndorser) releaseConn(conn *grpc.ClientConn) {
-	conn.Close()
+	p.connector.ReleaseConn(conn)
 }
 
-func (p *peerEndorser) sendProposal(proposal fab.ProcessProposalRequest, secured bool) (*pb.ProposalResponse, error) {
-	conn, err := p.conn(secured)
+func (p *peerEndorser) sendProposal(ctx grpccontext.Context, proposal fab.ProcessProposalRequest, secured bool) (*pb.ProposalResponse, error) {
+	conn, err := p.conn(ctx, secured)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.NewFromContextError(status.EndorserClientStatus, ctxErr, []interface{}{p.target})
+		}
 		if secured && p.allowInsecure {
 			//If secured mode failed and allow insecure is enabled then retry in insecure mode
 			logger.Debug("Secured NewEndorserClient failed, attempting insecured")
-			return p.sendProposal(proposal, false)
+			return p.sendProposal(ctx, proposal, false)
 		}
 		return nil, status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), []interface{}{p.target})
 	}
 	defer p.releaseConn(conn)
 
 	endorserClient := pb.NewEndorserClient(conn)
-	resp, err := endorserClient.ProcessProposal(grpccontext.Background(), proposal.SignedProposal)
+	resp, err := endorserClient.ProcessProposal(ctx, proposal.SignedProposal)
 	if err != nil {
 		logger.Error("NewEndorserClient failed, cause : ", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.NewFromContextError(status.EndorserClientStatus, ctxErr, []interface{}{p.target})
+		}
 		if secured && p.allowInsecure {
 			//If secured mode failed and allow insecure is enabled then retry in insecure mode
 			logger.Debug("Secured NewEndorserClient failed, attempting insecured")
-			return p.sendProposal(proposal, false)
+			return p.sendProposal(ctx, proposal, false)
 		}
 
 		rpcStatus, ok := grpcstatus.FromError(err)
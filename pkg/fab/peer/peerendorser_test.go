@@ -7,10 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	reqContext "context"
 	"crypto/x509"
 	"fmt"
 	"net"
-	"reflect"
 	"testing"
 	"time"
 
@@ -55,13 +55,8 @@ func TestNewPeerEndorserTLS(t *testing.T) {
 		t.Fatalf("Peer conn should be constructed")
 	}
 
-	optInsecure := reflect.ValueOf(grpc.WithInsecure())
-
-	for _, opt := range conn.grpcDialOption {
-		optr := reflect.ValueOf(opt)
-		if optr.Pointer() == optInsecure.Pointer() {
-			t.Fatalf("TLS enabled - insecure not allowed")
-		}
+	if !conn.secured {
+		t.Fatalf("TLS enabled - insecure not allowed")
 	}
 }
 
@@ -87,13 +82,8 @@ func TestNewPeerEndorserMutualTLS(t *testing.T) {
 		t.Fatalf("Peer conn should be constructed: %v", err)
 	}
 
-	optInsecure := reflect.ValueOf(grpc.WithInsecure())
-
-	for _, opt := range conn.grpcDialOption {
-		optr := reflect.ValueOf(opt)
-		if optr.Pointer() == optInsecure.Pointer() {
-			t.Fatalf("TLS enabled - insecure not allowed")
-		}
+	if !conn.secured {
+		t.Fatalf("TLS enabled - insecure not allowed")
 	}
 }
 
@@ -190,17 +180,7 @@ func TestNewPeerEndorserBlocking(t *testing.T) {
 		t.Fatalf("Peer conn should be constructed")
 	}
 
-	optBlocking := reflect.ValueOf(grpc.WithBlock())
-	optBlockingFound := false
-
-	for _, opt := range conn.grpcDialOption {
-		optr := reflect.ValueOf(opt)
-		if optr.Pointer() == optBlocking.Pointer() {
-			optBlockingFound = true
-		}
-	}
-
-	if !optBlockingFound {
+	if !conn.dialOpts.Blocking {
 		t.Fatalf("Expected blocking to be found")
 	}
 }
@@ -220,13 +200,8 @@ func TestNewPeerEndorserNonBlocking(t *testing.T) {
 		t.Fatalf("Peer conn should be constructed")
 	}
 
-	optBlocking := reflect.ValueOf(grpc.WithBlock())
-
-	for _, opt := range conn.grpcDialOption {
-		optr := reflect.ValueOf(opt)
-		if optr.Pointer() == optBlocking.Pointer() {
-			t.Fatalf("Blocking opt found when not expected")
-		}
+	if conn.dialOpts.Blocking {
+		t.Fatalf("Blocking opt found when not expected")
 	}
 }
 
@@ -290,7 +265,7 @@ func testProcessProposal(t *testing.T, url string) (*fab.TransactionProposalResp
 		t.Fatalf("Peer conn construction error (%v)", err)
 	}
 
-	return conn.ProcessTransactionProposal(mockProcessProposalRequest())
+	return conn.ProcessTransactionProposal(reqContext.Background(), mockProcessProposalRequest())
 }
 
 func getPeerEndorserRequest(url string, cert *x509.Certificate, serverHostOverride string,
@@ -0,0 +1,34 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package policydsl converts between signature policy envelopes and the policy syntax
+// used by Fabric, e.g. OR('Org1MSP.member', AND('Org2MSP.peer', 'Org3MSP.admin')).
+//
+// A policy is a gate of principals and nested gates:
+//
+//	AND(P, P, ...)      all of the policies P are satisfied
+//	OR(P, P, ...)       one of the policies P is satisfied
+//	OutOf(N, P, ...)    N of the policies P are satisfied
+//
+// A principal is a quoted string 'MSPID.ROLE', where ROLE is member, admin, client, peer
+// or orderer.
+package policydsl
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// FromString parses the policy into a signature policy envelope with the parser of Fabric
+func FromString(policy string) (*common.SignaturePolicyEnvelope, error) {
+	envelope, err := cauthdsl.FromString(policy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing policy [%s] failed", policy)
+	}
+	return envelope, nil
+}
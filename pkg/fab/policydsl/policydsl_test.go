@@ -0,0 +1,28 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policydsl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromString(t *testing.T) {
+	envelope, err := FromString("OR('Org1MSP.member', AND('Org2MSP.peer', 'Org3MSP.admin'))")
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %s", err)
+	}
+	assert.Len(t, envelope.Identities, 3)
+	assert.Equal(t, int32(1), envelope.Rule.GetNOutOf().N)
+
+	policies := []string{"", "OR('Org1MSP.member'", "XOR('Org1MSP.member')", "OR('Org1MSP.owner')"}
+	for _, policy := range policies {
+		_, err := FromString(policy)
+		assert.NotNil(t, err, "expected error for policy %s", policy)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policydsl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+// String returns the policy syntax of the signature policy envelope. A gate which requires all
+// of its policies is printed as AND, a gate which requires one of them as OR, and any other gate
+// as OutOf. Policies are printed in the order of the rules of the envelope. Principals other than
+// MSP roles cannot be expressed in the syntax.
+func String(envelope *common.SignaturePolicyEnvelope) (string, error) {
+	if envelope == nil || envelope.Rule == nil {
+		return "", errors.New("signature policy envelope must provide a rule")
+	}
+
+	principals := make([]string, len(envelope.Identities))
+	for i, identity := range envelope.Identities {
+		principal, err := principalString(identity)
+		if err != nil {
+			return "", errors.WithMessage(err, fmt.Sprintf("invalid identity %d", i))
+		}
+		principals[i] = principal
+	}
+
+	return ruleString(envelope.Rule, principals)
+}
+
+func ruleString(rule *common.SignaturePolicy, principals []string) (string, error) {
+	switch r := rule.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if r.SignedBy < 0 || int(r.SignedBy) >= len(principals) {
+			return "", errors.Errorf("rule refers to identity %d of %d identities", r.SignedBy, len(principals))
+		}
+		return principals[r.SignedBy], nil
+
+	case *common.SignaturePolicy_NOutOf_:
+		rules := r.NOutOf.Rules
+		n := int(r.NOutOf.N)
+		if len(rules) == 0 {
+			return "", errors.New("gate has no policies")
+		}
+
+		args := make([]string, len(rules))
+		for i, subRule := range rules {
+			arg, err := ruleString(subRule, principals)
+			if err != nil {
+				return "", err
+			}
+			args[i] = arg
+		}
+
+		switch {
+		case n == 1:
+			return fmt.Sprintf("OR(%s)", strings.Join(args, ", ")), nil
+		case n == len(rules):
+			return fmt.Sprintf("AND(%s)", strings.Join(args, ", ")), nil
+		}
+		return fmt.Sprintf("OutOf(%d, %s)", n, strings.Join(args, ", ")), nil
+	}
+	return "", errors.Errorf("unsupported rule type %T", rule.Type)
+}
+
+func principalString(principal *msp.MSPPrincipal) (string, error) {
+	if principal.PrincipalClassification != msp.MSPPrincipal_ROLE {
+		return "", errors.Errorf("unsupported principal classification %s", principal.PrincipalClassification)
+	}
+
+	role := &msp.MSPRole{}
+	if err := proto.Unmarshal(principal.Principal, role); err != nil {
+		return "", errors.Wrap(err, "unmarshal of MSP role failed")
+	}
+	return fmt.Sprintf("'%s.%s'", role.MspIdentifier, strings.ToLower(role.Role.String())), nil
+}
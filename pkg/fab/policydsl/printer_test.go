@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policydsl
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+func TestString(t *testing.T) {
+	policies := map[string]string{
+		"or('Org1MSP.member','Org2MSP.member')":                           "OR('Org1MSP.member', 'Org2MSP.member')",
+		"AND('Org1MSP.member', OR('Org2MSP.peer', 'Org3MSP.admin'))":      "AND('Org1MSP.member', OR('Org2MSP.peer', 'Org3MSP.admin'))",
+		"OutOf(2, 'Org1MSP.member', 'Org2MSP.client', 'Org3MSP.orderer')": "OutOf(2, 'Org1MSP.member', 'Org2MSP.client', 'Org3MSP.orderer')",
+		"OutOf(2, 'Org1MSP.member', 'Org2MSP.member')":                    "AND('Org1MSP.member', 'Org2MSP.member')",
+	}
+
+	for policy, expected := range policies {
+		envelope, err := FromString(policy)
+		if err != nil {
+			t.Fatalf("Failed to parse policy %s: %s", policy, err)
+		}
+		printed, err := String(envelope)
+		if err != nil {
+			t.Fatalf("Failed to print policy %s: %s", policy, err)
+		}
+		assert.Equal(t, expected, printed)
+
+		// The printed policy parses to the same envelope
+		reparsed, err := FromString(printed)
+		if err != nil {
+			t.Fatalf("Failed to parse printed policy %s: %s", printed, err)
+		}
+		assert.True(t, proto.Equal(envelope, reparsed), "unexpected envelope for printed policy %s", printed)
+	}
+
+	// Envelopes built with cauthdsl share identities between rules
+	printed, err := String(cauthdsl.SignedByAnyMember([]string{"Org1MSP", "Org2MSP"}))
+	if err != nil {
+		t.Fatalf("Failed to print policy: %s", err)
+	}
+	assert.Equal(t, "OR('Org1MSP.member', 'Org2MSP.member')", printed)
+
+	printed, err = String(cauthdsl.SignedByMspAdmin("Org1MSP"))
+	if err != nil {
+		t.Fatalf("Failed to print policy: %s", err)
+	}
+	assert.Equal(t, "OR('Org1MSP.admin')", printed)
+
+	// A rule of a single principal is printed as the principal
+	printed, err = String(&common.SignaturePolicyEnvelope{Rule: cauthdsl.SignedBy(0), Identities: cauthdsl.SignedByMspMember("Org1MSP").Identities})
+	if err != nil {
+		t.Fatalf("Failed to print policy: %s", err)
+	}
+	assert.Equal(t, "'Org1MSP.member'", printed)
+}
+
+func TestStringErrors(t *testing.T) {
+	_, err := String(nil)
+	assert.NotNil(t, err, "expected error for missing envelope")
+
+	_, err = String(&common.SignaturePolicyEnvelope{Rule: cauthdsl.SignedBy(1), Identities: cauthdsl.SignedByMspMember("Org1MSP").Identities})
+	assert.NotNil(t, err, "expected error for invalid identity index")
+
+	_, err = String(&common.SignaturePolicyEnvelope{Rule: cauthdsl.NOutOf(1, nil)})
+	assert.NotNil(t, err, "expected error for gate without policies")
+
+	identity := &msp.MSPPrincipal{PrincipalClassification: msp.MSPPrincipal_IDENTITY, Principal: []byte("cert")}
+	_, err = String(&common.SignaturePolicyEnvelope{Rule: cauthdsl.SignedBy(0), Identities: []*msp.MSPPrincipal{identity}})
+	assert.NotNil(t, err, "expected error for identity principal")
+}
@@ -70,4 +70,7 @@ type JoinChannelRequest struct {
 type CCPackage struct {
 	Type pb.ChaincodeSpec_Type
 	Code []byte
+	// optional - signed CDS package, which is installed instead of a CDS created from
+	// the package type and bytes (see cdspackager)
+	Signed *common.Envelope
 }
@@ -35,11 +35,22 @@ type ChaincodeInstallRequest struct {
 type ChaincodePackage struct {
 	Type pb.ChaincodeSpec_Type
 	Code []byte
+	// Signed CDS package which is installed as is (optional)
+	Signed *common.Envelope
 }
 
 // CreateChaincodeInstallProposal creates an install chaincode proposal.
 func CreateChaincodeInstallProposal(txh *txn.TransactionHeader, request ChaincodeInstallRequest) (*fab.TransactionProposal, error) {
 
+	// A signed package is installed as is
+	if request.Package.Signed != nil {
+		envelopeBytes, err := signedPackageBytes(request)
+		if err != nil {
+			return nil, err
+		}
+		return createInstallProposal(txh, [][]byte{envelopeBytes})
+	}
+
 	// Generate arguments for install
 	args := [][]byte{}
 	timestamp := time.Now()
@@ -57,6 +68,10 @@ func CreateChaincodeInstallProposal(txh *txn.TransactionHeader, request Chaincod
 	}
 	args = append(args, ccdsBytes)
 
+	return createInstallProposal(txh, args)
+}
+
+func createInstallProposal(txh *txn.TransactionHeader, args [][]byte) (*fab.TransactionProposal, error) {
 	cir := fab.ChaincodeInvokeRequest{
 		ChaincodeID: "lscc",
 		Fcn:         "install",
@@ -66,6 +81,35 @@ func CreateChaincodeInstallProposal(txh *txn.TransactionHeader, request Chaincod
 	return txn.CreateChaincodeInvokeProposal(txh, cir)
 }
 
+// signedPackageBytes returns the signed CDS package of the request after checking that the
+// package contains the chaincode of the request
+func signedPackageBytes(request ChaincodeInstallRequest) ([]byte, error) {
+	payload, err := protos_utils.GetPayload(request.Package.Signed)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal of signed chaincode package payload failed")
+	}
+	signedCDS := &pb.SignedChaincodeDeploymentSpec{}
+	if err := proto.Unmarshal(payload.Data, signedCDS); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of signed chaincode deployment spec failed")
+	}
+	ccds := &pb.ChaincodeDeploymentSpec{}
+	if err := proto.Unmarshal(signedCDS.ChaincodeDeploymentSpec, ccds); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of chaincode deployment spec failed")
+	}
+
+	ccID := ccds.GetChaincodeSpec().GetChaincodeId()
+	if ccID.GetName() != request.Name || ccID.GetVersion() != request.Version {
+		return nil, errors.Errorf("signed chaincode package contains chaincode %s:%s but request is for chaincode %s:%s",
+			ccID.GetName(), ccID.GetVersion(), request.Name, request.Version)
+	}
+
+	envelopeBytes, err := proto.Marshal(request.Package.Signed)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of signed chaincode package failed")
+	}
+	return envelopeBytes, nil
+}
+
 // CreateConfigSignature creates a ConfigSignature for the current context.
 func CreateConfigSignature(ctx context.Context, config []byte) (*common.ConfigSignature, error) {
 
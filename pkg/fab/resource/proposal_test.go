@@ -7,13 +7,20 @@ SPDX-License-Identifier: Apache-2.0
 package resource
 
 import (
+	reqContext "context"
 	"io/ioutil"
 	"path"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/cdspackager"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 	"github.com/hyperledger/fabric-sdk-go/test/metadata"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/stretchr/testify/assert"
@@ -36,10 +43,45 @@ func TestCreateChaincodeInstallProposal(t *testing.T) {
 	prop, err := CreateChaincodeInstallProposal(txid, request)
 	assert.Nil(t, err, "CreateChaincodeInstallProposal failed")
 
-	_, err = txn.SendProposal(c.clientContext, prop, []fab.ProposalProcessor{&peer})
+	_, err = txn.SendProposal(reqContext.Background(), c.clientContext, prop, []fab.ProposalProcessor{&peer})
 	assert.Nil(t, err, "sending mock proposal failed")
 }
 
+func TestCreateChaincodeInstallProposalSignedPackage(t *testing.T) {
+	c := setupTestClient()
+
+	spec, err := cdspackager.NewDeploymentSpec("examplecc", "github.com/examplecc", "1", &api.CCPackage{Code: []byte("code")})
+	assert.Nil(t, err, "NewDeploymentSpec failed")
+	pkg, err := cdspackager.NewSignedPackage(spec, cauthdsl.SignedByMspMember("Org1MSP"), nil)
+	assert.Nil(t, err, "NewSignedPackage failed")
+
+	request := ChaincodeInstallRequest{
+		Name:    "examplecc",
+		Path:    "github.com/examplecc",
+		Version: "1",
+		Package: &ChaincodePackage{Signed: pkg.Envelope},
+	}
+
+	txid, err := txn.NewHeader(c.clientContext, fab.SystemChannel)
+	assert.Nil(t, err, "create transaction ID failed")
+
+	prop, err := CreateChaincodeInstallProposal(txid, request)
+	assert.Nil(t, err, "CreateChaincodeInstallProposal failed")
+
+	// The signed package is the argument of the install
+	proposalPayload := &pb.ChaincodeProposalPayload{}
+	assert.Nil(t, proto.Unmarshal(prop.Proposal.Payload, proposalPayload))
+	invocationSpec := &pb.ChaincodeInvocationSpec{}
+	assert.Nil(t, proto.Unmarshal(proposalPayload.Input, invocationSpec))
+	envelopeBytes, err := proto.Marshal(pkg.Envelope)
+	assert.Nil(t, err, "marshal of envelope failed")
+	assert.Equal(t, [][]byte{[]byte("install"), envelopeBytes}, invocationSpec.ChaincodeSpec.Input.Args)
+
+	request.Version = "2"
+	_, err = CreateChaincodeInstallProposal(txid, request)
+	assert.NotNil(t, err, "expected error for package of different chaincode version")
+}
+
 func TestExtractChannelConfig(t *testing.T) {
 	configTx, err := ioutil.ReadFile(path.Join("../../../", metadata.ChannelConfigPath, "mychannel.tx"))
 	if err != nil {
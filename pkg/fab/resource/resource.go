@@ -8,6 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package resource
 
 import (
+	reqContext "context"
 	"net/http"
 
 	"github.com/golang/protobuf/proto"
@@ -107,7 +108,7 @@ func (c *Resource) createChannelFromEnvelope(request api.CreateChannelRequest) (
 	}
 
 	// Send request
-	_, err = request.Orderer.SendBroadcast(env)
+	_, err = request.Orderer.SendBroadcast(reqContext.Background(), env)
 	if err != nil {
 		return fab.EmptyTransactionID, errors.WithMessage(err, "failed broadcast to orderer")
 	}
@@ -120,38 +121,16 @@ func (c *Resource) GenesisBlockFromOrderer(channelName string, orderer fab.Order
 
 	orderers := []fab.Orderer{orderer}
 
-	txh, err := txn.NewHeader(c.clientContext, channelName)
-	if err != nil {
-		return nil, errors.WithMessage(err, "failed to calculate transaction id")
-	}
-
 	// now build the seek info , will be used once the channel is created
 	// to get the genesis block back
-	seekStart := newSpecificSeekPosition(0)
-	seekStop := newSpecificSeekPosition(0)
 	seekInfo := &ab.SeekInfo{
-		Start:    seekStart,
-		Stop:     seekStop,
+		Start:    newSpecificSeekPosition(0),
+		Stop:     newSpecificSeekPosition(0),
 		Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
 	}
-	seekInfoBytes, err := proto.Marshal(seekInfo)
-	if err != nil {
-		return nil, errors.Wrap(err, "marshaling of seek info header failed")
-	}
-
-	tlsCertHash := ccomm.TLSCertHash(c.clientContext.Config())
-	channelHeaderOpts := txn.ChannelHeaderOpts{
-		TxnHeader:   txh,
-		TLSCertHash: tlsCertHash,
-	}
-	seekInfoHeader, err := txn.CreateChannelHeader(common.HeaderType_DELIVER_SEEK_INFO, channelHeaderOpts)
-	if err != nil {
-		return nil, errors.Wrap(err, "CreateChannelHeader failed")
-	}
-
-	payload, err := txn.CreatePayload(txh, seekInfoHeader, seekInfoBytes)
+	payload, err := txn.CreateSeekPayload(c.clientContext, channelName, seekInfo)
 	if err != nil {
-		return nil, errors.Wrap(err, "CreatePayload failed")
+		return nil, errors.WithMessage(err, "CreateSeekPayload failed")
 	}
 
 	block, err := txn.SendPayload(c.clientContext, payload, orderers)
@@ -228,7 +207,7 @@ func (c *Resource) createOrUpdateChannel(txh *txn.TransactionHeader, request api
 		return errors.WithMessage(err, "CreatePayload failed")
 	}
 
-	_, err = txn.BroadcastPayload(c.clientContext, payload, []fab.Orderer{request.Orderer})
+	_, err = txn.BroadcastPayload(reqContext.Background(), c.clientContext, payload, []fab.Orderer{request.Orderer})
 	if err != nil {
 		return errors.WithMessage(err, "SendEnvelope failed")
 	}
@@ -353,8 +332,9 @@ func (c *Resource) InstallChaincode(req api.InstallChaincodeRequest) ([]*fab.Tra
 		Path:    req.Path,
 		Version: req.Version,
 		Package: &ChaincodePackage{
-			Type: req.Package.Type,
-			Code: req.Package.Code,
+			Type:   req.Package.Type,
+			Code:   req.Package.Code,
+			Signed: req.Package.Signed,
 		},
 	}
 
@@ -368,7 +348,7 @@ func (c *Resource) InstallChaincode(req api.InstallChaincodeRequest) ([]*fab.Tra
 		return nil, "", errors.WithMessage(err, "creation of install chaincode proposal failed")
 	}
 
-	transactionProposalResponse, err := txn.SendProposal(c.clientContext, prop, req.Targets)
+	transactionProposalResponse, err := txn.SendProposal(reqContext.Background(), c.clientContext, prop, req.Targets)
 
 	return transactionProposalResponse, prop.TxnID, err
 }
@@ -403,7 +383,7 @@ func (c *Resource) queryChaincodeWithTarget(request fab.ChaincodeInvokeRequest,
 		return nil, errors.WithMessage(err, "NewProposal failed")
 	}
 
-	tpr, err := txn.SendProposal(c.clientContext, tp, targets)
+	tpr, err := txn.SendProposal(reqContext.Background(), c.clientContext, tp, targets)
 	if err != nil {
 		return nil, errors.WithMessage(err, "SendProposal failed")
 	}
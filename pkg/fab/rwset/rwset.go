@@ -0,0 +1,267 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package rwset decodes the chaincode actions, including the read-write sets,
+// contained in proposal responses and in the transactions of a block.
+package rwset
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	lrwset "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// ChaincodeAction is the decoded result of a chaincode invocation
+type ChaincodeAction struct {
+	// ChaincodeID contains the name and version of the invoked chaincode
+	ChaincodeID *pb.ChaincodeID
+	Response    *pb.Response
+	Event       *pb.ChaincodeEvent
+	RWSet       *TxRWSet
+}
+
+// TxRWSet contains the read-write sets of a chaincode invocation, one per namespace
+type TxRWSet struct {
+	NsRWSets []*NsRWSet
+}
+
+// NsRWSet contains the reads and writes of a single namespace (chaincode)
+type NsRWSet struct {
+	Namespace        string
+	Reads            []*kvrwset.KVRead
+	Writes           []*kvrwset.KVWrite
+	Deletes          []*kvrwset.KVWrite
+	RangeQueriesInfo []*kvrwset.RangeQueryInfo
+	CollHashedRWSets []*CollHashedRWSet
+}
+
+// CollHashedRWSet contains the hashed reads and writes of a private data collection
+type CollHashedRWSet struct {
+	CollectionName string
+	HashedReads    []*kvrwset.KVReadHash
+	HashedWrites   []*kvrwset.KVWriteHash
+	PvtRWSetHash   []byte
+}
+
+// Transaction contains the decoded chaincode actions of an endorser transaction in a block
+type Transaction struct {
+	TxID             string
+	ChannelID        string
+	TxValidationCode pb.TxValidationCode
+	// Validated is false if the block has no transaction filter, e.g. a block delivered by an
+	// orderer, in which case the validation code of the transaction is not known
+	Validated bool
+	Actions   []*ChaincodeAction
+}
+
+// Namespace returns the read-write set of the given namespace or nil if the
+// chaincode action did not touch the namespace
+func (a *ChaincodeAction) Namespace(namespace string) *NsRWSet {
+	if a.RWSet == nil {
+		return nil
+	}
+	for _, nsRWSet := range a.RWSet.NsRWSets {
+		if nsRWSet.Namespace == namespace {
+			return nsRWSet
+		}
+	}
+	return nil
+}
+
+// FromProposalResponse decodes the chaincode action of an endorsed proposal response
+func FromProposalResponse(response *pb.ProposalResponse) (*ChaincodeAction, error) {
+	if response == nil {
+		return nil, errors.New("proposal response is nil")
+	}
+	return FromProposalResponsePayload(response.Payload)
+}
+
+// FromProposalResponsePayload decodes the chaincode action contained in a marshalled ProposalResponsePayload
+func FromProposalResponsePayload(payload []byte) (*ChaincodeAction, error) {
+	prp, err := utils.GetProposalResponsePayload(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling proposal response payload")
+	}
+
+	ccAction, err := utils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode action")
+	}
+
+	event, err := utils.GetChaincodeEvents(ccAction.Events)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode event")
+	}
+	if event != nil && event.ChaincodeId == "" && event.TxId == "" && event.EventName == "" {
+		// The peer always sets the events field, even if the chaincode did not set an event
+		event = nil
+	}
+
+	txRWSet, err := decodeTxRWSet(ccAction.Results)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChaincodeAction{
+		ChaincodeID: ccAction.ChaincodeId,
+		Response:    ccAction.Response,
+		Event:       event,
+		RWSet:       txRWSet,
+	}, nil
+}
+
+// FromEnvelope decodes the chaincode actions of an endorser transaction envelope
+func FromEnvelope(envelope *cb.Envelope) (*Transaction, error) {
+	payload, err := utils.ExtractPayload(envelope)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting payload from envelope")
+	}
+	if payload.Header == nil {
+		return nil, errors.New("payload header is nil")
+	}
+
+	channelHeader, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting channel header from payload")
+	}
+	if cb.HeaderType(channelHeader.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return nil, errors.Errorf("transaction type [%s] is not an endorser transaction", cb.HeaderType(channelHeader.Type))
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling transaction")
+	}
+
+	transaction := &Transaction{TxID: channelHeader.TxId, ChannelID: channelHeader.ChannelId}
+	for _, action := range tx.Actions {
+		ccActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling chaincode action payload")
+		}
+		if ccActionPayload.Action == nil {
+			return nil, errors.New("chaincode endorsed action is nil")
+		}
+
+		ccAction, err := FromProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+		if err != nil {
+			return nil, err
+		}
+		transaction.Actions = append(transaction.Actions, ccAction)
+	}
+
+	return transaction, nil
+}
+
+// FromBlock decodes the endorser transactions of a block. Other transaction types,
+// e.g. config transactions, are skipped.
+func FromBlock(block *cb.Block) ([]*Transaction, error) {
+	if block == nil || block.Data == nil {
+		return nil, errors.New("block data is nil")
+	}
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	var transactions []*Transaction
+	for i, data := range block.Data.Data {
+		envelope, err := utils.GetEnvelopeFromBlock(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error extracting envelope %d from block", i)
+		}
+
+		payload, err := utils.ExtractPayload(envelope)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error extracting payload of transaction %d", i)
+		}
+		if payload.Header == nil {
+			return nil, errors.Errorf("payload header of transaction %d is nil", i)
+		}
+		channelHeader, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error extracting channel header of transaction %d", i)
+		}
+		if cb.HeaderType(channelHeader.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+
+		tx, err := FromEnvelope(envelope)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error decoding transaction "+channelHeader.TxId)
+		}
+		if i < len(txFilter) {
+			tx.TxValidationCode = txFilter.Flag(i)
+			tx.Validated = true
+		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, nil
+}
+
+func decodeTxRWSet(results []byte) (*TxRWSet, error) {
+	if len(results) == 0 {
+		return &TxRWSet{}, nil
+	}
+
+	txRWSet := &lrwset.TxReadWriteSet{}
+	if err := proto.Unmarshal(results, txRWSet); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling read-write set")
+	}
+
+	decoded := &TxRWSet{}
+	for _, nsRWSet := range txRWSet.NsRwset {
+		ns, err := decodeNsRWSet(nsRWSet)
+		if err != nil {
+			return nil, err
+		}
+		decoded.NsRWSets = append(decoded.NsRWSets, ns)
+	}
+	return decoded, nil
+}
+
+func decodeNsRWSet(nsRWSet *lrwset.NsReadWriteSet) (*NsRWSet, error) {
+	kvRWSet := &kvrwset.KVRWSet{}
+	if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshalling read-write set of namespace [%s]", nsRWSet.Namespace)
+	}
+
+	ns := &NsRWSet{
+		Namespace:        nsRWSet.Namespace,
+		Reads:            kvRWSet.Reads,
+		RangeQueriesInfo: kvRWSet.RangeQueriesInfo,
+	}
+	for _, write := range kvRWSet.Writes {
+		if write.IsDelete {
+			ns.Deletes = append(ns.Deletes, write)
+		} else {
+			ns.Writes = append(ns.Writes, write)
+		}
+	}
+
+	for _, collRWSet := range nsRWSet.CollectionHashedRwset {
+		hashedRWSet := &kvrwset.HashedRWSet{}
+		if err := proto.Unmarshal(collRWSet.HashedRwset, hashedRWSet); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshalling hashed read-write set of collection [%s:%s]", nsRWSet.Namespace, collRWSet.CollectionName)
+		}
+		ns.CollHashedRWSets = append(ns.CollHashedRWSets, &CollHashedRWSet{
+			CollectionName: collRWSet.CollectionName,
+			HashedReads:    hashedRWSet.HashedReads,
+			HashedWrites:   hashedRWSet.HashedWrites,
+			PvtRWSetHash:   collRWSet.PvtRwsetHash,
+		})
+	}
+
+	return ns, nil
+}
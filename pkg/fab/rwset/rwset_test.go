@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rwset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+const (
+	testChannelID = "mychannel"
+	testCCID      = "examplecc"
+)
+
+func TestFromProposalResponse(t *testing.T) {
+	response := &pb.ProposalResponse{Payload: newTxBuilder("txid1").BuildProposalResponsePayload()}
+
+	action, err := FromProposalResponse(response)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	assert.Equal(t, testCCID, action.ChaincodeID.Name)
+	assert.Equal(t, "v1", action.ChaincodeID.Version)
+	assert.Equal(t, int32(200), action.Response.Status)
+	assert.Equal(t, []byte("payload"), action.Response.Payload)
+	if !assert.NotNil(t, action.Event) {
+		t.FailNow()
+	}
+	assert.Equal(t, "event1", action.Event.EventName)
+
+	ns := action.Namespace(testCCID)
+	if !assert.NotNil(t, ns) {
+		t.FailNow()
+	}
+	if !assert.Len(t, ns.Reads, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "a", ns.Reads[0].Key)
+	assert.Equal(t, uint64(5), ns.Reads[0].Version.BlockNum)
+	assert.Equal(t, uint64(2), ns.Reads[0].Version.TxNum)
+	if !assert.Len(t, ns.Writes, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "b", ns.Writes[0].Key)
+	assert.Equal(t, []byte("100"), ns.Writes[0].Value)
+	if !assert.Len(t, ns.Deletes, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "c", ns.Deletes[0].Key)
+	if !assert.Len(t, ns.RangeQueriesInfo, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "k1", ns.RangeQueriesInfo[0].StartKey)
+
+	if !assert.Len(t, ns.CollHashedRWSets, 1) {
+		t.FailNow()
+	}
+	coll := ns.CollHashedRWSets[0]
+	assert.Equal(t, "coll1", coll.CollectionName)
+	assert.Equal(t, []byte("pvthash"), coll.PvtRWSetHash)
+	if !assert.Len(t, coll.HashedWrites, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, []byte("keyhash"), coll.HashedWrites[0].KeyHash)
+
+	assert.NotNil(t, action.Namespace("lscc"))
+	assert.Nil(t, action.Namespace("othercc"))
+}
+
+func TestFromProposalResponseErrors(t *testing.T) {
+	_, err := FromProposalResponse(nil)
+	assert.Error(t, err)
+
+	_, err = FromProposalResponse(&pb.ProposalResponse{Payload: []byte("invalid")})
+	assert.Error(t, err)
+}
+
+func TestFromProposalResponseNoEvent(t *testing.T) {
+	ccAction := &pb.ChaincodeAction{
+		Events:   utils.MarshalOrPanic(&pb.ChaincodeEvent{}),
+		Response: &pb.Response{Status: 200},
+	}
+	prp := utils.MarshalOrPanic(&pb.ProposalResponsePayload{Extension: utils.MarshalOrPanic(ccAction)})
+
+	action, err := FromProposalResponsePayload(prp)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assert.Nil(t, action.Event)
+	assert.Empty(t, action.RWSet.NsRWSets)
+}
+
+func TestFromBlock(t *testing.T) {
+	envelopes := []*cb.Envelope{
+		newTxBuilder("").BuildEnvelopeWithData(cb.HeaderType_CONFIG, nil),
+		newTxBuilder("txid1").BuildEnvelope(),
+		newTxBuilder("txid2").BuildEnvelope(),
+	}
+	block := mocks.NewMockBlockWithEnvelopes(10, envelopes, []pb.TxValidationCode{
+		pb.TxValidationCode_VALID, pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT,
+	})
+
+	txs, err := FromBlock(block)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !assert.Len(t, txs, 2) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "txid1", txs[0].TxID)
+	assert.Equal(t, testChannelID, txs[0].ChannelID)
+	assert.Equal(t, pb.TxValidationCode_VALID, txs[0].TxValidationCode)
+	assert.Equal(t, "txid2", txs[1].TxID)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, txs[1].TxValidationCode)
+
+	if !assert.Len(t, txs[1].Actions, 1) {
+		t.FailNow()
+	}
+	ns := txs[1].Actions[0].Namespace(testCCID)
+	if !assert.NotNil(t, ns) {
+		t.FailNow()
+	}
+	assert.Equal(t, "b", ns.Writes[0].Key)
+	assert.True(t, txs[1].Validated)
+
+	// blocks delivered by an orderer have no transaction filter
+	block.Metadata = nil
+	txs, err = FromBlock(block)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, tx := range txs {
+		assert.False(t, tx.Validated)
+	}
+}
+
+func TestFromEnvelopeNotEndorserTransaction(t *testing.T) {
+	_, err := FromEnvelope(newTxBuilder("").BuildEnvelopeWithData(cb.HeaderType_CONFIG, nil))
+	assert.Error(t, err)
+}
+
+func newTxBuilder(txID string) *mocks.MockEndorserTxBuilder {
+	return &mocks.MockEndorserTxBuilder{ChannelID: testChannelID, TxID: txID, ChaincodeID: testCCID}
+}
@@ -16,8 +16,11 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	ccomm "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
@@ -54,19 +57,29 @@ func (th *TransactionHeader) ChannelID() string {
 // NewHeader computes a TransactionID from the current user context and holds
 // metadata to create transaction proposals.
 func NewHeader(ctx contextApi.Context, channelID string) (*TransactionHeader, error) {
-	// generate a random nonce
-	nonce, err := crypto.GetRandomNonce()
+	creator, err := ctx.Identity()
 	if err != nil {
-		return nil, errors.WithMessage(err, "nonce creation failed")
+		return nil, errors.WithMessage(err, "identity from context failed")
 	}
 
-	creator, err := ctx.Identity()
+	return NewHeaderWithCreator(ctx.CryptoSuite(), creator, channelID)
+}
+
+// NewHeaderWithCreator computes a TransactionID for the given serialized creator identity.
+// It is used to create proposals which are signed outside of the SDK by the creator's key.
+func NewHeaderWithCreator(cs core.CryptoSuite, creator []byte, channelID string) (*TransactionHeader, error) {
+	if len(creator) == 0 {
+		return nil, errors.New("creator is required")
+	}
+
+	// generate a random nonce
+	nonce, err := crypto.GetRandomNonce()
 	if err != nil {
-		return nil, errors.WithMessage(err, "identity from context failed")
+		return nil, errors.WithMessage(err, "nonce creation failed")
 	}
 
 	ho := cryptosuite.GetSHA256Opts() // TODO: make configurable
-	h, err := ctx.CryptoSuite().GetHash(ho)
+	h, err := cs.GetHash(ho)
 	if err != nil {
 		return nil, errors.WithMessage(err, "hash function creation failed")
 	}
@@ -199,6 +212,38 @@ func CreatePayload(txh *TransactionHeader, channelHeader *common.ChannelHeader,
 	return &payload, nil
 }
 
+// CreateSeekPayload creates the payload of a deliver request for the blocks of the seek info
+func CreateSeekPayload(ctx context, channelID string, seekInfo *ab.SeekInfo) (*common.Payload, error) {
+	txh, err := NewHeader(ctx, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "generating TX ID failed")
+	}
+
+	channelHeader, err := CreateChannelHeader(common.HeaderType_DELIVER_SEEK_INFO, ChannelHeaderOpts{
+		TxnHeader:   txh,
+		TLSCertHash: ccomm.TLSCertHash(ctx.Config()),
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "CreateChannelHeader failed")
+	}
+
+	seekInfoBytes, err := proto.Marshal(seekInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal seek info failed")
+	}
+
+	return CreatePayload(txh, channelHeader, seekInfoBytes)
+}
+
+// CreateSeekEnvelope creates the signed envelope of a deliver request for the blocks of the seek info
+func CreateSeekEnvelope(ctx context, channelID string, seekInfo *ab.SeekInfo) (*fab.SignedEnvelope, error) {
+	payload, err := CreateSeekPayload(ctx, channelID, seekInfo)
+	if err != nil {
+		return nil, err
+	}
+	return signPayload(ctx, payload)
+}
+
 // CreateSignatureHeader creates a SignatureHeader based on the nonce and creator of the transaction header.
 func CreateSignatureHeader(txh *TransactionHeader) (*common.SignatureHeader, error) {
 
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package txn
 
 import (
+	reqContext "context"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
@@ -54,8 +55,35 @@ func CreateChaincodeInvokeProposal(txh fab.TransactionHeader, request fab.Chainc
 	return &tp, nil
 }
 
-// signProposal creates a SignedProposal based on the current context.
-func signProposal(ctx context, proposal *pb.Proposal) (*pb.SignedProposal, error) {
+// UnsignedProposal is a transaction proposal together with the bytes that must be signed
+// by the proposal's creator. It allows the proposal to be signed outside of the SDK.
+type UnsignedProposal struct {
+	*fab.TransactionProposal
+	// Bytes is the marshalled proposal over which the signature is computed
+	Bytes []byte
+}
+
+// NewUnsignedProposal marshals the proposal so that it may be signed
+func NewUnsignedProposal(proposal *fab.TransactionProposal) (*UnsignedProposal, error) {
+	if proposal == nil || proposal.Proposal == nil {
+		return nil, errors.New("proposal is required")
+	}
+
+	proposalBytes, err := proto.Marshal(proposal.Proposal)
+	if err != nil {
+		return nil, errors.Wrap(err, "mashal proposal failed")
+	}
+
+	return &UnsignedProposal{TransactionProposal: proposal, Bytes: proposalBytes}, nil
+}
+
+// Sign returns the SignedProposal for the given signature over the proposal bytes
+func (p *UnsignedProposal) Sign(signature []byte) *pb.SignedProposal {
+	return &pb.SignedProposal{ProposalBytes: p.Bytes, Signature: signature}
+}
+
+// SignProposal creates a SignedProposal based on the current context.
+func SignProposal(ctx context, proposal *pb.Proposal) (*pb.SignedProposal, error) {
 	proposalBytes, err := proto.Marshal(proposal)
 	if err != nil {
 		return nil, errors.Wrap(err, "mashal proposal failed")
@@ -75,7 +103,8 @@ func signProposal(ctx context, proposal *pb.Proposal) (*pb.SignedProposal, error
 }
 
 // SendProposal sends a TransactionProposal to ProposalProcessor.
-func SendProposal(ctx context, proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+// Outstanding requests are abandoned once reqCtx is cancelled or its deadline expires.
+func SendProposal(reqCtx reqContext.Context, ctx context, proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
 
 	if proposal == nil {
 		return nil, errors.New("proposal is required")
@@ -85,11 +114,26 @@ func SendProposal(ctx context, proposal *fab.TransactionProposal, targets []fab.
 		return nil, errors.New("targets is required")
 	}
 
-	signedProposal, err := signProposal(ctx, proposal.Proposal)
+	signedProposal, err := SignProposal(ctx, proposal.Proposal)
 	if err != nil {
 		return nil, errors.WithMessage(err, "sign proposal failed")
 	}
 
+	return SendSignedProposal(reqCtx, signedProposal, targets)
+}
+
+// SendSignedProposal sends a proposal, which has already been signed, to ProposalProcessor.
+// Outstanding requests are abandoned once reqCtx is cancelled or its deadline expires.
+func SendSignedProposal(reqCtx reqContext.Context, signedProposal *pb.SignedProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+
+	if signedProposal == nil {
+		return nil, errors.New("signed proposal is required")
+	}
+
+	if len(targets) < 1 {
+		return nil, errors.New("targets is required")
+	}
+
 	request := fab.ProcessProposalRequest{SignedProposal: signedProposal}
 
 	var responseMtx sync.Mutex
@@ -102,7 +146,7 @@ func SendProposal(ctx context, proposal *fab.TransactionProposal, targets []fab.
 		go func(processor fab.ProposalProcessor) {
 			defer wg.Done()
 
-			resp, err := processor.ProcessTransactionProposal(request)
+			resp, err := processor.ProcessTransactionProposal(reqCtx, request)
 			if err != nil {
 				logger.Debugf("Received error response from txn proposal processing: %v", err)
 				responseMtx.Lock()
@@ -116,7 +160,18 @@ func SendProposal(ctx context, proposal *fab.TransactionProposal, targets []fab.
 			responseMtx.Unlock()
 		}(p)
 	}
-	wg.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-reqCtx.Done():
+		return nil, errors.Wrap(reqCtx.Err(), "sending proposal aborted")
+	}
 
 	return transactionProposalResponses, errs.ToError()
 }
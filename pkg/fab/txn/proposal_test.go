@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package txn
 
 import (
+	reqContext "context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -45,7 +46,7 @@ func TestNewTransactionProposal(t *testing.T) {
 		t.Fatalf("Create Transaction Proposal Failed: %s", err)
 	}
 
-	signedProposal, err := signProposal(ctx, tp.Proposal)
+	signedProposal, err := SignProposal(ctx, tp.Proposal)
 	if err != nil {
 		t.Fatalf("signProposal failed: %s", err)
 	}
@@ -81,7 +82,7 @@ func TestSendTransactionProposal(t *testing.T) {
 		t.Fatalf("new transaction proposal failed: %s", err)
 	}
 
-	tpr, err := SendProposal(ctx, tp, []fab.ProposalProcessor{&peer})
+	tpr, err := SendProposal(reqContext.Background(), ctx, tp, []fab.ProposalProcessor{&peer})
 	if err != nil {
 		t.Fatalf("send transaction proposal failed: %s", err)
 	}
@@ -93,6 +94,48 @@ func TestSendTransactionProposal(t *testing.T) {
 	}
 }
 
+func TestSendSignedProposal(t *testing.T) {
+	user := mocks.NewMockUserWithMSPID("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	txh, err := NewHeaderWithCreator(ctx.CryptoSuite(), []byte("creator"), testChannel)
+	if err != nil {
+		t.Fatalf("create transaction ID failed: %s", err)
+	}
+
+	tp, err := CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{ChaincodeID: "cc", Fcn: "Hello"})
+	if err != nil {
+		t.Fatalf("new transaction proposal failed: %s", err)
+	}
+
+	unsignedProposal, err := NewUnsignedProposal(tp)
+	if err != nil {
+		t.Fatalf("new unsigned proposal failed: %s", err)
+	}
+
+	proposal := &pb.Proposal{}
+	if err := proto.Unmarshal(unsignedProposal.Bytes, proposal); err != nil {
+		t.Fatalf("unmarshal of proposal bytes failed: %s", err)
+	}
+	assert.True(t, proto.Equal(tp.Proposal, proposal))
+
+	signedProposal := unsignedProposal.Sign([]byte("signature"))
+	assert.Equal(t, []byte("signature"), signedProposal.Signature)
+
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, Payload: []byte("A")}
+	tpr, err := SendSignedProposal(reqContext.Background(), signedProposal, []fab.ProposalProcessor{&peer})
+	if err != nil {
+		t.Fatalf("send signed proposal failed: %s", err)
+	}
+	assert.Equal(t, []byte("A"), tpr[0].ProposalResponse.Response.Payload)
+
+	_, err = NewHeaderWithCreator(ctx.CryptoSuite(), nil, testChannel)
+	assert.NotNil(t, err, "expected error for missing creator")
+
+	_, err = SendSignedProposal(reqContext.Background(), nil, []fab.ProposalProcessor{&peer})
+	assert.NotNil(t, err, "expected error for missing proposal")
+}
+
 func TestNewTransactionProposalParams(t *testing.T) {
 	user := mocks.NewMockUserWithMSPID("test", "1234")
 	ctx := mocks.NewMockContext(user)
@@ -112,7 +155,7 @@ func TestNewTransactionProposalParams(t *testing.T) {
 		t.Fatalf("new transaction proposal failed: %s", err)
 	}
 
-	_, err = SendProposal(ctx, tp, nil)
+	_, err = SendProposal(reqContext.Background(), ctx, tp, nil)
 	if err == nil {
 		t.Fatalf("Expected error")
 	}
@@ -152,7 +195,7 @@ func TestConcurrentPeers(t *testing.T) {
 	user := mocks.NewMockUserWithMSPID("test", "1234")
 	ctx := mocks.NewMockContext(user)
 
-	result, err := SendProposal(ctx, &fab.TransactionProposal{
+	result, err := SendProposal(reqContext.Background(), ctx, &fab.TransactionProposal{
 		Proposal: &pb.Proposal{},
 	}, peers)
 	if err != nil {
@@ -173,7 +216,7 @@ func TestSendTransactionProposalToProcessors(t *testing.T) {
 	defer mockCtrl.Finish()
 	proc := mock_context.NewMockProposalProcessor(mockCtrl)
 
-	stp, err := signProposal(ctx, &pb.Proposal{})
+	stp, err := SignProposal(ctx, &pb.Proposal{})
 	if err != nil {
 		t.Fatalf("signProposal returned error: %s", err)
 	}
@@ -182,10 +225,10 @@ func TestSendTransactionProposalToProcessors(t *testing.T) {
 	}
 
 	tpr := fab.TransactionProposalResponse{Endorser: "example.com", Status: 99}
-	proc.EXPECT().ProcessTransactionProposal(tp).Return(&tpr, nil)
+	proc.EXPECT().ProcessTransactionProposal(gomock.Any(), tp).Return(&tpr, nil)
 	targets := []fab.ProposalProcessor{proc}
 
-	result, err := SendProposal(ctx, &fab.TransactionProposal{
+	result, err := SendProposal(reqContext.Background(), ctx, &fab.TransactionProposal{
 		Proposal: &pb.Proposal{},
 	}, nil)
 
@@ -193,7 +236,7 @@ func TestSendTransactionProposalToProcessors(t *testing.T) {
 		t.Fatalf("Test SendTransactionProposal failed, validation on peer is nil is not working as expected: %v", err)
 	}
 
-	result, err = SendProposal(ctx, &fab.TransactionProposal{
+	result, err = SendProposal(reqContext.Background(), ctx, &fab.TransactionProposal{
 		Proposal: &pb.Proposal{},
 	}, []fab.ProposalProcessor{})
 
@@ -201,7 +244,7 @@ func TestSendTransactionProposalToProcessors(t *testing.T) {
 		t.Fatalf("Test SendTransactionProposal failed, validation on missing peer objects is not working: %v", err)
 	}
 
-	result, err = SendProposal(ctx, &fab.TransactionProposal{
+	result, err = SendProposal(reqContext.Background(), ctx, &fab.TransactionProposal{
 		Proposal: &pb.Proposal{}}, targets)
 
 	if result == nil || err != nil {
@@ -220,7 +263,7 @@ func TestProposalResponseError(t *testing.T) {
 	proc := mock_context.NewMockProposalProcessor(mockCtrl)
 	proc2 := mock_context.NewMockProposalProcessor(mockCtrl)
 
-	stp, err := signProposal(ctx, &pb.Proposal{})
+	stp, err := SignProposal(ctx, &pb.Proposal{})
 	if err != nil {
 		t.Fatalf("signProposal returned error: %s", err)
 	}
@@ -230,11 +273,11 @@ func TestProposalResponseError(t *testing.T) {
 
 	// Test with error from lower layer
 	tpr := fab.TransactionProposalResponse{Endorser: "example.com", Status: 200}
-	proc.EXPECT().ProcessTransactionProposal(tp).Return(&tpr, testError)
-	proc2.EXPECT().ProcessTransactionProposal(tp).Return(&tpr, testError)
+	proc.EXPECT().ProcessTransactionProposal(gomock.Any(), tp).Return(&tpr, testError)
+	proc2.EXPECT().ProcessTransactionProposal(gomock.Any(), tp).Return(&tpr, testError)
 
 	targets := []fab.ProposalProcessor{proc, proc2}
-	_, err = SendProposal(ctx, &fab.TransactionProposal{
+	_, err = SendProposal(reqContext.Background(), ctx, &fab.TransactionProposal{
 		Proposal: &pb.Proposal{},
 	}, targets)
 	errs, ok := err.(multi.Errors)
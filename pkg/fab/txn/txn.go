@@ -9,10 +9,12 @@ package txn
 
 import (
 	"bytes"
+	reqContext "context"
 	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/context"
@@ -110,10 +112,69 @@ func New(request fab.TransactionRequest) (*fab.Transaction, error) {
 }
 
 // Send send a transaction to the chain’s orderer service (one or more orderer endpoints) for consensus and committing to the ledger.
-func Send(ctx context, tx *fab.Transaction, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+// Sending stops once reqCtx is cancelled or its deadline expires.
+func Send(reqCtx reqContext.Context, ctx context, tx *fab.Transaction, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
 	if orderers == nil || len(orderers) == 0 {
 		return nil, errors.New("orderers is nil")
 	}
+
+	payload, err := createTransactionPayload(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionResponse, err := BroadcastPayload(reqCtx, ctx, payload, orderers)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactionResponse, nil
+}
+
+// UnsignedTransaction is a transaction together with the payload bytes that must be signed
+// by the transaction's creator. It allows the transaction to be signed outside of the SDK.
+type UnsignedTransaction struct {
+	*fab.Transaction
+	// Bytes is the marshalled transaction payload over which the signature is computed
+	Bytes []byte
+}
+
+// NewUnsignedTransaction creates the transaction payload so that it may be signed
+func NewUnsignedTransaction(tx *fab.Transaction) (*UnsignedTransaction, error) {
+	payload, err := createTransactionPayload(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "marshaling of payload failed")
+	}
+
+	return &UnsignedTransaction{Transaction: tx, Bytes: payloadBytes}, nil
+}
+
+// TransactionID returns the ID of the transaction
+func (t *UnsignedTransaction) TransactionID() fab.TransactionID {
+	return t.Proposal.TxnID
+}
+
+// Sign returns the SignedEnvelope for the given signature over the payload bytes
+func (t *UnsignedTransaction) Sign(signature []byte) *fab.SignedEnvelope {
+	return &fab.SignedEnvelope{Payload: t.Bytes, Signature: signature}
+}
+
+// SendEnvelope sends an envelope, which has already been signed, to some orderer, picking
+// random endpoints until all are exhausted or reqCtx is done
+func SendEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+	if envelope == nil {
+		return nil, errors.New("envelope is nil")
+	}
+	return broadcastEnvelope(reqCtx, envelope, orderers)
+}
+
+// createTransactionPayload creates the payload, which is signed and sent to the orderer, for the transaction
+func createTransactionPayload(tx *fab.Transaction) (*common.Payload, error) {
 	if tx == nil {
 		return nil, errors.New("transaction is nil")
 	}
@@ -133,19 +194,12 @@ func Send(ctx context, tx *fab.Transaction, orderers []fab.Orderer) (*fab.Transa
 	}
 
 	// create the payload
-	payload := common.Payload{Header: hdr, Data: txBytes}
-
-	transactionResponse, err := BroadcastPayload(ctx, &payload, orderers)
-	if err != nil {
-		return nil, err
-	}
-
-	return transactionResponse, nil
+	return &common.Payload{Header: hdr, Data: txBytes}, nil
 }
 
 // BroadcastPayload will send the given payload to some orderer, picking random endpoints
-// until all are exhausted
-func BroadcastPayload(ctx context, payload *common.Payload, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+// until all are exhausted or reqCtx is done
+func BroadcastPayload(reqCtx reqContext.Context, ctx context, payload *common.Payload, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
 	// Check if orderers are defined
 	if len(orderers) == 0 {
 		return nil, errors.New("orderers not set")
@@ -156,12 +210,12 @@ func BroadcastPayload(ctx context, payload *common.Payload, orderers []fab.Order
 		return nil, err
 	}
 
-	return broadcastEnvelope(ctx, envelope, orderers)
+	return broadcastEnvelope(reqCtx, envelope, orderers)
 }
 
 // broadcastEnvelope will send the given envelope to some orderer, picking random endpoints
-// until all are exhausted
-func broadcastEnvelope(ctx context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+// until all are exhausted or reqCtx is done
+func broadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
 	// Check if orderers are defined
 	if len(orderers) == 0 {
 		return nil, errors.New("orderers not set")
@@ -176,7 +230,11 @@ func broadcastEnvelope(ctx context, envelope *fab.SignedEnvelope, orderers []fab
 	// Iterate them in a random order and try broadcasting 1 by 1
 	var errResp *fab.TransactionResponse
 	for _, i := range rand.Perm(len(randOrderers)) {
-		resp := sendBroadcast(envelope, randOrderers[i])
+		if errResp != nil && reqCtx.Err() != nil {
+			logger.Debugf("Request context done - not trying remaining orderers: %s", reqCtx.Err())
+			break
+		}
+		resp := sendBroadcast(reqCtx, envelope, randOrderers[i])
 		if resp.Err != nil {
 			errResp = resp
 		} else {
@@ -186,9 +244,9 @@ func broadcastEnvelope(ctx context, envelope *fab.SignedEnvelope, orderers []fab
 	return errResp, nil
 }
 
-func sendBroadcast(envelope *fab.SignedEnvelope, orderer fab.Orderer) *fab.TransactionResponse {
+func sendBroadcast(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderer fab.Orderer) *fab.TransactionResponse {
 	logger.Debugf("Broadcasting envelope to orderer :%s\n", orderer.URL())
-	if _, err := orderer.SendBroadcast(envelope); err != nil {
+	if _, err := orderer.SendBroadcast(reqCtx, envelope); err != nil {
 		logger.Debugf("Receive Error Response from orderer :%v\n", err)
 		return &fab.TransactionResponse{Orderer: orderer.URL(),
 			Err: errors.Wrapf(err, "calling orderer '%s' failed", orderer.URL())}
@@ -290,7 +348,7 @@ type Status struct {
 // the transaction committed successfully, otherwise the code indicates the error
 // that occurred.
 func RegisterStatus(txID fab.TransactionID, eventHub fab.EventHub) chan Status {
-	statusNotifier := make(chan Status)
+	statusNotifier := make(chan Status, 1)
 
 	eventHub.RegisterTxEvent(txID, func(txId fab.TransactionID, code pb.TxValidationCode, err error) {
 		logger.Debugf("Received code(%s) for txid(%s) and err(%s)\n", code, txId, err)
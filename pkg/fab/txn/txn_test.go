@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package txn
 
 import (
+	reqContext "context"
 	"crypto/rand"
 	"fmt"
 	"os"
@@ -15,9 +16,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
@@ -140,9 +143,6 @@ func (r *mockReader) Read(p []byte) (int, error) {
 }
 
 func TestBroadcastEnvelope(t *testing.T) {
-	user := mocks.NewMockUserWithMSPID("test", "1234")
-	ctx := mocks.NewMockContext(user)
-
 	lsnr1 := make(chan *fab.SignedEnvelope)
 	lsnr2 := make(chan *fab.SignedEnvelope)
 	//Create mock orderers
@@ -155,7 +155,7 @@ func TestBroadcastEnvelope(t *testing.T) {
 		Signature: []byte(""),
 		Payload:   []byte(""),
 	}
-	res, err := broadcastEnvelope(ctx, sigEnvelope, orderers)
+	res, err := broadcastEnvelope(reqContext.Background(), sigEnvelope, orderers)
 
 	if err != nil || res.Err != nil {
 		t.Fatalf("Test Broadcast Envelope Failed, cause %v %v", err, res)
@@ -185,7 +185,7 @@ func TestBroadcastEnvelope(t *testing.T) {
 	}
 	// It should always succeed even though one of them has failed
 	for i := 0; i < broadcastCount; i++ {
-		if res, err := broadcastEnvelope(ctx, sigEnvelope, orderers); err != nil || res.Err != nil {
+		if res, err := broadcastEnvelope(reqContext.Background(), sigEnvelope, orderers); err != nil || res.Err != nil {
 			t.Fatalf("Test Broadcast Envelope Failed, cause %v %v", err, res)
 		}
 	}
@@ -197,7 +197,7 @@ func TestBroadcastEnvelope(t *testing.T) {
 	}
 
 	for i := 0; i < broadcastCount; i++ {
-		res, err := broadcastEnvelope(ctx, sigEnvelope, orderers)
+		res, err := broadcastEnvelope(reqContext.Background(), sigEnvelope, orderers)
 		if err != nil {
 			t.Fatalf("Test Broadcast sending failed, cause %v", err)
 		}
@@ -210,7 +210,7 @@ func TestBroadcastEnvelope(t *testing.T) {
 	}
 
 	emptyOrderers := []fab.Orderer{}
-	_, err = broadcastEnvelope(ctx, sigEnvelope, emptyOrderers)
+	_, err = broadcastEnvelope(reqContext.Background(), sigEnvelope, emptyOrderers)
 
 	if err == nil || err.Error() != "orderers not set" {
 		t.Fatal("orderers not set validation on broadcast envelope is not working as expected")
@@ -222,7 +222,7 @@ func TestSendTransaction(t *testing.T) {
 	user := mocks.NewMockUserWithMSPID("test", "1234")
 	ctx := mocks.NewMockContext(user)
 
-	response, err := Send(ctx, nil, nil)
+	response, err := Send(reqContext.Background(), ctx, nil, nil)
 
 	//Expect orderer is nil error
 	if response != nil || err == nil || err.Error() != "orderers is nil" {
@@ -234,7 +234,7 @@ func TestSendTransaction(t *testing.T) {
 	orderers := []fab.Orderer{orderer}
 
 	//Call Send Transaction with nil tx
-	response, err = Send(ctx, nil, orderers)
+	response, err = Send(reqContext.Background(), ctx, nil, orderers)
 
 	//Expect tx is nil error
 	if response != nil || err == nil || err.Error() != "transaction is nil" {
@@ -250,7 +250,7 @@ func TestSendTransaction(t *testing.T) {
 	}
 
 	//Call Send Transaction with nil proposal
-	response, err = Send(ctx, &txn, orderers)
+	response, err = Send(reqContext.Background(), ctx, &txn, orderers)
 
 	//Expect proposal is nil error
 	if response != nil || err == nil || err.Error() != "proposal is nil" {
@@ -265,7 +265,7 @@ func TestSendTransaction(t *testing.T) {
 		Transaction: &pb.Transaction{},
 	}
 	//Call Send Transaction
-	response, err = Send(ctx, &txn, orderers)
+	response, err = Send(reqContext.Background(), ctx, &txn, orderers)
 
 	//Expect header unmarshal error
 	if response != nil || err == nil || !strings.Contains(err.Error(), "unmarshal") {
@@ -281,7 +281,7 @@ func TestSendTransaction(t *testing.T) {
 	}
 
 	//Call Send Transaction
-	response, err = Send(ctx, &txn, orderers)
+	response, err = Send(reqContext.Background(), ctx, &txn, orderers)
 
 	if response == nil || err != nil {
 		t.Fatalf("Test SendTransaction failed, reason : '%s'", err.Error())
@@ -341,7 +341,7 @@ func TestConcurrentOrderers(t *testing.T) {
 		},
 		Transaction: &pb.Transaction{},
 	}
-	_, err = Send(ctx, &txn, orderers)
+	_, err = Send(reqContext.Background(), ctx, &txn, orderers)
 	if err != nil {
 		t.Fatalf("SendTransaction returned error: %s", err)
 	}
@@ -357,3 +357,36 @@ func setupMassiveTestOrderer(numberOfOrderers int) []fab.Orderer {
 
 	return orderers
 }
+
+func TestCreateSeekEnvelope(t *testing.T) {
+	user := mocks.NewMockUserWithMSPID("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	seekInfo := &ab.SeekInfo{
+		Start:    &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 5}}},
+		Stop:     &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 5}}},
+		Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
+	}
+	envelope, err := CreateSeekEnvelope(ctx, "mychannel", seekInfo)
+	if err != nil {
+		t.Fatalf("Failed to create seek envelope: %s", err)
+	}
+	assert.NotEmpty(t, envelope.Signature)
+
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		t.Fatalf("Failed to unmarshal payload: %s", err)
+	}
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.Header.ChannelHeader, channelHeader); err != nil {
+		t.Fatalf("Failed to unmarshal channel header: %s", err)
+	}
+	assert.Equal(t, int32(common.HeaderType_DELIVER_SEEK_INFO), channelHeader.Type)
+	assert.Equal(t, "mychannel", channelHeader.ChannelId)
+
+	sentSeekInfo := &ab.SeekInfo{}
+	if err := proto.Unmarshal(payload.Data, sentSeekInfo); err != nil {
+		t.Fatalf("Failed to unmarshal seek info: %s", err)
+	}
+	assert.True(t, proto.Equal(seekInfo, sentSeekInfo), "unexpected seek info")
+}
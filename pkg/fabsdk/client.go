@@ -8,6 +8,7 @@ package fabsdk
 
 import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
@@ -197,6 +198,34 @@ func (c *ClientContext) Channel(id string, opts ...ClientOption) (*channel.Clien
 	return client, nil
 }
 
+// Ledger returns a client API for querying the ledger of a channel.
+func (c *ClientContext) Ledger(id string, opts ...ClientOption) (*ledger.Client, error) {
+	p, err := c.provider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to get client provider context")
+	}
+	o, err := newClientOptions(opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to retrieve client options")
+	}
+
+	channelService, err := p.providers.ChannelProvider().ChannelService(p.identity, id)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get channel service")
+	}
+	discovery, err := p.providers.DiscoveryProvider().NewDiscoveryService(id)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create discovery service")
+	}
+
+	ctx := ledger.Context{
+		ChannelID:        id,
+		DiscoveryService: discovery,
+		ChannelService:   channelService,
+	}
+	return ledger.New(ctx, ledger.WithDefaultTargetFilter(o.targetFilter))
+}
+
 // ChannelService returns a client API for interacting with a channel.
 func (c *ClientContext) ChannelService(id string) (fab.ChannelService, error) {
 	p, err := c.provider()
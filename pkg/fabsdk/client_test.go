@@ -87,6 +87,23 @@ func TestClient(t *testing.T) {
 	}
 }
 
+func TestLedgerClient(t *testing.T) {
+	sdk, err := New(configImpl.FromFile(clientConfigFile))
+	if err != nil {
+		t.Fatalf("Expected no error from New, but got %v", err)
+	}
+
+	_, err = sdk.NewClient(WithUser(clientValidUser)).Ledger("mychannel", WithTargetFilter(&mockTargetFilter{}))
+	if err != nil {
+		t.Fatalf("Expected no error from Ledger, but got %v", err)
+	}
+
+	_, err = sdk.NewClient(noopIdentityOpt()).Ledger("mychannel")
+	if err == nil {
+		t.Fatal("Expected error from Ledger")
+	}
+}
+
 func TestWithOrg(t *testing.T) {
 	sdk, err := New(configImpl.FromFile(clientConfigFile))
 	if err != nil {
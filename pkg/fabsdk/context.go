@@ -11,6 +11,7 @@ import (
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/context/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
 	"github.com/pkg/errors"
 )
@@ -33,6 +34,11 @@ func (c *fabContext) CryptoSuite() core.CryptoSuite {
 	return c.sdk.cryptoSuite
 }
 
+// Connector returns the connection cache of the fabric provider
+func (c *fabContext) Connector() *comm.CachingConnector {
+	return comm.ContextConnector(c.sdk.fabricProvider)
+}
+
 // SigningManager returns signing manager
 func (c *fabContext) SigningManager() contextApi.SigningManager {
 	return c.sdk.signingManager
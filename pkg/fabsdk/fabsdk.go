@@ -18,6 +18,7 @@ import (
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/context/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/chpvdr"
 	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
@@ -162,6 +163,15 @@ type providerInit interface {
 	Initialize(sdk *FabricSDK) error
 }
 
+// connectorInit interface allows the discovery provider to share the connections of the SDK
+type connectorInit interface {
+	InitConnector(connector *comm.CachingConnector)
+}
+
+type closable interface {
+	Close()
+}
+
 func initSDK(sdk *FabricSDK, opts []Option) error {
 	for _, option := range opts {
 		err := option(&sdk.opts)
@@ -219,6 +229,9 @@ func initSDK(sdk *FabricSDK, opts []Option) error {
 	if pi, ok := discoveryProvider.(providerInit); ok {
 		pi.Initialize(sdk)
 	}
+	if ci, ok := discoveryProvider.(connectorInit); ok {
+		ci.InitConnector(comm.ContextConnector(fabricProvider))
+	}
 	sdk.discoveryProvider = discoveryProvider
 
 	// Initialize selection provider (for selecting endorsing peers)
@@ -240,6 +253,17 @@ func initSDK(sdk *FabricSDK, opts []Option) error {
 	return nil
 }
 
+// Close frees up caches and connections being maintained by the SDK.
+// Connections that are still in use are closed once they are released.
+func (sdk *FabricSDK) Close() {
+	if sdk.channelProvider != nil {
+		sdk.channelProvider.Close()
+	}
+	if c, ok := sdk.fabricProvider.(closable); ok {
+		c.Close()
+	}
+}
+
 // Config returns the SDK's configuration.
 func (sdk *FabricSDK) Config() core.Config {
 	return sdk.config
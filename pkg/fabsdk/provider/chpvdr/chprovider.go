@@ -9,6 +9,8 @@ package chpvdr
 import (
 	"sync"
 
+	"github.com/pkg/errors"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
@@ -22,16 +24,65 @@ import (
 // TODO: add listener for channel config changes. Upon channel config change,
 // underlying channel services need to recreate their channel clients.
 type ChannelProvider struct {
-	fabricProvider api.FabricProvider
-	chCfgMap       sync.Map
+	fabricProvider  api.FabricProvider
+	chCfgMap        sync.Map
+	eventClientLock sync.Mutex
+	eventClients    map[eventClientKey]*eventService
+}
+
+// eventClientKey identifies the event client shared by channel services
+// of the same identity on the same channel
+type eventClientKey struct {
+	channelID string
+	mspID     string
+	identity  string
 }
 
 // New creates a ChannelProvider based on a context
 func New(fabricProvider api.FabricProvider) (*ChannelProvider, error) {
-	cp := ChannelProvider{fabricProvider: fabricProvider}
+	cp := ChannelProvider{
+		fabricProvider: fabricProvider,
+		eventClients:   make(map[eventClientKey]*eventService),
+	}
 	return &cp, nil
 }
 
+// Close closes the event clients that are shared by the channel services
+func (cp *ChannelProvider) Close() {
+	cp.eventClientLock.Lock()
+	defer cp.eventClientLock.Unlock()
+
+	for key, es := range cp.eventClients {
+		logger.Debugf("Closing event client for channel [%s]", key.channelID)
+		es.Close()
+		delete(cp.eventClients, key)
+	}
+}
+
+func (cp *ChannelProvider) eventService(ic context.IdentityContext, channelID string) (fab.EventService, error) {
+	identity, err := ic.Identity()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get identity")
+	}
+	key := eventClientKey{channelID: channelID, mspID: ic.MspID(), identity: string(identity)}
+
+	cp.eventClientLock.Lock()
+	defer cp.eventClientLock.Unlock()
+
+	if es, ok := cp.eventClients[key]; ok && !es.closed() {
+		return es, nil
+	}
+
+	client, err := cp.fabricProvider.CreateEventClient(ic, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "event client creation failed")
+	}
+
+	es := &eventService{EventClient: client}
+	cp.eventClients[key] = es
+	return es, nil
+}
+
 // ChannelService creates a ChannelService for an identity
 func (cp *ChannelProvider) ChannelService(ic context.IdentityContext, channelID string) (fab.ChannelService, error) {
 
@@ -99,6 +150,12 @@ func (cs *ChannelService) Ledger() (fab.ChannelLedger, error) {
 	return cs.fabricProvider.CreateChannelLedger(cs.identityContext, cs.cfg.Name())
 }
 
+// EventService returns the event service for the named channel. The event service is shared
+// by all channel services of the same identity and connects on first registration.
+func (cs *ChannelService) EventService() (fab.EventService, error) {
+	return cs.provider.eventService(cs.identityContext, cs.cfg.Name())
+}
+
 // Transactor returns a transaction client for the current context and named channel.
 func (cs *ChannelService) Transactor() (fab.Transactor, error) {
 	return cs.fabricProvider.CreateChannelTransactor(cs.identityContext, cs.cfg)
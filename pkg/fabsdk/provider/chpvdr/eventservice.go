@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chpvdr
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+// eventService wraps an event client and connects it when the first registration
+// is made. Once connected, the event client takes care of reconnecting on its own.
+type eventService struct {
+	fab.EventClient
+	lock      sync.Mutex
+	connected bool
+}
+
+type stoppable interface {
+	Stopped() bool
+}
+
+func (s *eventService) connect() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.connected {
+		return nil
+	}
+
+	if err := s.EventClient.Connect(); err != nil {
+		return errors.WithMessage(err, "event client connect failed")
+	}
+	s.connected = true
+	return nil
+}
+
+// closed returns true if the event client has been stopped, for example
+// because it could not reconnect to any event source
+func (s *eventService) closed() bool {
+	if c, ok := s.EventClient.(stoppable); ok {
+		return c.Stopped()
+	}
+	return false
+}
+
+// RegisterBlockEvent registers for block events
+func (s *eventService) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	if err := s.connect(); err != nil {
+		return nil, nil, err
+	}
+	return s.EventClient.RegisterBlockEvent(filter...)
+}
+
+// RegisterFilteredBlockEvent registers for filtered block events
+func (s *eventService) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	if err := s.connect(); err != nil {
+		return nil, nil, err
+	}
+	return s.EventClient.RegisterFilteredBlockEvent()
+}
+
+// RegisterChaincodeEvent registers for chaincode events
+func (s *eventService) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	if err := s.connect(); err != nil {
+		return nil, nil, err
+	}
+	return s.EventClient.RegisterChaincodeEvent(ccID, eventFilter)
+}
+
+// RegisterTxStatusEvent registers for transaction status events
+func (s *eventService) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	if err := s.connect(); err != nil {
+		return nil, nil, err
+	}
+	return s.EventClient.RegisterTxStatusEvent(txID)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chpvdr
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
+)
+
+// mockEventClient counts the calls made by the channel provider
+type mockEventClient struct {
+	*mocks.MockEventService
+	lock       sync.Mutex
+	connectErr error
+	connects   int
+	closed     bool
+	stopped    bool
+}
+
+func (c *mockEventClient) Connect() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.connects++
+	return c.connectErr
+}
+
+func (c *mockEventClient) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.closed = true
+}
+
+func (c *mockEventClient) Stopped() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.stopped
+}
+
+func (c *mockEventClient) RegisterConnectionEvent() (fab.Registration, chan *fab.ConnectionEvent, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+// mockFabricProvider creates mock event clients. All other functions are not implemented.
+type mockFabricProvider struct {
+	api.FabricProvider
+	connectErr error
+	clients    []*mockEventClient
+}
+
+func (p *mockFabricProvider) CreateEventClient(ic context.IdentityContext, channelID string) (fab.EventClient, error) {
+	client := &mockEventClient{MockEventService: mocks.NewMockEventService(), connectErr: p.connectErr}
+	p.clients = append(p.clients, client)
+	return client, nil
+}
+
+func TestEventServiceSharedAndLazilyConnected(t *testing.T) {
+	fp := &mockFabricProvider{}
+	cp, err := New(fp)
+	if err != nil {
+		t.Fatalf("Unexpected error creating Channel Provider: %v", err)
+	}
+
+	user := mocks.NewMockUserWithMSPID("user1", "Org1MSP")
+	es1, err := cp.eventService(user, "mychannel")
+	if err != nil {
+		t.Fatalf("Unexpected error getting event service: %v", err)
+	}
+	es2, err := cp.eventService(user, "mychannel")
+	if err != nil {
+		t.Fatalf("Unexpected error getting event service: %v", err)
+	}
+	if es1 != es2 || len(fp.clients) != 1 {
+		t.Fatalf("Expecting the event service to be shared by the same identity on the same channel")
+	}
+	if fp.clients[0].connects != 0 {
+		t.Fatalf("Expecting the event client not to be connected before the first registration")
+	}
+
+	if _, _, err := es1.RegisterTxStatusEvent("txid1"); err != nil {
+		t.Fatalf("Unexpected error registering for transaction status: %v", err)
+	}
+	if _, _, err := es2.RegisterTxStatusEvent("txid2"); err != nil {
+		t.Fatalf("Unexpected error registering for transaction status: %v", err)
+	}
+	if fp.clients[0].connects != 1 {
+		t.Fatalf("Expecting the event client to be connected once but got %d connects", fp.clients[0].connects)
+	}
+
+	if _, err := cp.eventService(user, "otherchannel"); err != nil {
+		t.Fatalf("Unexpected error getting event service: %v", err)
+	}
+	if _, err := cp.eventService(mocks.NewMockUserWithMSPID("user2", "Org2MSP"), "mychannel"); err != nil {
+		t.Fatalf("Unexpected error getting event service: %v", err)
+	}
+	if len(fp.clients) != 3 {
+		t.Fatalf("Expecting an event client per identity and channel but got %d event clients", len(fp.clients))
+	}
+
+	cp.Close()
+	for _, client := range fp.clients {
+		if !client.closed {
+			t.Fatalf("Expecting all event clients to be closed")
+		}
+	}
+}
+
+func TestEventServiceRecreatedWhenStopped(t *testing.T) {
+	fp := &mockFabricProvider{}
+	cp, err := New(fp)
+	if err != nil {
+		t.Fatalf("Unexpected error creating Channel Provider: %v", err)
+	}
+	defer cp.Close()
+
+	user := mocks.NewMockUserWithMSPID("user1", "Org1MSP")
+	es1, err := cp.eventService(user, "mychannel")
+	if err != nil {
+		t.Fatalf("Unexpected error getting event service: %v", err)
+	}
+
+	// the event client gave up reconnecting
+	fp.clients[0].stopped = true
+
+	es2, err := cp.eventService(user, "mychannel")
+	if err != nil {
+		t.Fatalf("Unexpected error getting event service: %v", err)
+	}
+	if es1 == es2 || len(fp.clients) != 2 {
+		t.Fatalf("Expecting a new event client once the previous one is stopped")
+	}
+}
+
+func TestEventServiceConnectError(t *testing.T) {
+	fp := &mockFabricProvider{connectErr: errors.New("connect failed")}
+	cp, err := New(fp)
+	if err != nil {
+		t.Fatalf("Unexpected error creating Channel Provider: %v", err)
+	}
+	defer cp.Close()
+
+	es, err := cp.eventService(mocks.NewMockUserWithMSPID("user1", "Org1MSP"), "mychannel")
+	if err != nil {
+		t.Fatalf("Unexpected error getting event service: %v", err)
+	}
+	if _, _, err := es.RegisterTxStatusEvent("txid1"); err == nil {
+		t.Fatalf("Expecting error registering with an event client that cannot connect")
+	}
+
+	// the next registration tries to connect again
+	fp.clients[0].connectErr = nil
+	if _, _, err := es.RegisterTxStatusEvent("txid1"); err != nil {
+		t.Fatalf("Unexpected error registering for transaction status: %v", err)
+	}
+	if fp.clients[0].connects != 2 {
+		t.Fatalf("Expecting two connect attempts but got %d", fp.clients[0].connects)
+	}
+}
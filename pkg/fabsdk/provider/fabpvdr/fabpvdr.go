@@ -7,13 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package fabpvdr
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/context/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	channelImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/endpoint"
 	identityImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/identity"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/identitymgr"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
@@ -26,27 +32,51 @@ import (
 // FabricProvider represents the default implementation of Fabric objects.
 type FabricProvider struct {
 	providerContext context.ProviderContext
+	connector       *comm.CachingConnector
 }
 
 type fabContext struct {
 	context.ProviderContext
 	context.IdentityContext
+	connector *comm.CachingConnector
+}
+
+// Connector returns the connection cache of the SDK
+func (c *fabContext) Connector() *comm.CachingConnector {
+	return c.connector
 }
 
 // New creates a FabricProvider enabling access to core Fabric objects and functionality.
 func New(ctx context.ProviderContext) *FabricProvider {
 	f := FabricProvider{
 		providerContext: ctx,
+		connector:       comm.NewCachingConnector(comm.DefaultConnSweepTime, comm.DefaultConnIdleTime),
 	}
 	return &f
 }
 
-// CreateResourceClient returns a new client initialized for the current instance of the SDK.
-func (f *FabricProvider) CreateResourceClient(ic context.IdentityContext) (api.Resource, error) {
-	ctx := &fabContext{
+// Connector returns the cache of the connections to peers and orderers
+func (f *FabricProvider) Connector() *comm.CachingConnector {
+	return f.connector
+}
+
+// Close closes the cached connections. Connections that are still in use are closed once
+// they are released.
+func (f *FabricProvider) Close() {
+	f.connector.Close()
+}
+
+func (f *FabricProvider) newContext(ic context.IdentityContext) *fabContext {
+	return &fabContext{
 		ProviderContext: f.providerContext,
 		IdentityContext: ic,
+		connector:       f.connector,
 	}
+}
+
+// CreateResourceClient returns a new client initialized for the current instance of the SDK.
+func (f *FabricProvider) CreateResourceClient(ic context.IdentityContext) (api.Resource, error) {
+	ctx := f.newContext(ic)
 	client := clientImpl.New(ctx)
 
 	return client, nil
@@ -54,10 +84,7 @@ func (f *FabricProvider) CreateResourceClient(ic context.IdentityContext) (api.R
 
 // CreateChannelClient returns a new client initialized for the current instance of the SDK.
 func (f *FabricProvider) CreateChannelClient(ic context.IdentityContext, cfg fab.ChannelCfg) (fab.Channel, error) {
-	ctx := &fabContext{
-		ProviderContext: f.providerContext,
-		IdentityContext: ic,
-	}
+	ctx := f.newContext(ic)
 	channel, err := channelImpl.New(ctx, cfg)
 	if err != nil {
 		return nil, errors.WithMessage(err, "NewChannel failed")
@@ -68,10 +95,7 @@ func (f *FabricProvider) CreateChannelClient(ic context.IdentityContext, cfg fab
 
 // CreateChannelLedger returns a new client initialized for the current instance of the SDK.
 func (f *FabricProvider) CreateChannelLedger(ic context.IdentityContext, channelName string) (fab.ChannelLedger, error) {
-	ctx := &fabContext{
-		ProviderContext: f.providerContext,
-		IdentityContext: ic,
-	}
+	ctx := f.newContext(ic)
 	ledger, err := channelImpl.NewLedger(ctx, channelName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "NewLedger failed")
@@ -107,26 +131,35 @@ func (f *FabricProvider) CreateEventHub(ic context.IdentityContext, channelID st
 	return events.FromConfig(eventCtx, &eventSource.PeerConfig)
 }
 
-// CreateChannelConfig initializes the channel config
-func (f *FabricProvider) CreateChannelConfig(ic context.IdentityContext, channelID string) (fab.ChannelConfig, error) {
-
-	ctx := chconfig.Context{
-		ProviderContext: f.providerContext,
-		IdentityContext: ic,
+// CreateEventClient returns a filtered deliver client for the given channel. The client is
+// not connected. When the connection to an event source is lost, the client reconnects to
+// another event-source peer of the identity's organization and resumes from the last block received.
+func (f *FabricProvider) CreateEventClient(ic context.IdentityContext, channelID string) (fab.EventClient, error) {
+	discovery, err := endpoint.NewDiscoveryService(f.providerContext.Config(), channelID, ic.MspID())
+	if err != nil {
+		return nil, err
+	}
+	peers, err := discovery.GetPeers()
+	if err != nil {
+		return nil, err
 	}
 
-	return chconfig.New(ctx, channelID)
+	ctx := f.newContext(ic)
+	return deliverclient.New(ctx, channelID, discovery,
+		client.WithMaxConnectAttempts(uint(len(peers))),
+		client.WithTimeBetweenConnectAttempts(time.Second),
+		client.WithResponseTimeout(f.providerContext.Config().TimeoutOrDefault(core.EventReg)),
+	)
+}
+
+// CreateChannelConfig initializes the channel config
+func (f *FabricProvider) CreateChannelConfig(ic context.IdentityContext, channelID string) (fab.ChannelConfig, error) {
+	return chconfig.New(f.newContext(ic), channelID)
 }
 
 // CreateChannelTransactor initializes the transactor
 func (f *FabricProvider) CreateChannelTransactor(ic context.IdentityContext, cfg fab.ChannelCfg) (fab.Transactor, error) {
-
-	ctx := chconfig.Context{
-		ProviderContext: f.providerContext,
-		IdentityContext: ic,
-	}
-
-	return channelImpl.NewTransactor(ctx, cfg)
+	return channelImpl.NewTransactor(f.newContext(ic), cfg)
 }
 
 // CreateIdentityManager returns a new IdentityManager for an organization
@@ -147,12 +180,12 @@ func (f *FabricProvider) CreateUser(name string, signingIdentity *contextApi.Sig
 
 // CreatePeerFromConfig returns a new default implementation of Peer based configuration
 func (f *FabricProvider) CreatePeerFromConfig(peerCfg *core.NetworkPeer) (fab.Peer, error) {
-	return peerImpl.New(f.providerContext.Config(), peerImpl.FromPeerConfig(peerCfg))
+	return peerImpl.New(f.providerContext.Config(), peerImpl.FromPeerConfig(peerCfg), peerImpl.WithConnector(f.connector))
 }
 
 // CreateOrdererFromConfig creates a default implementation of Orderer based on configuration.
 func (f *FabricProvider) CreateOrdererFromConfig(cfg *core.OrdererConfig) (fab.Orderer, error) {
-	orderer, err := orderer.New(f.providerContext.Config(), orderer.FromOrdererConfig(cfg))
+	orderer, err := orderer.New(f.providerContext.Config(), orderer.FromOrdererConfig(cfg), orderer.WithConnector(f.connector))
 	if err != nil {
 		return nil, errors.WithMessage(err, "creating orderer failed")
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	channelImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
 	identityImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/identity"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/identitymgr"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
@@ -116,6 +117,37 @@ func TestCreatePeerFromConfig(t *testing.T) {
 	verifyPeer(t, peer, url)
 }
 
+func TestCreateEventClient(t *testing.T) {
+	peerCfg := core.ChannelPeer{
+		PeerChannelConfig: core.PeerChannelConfig{EventSource: true},
+		NetworkPeer: core.NetworkPeer{
+			PeerConfig: core.PeerConfig{URL: "grpc://localhost:8080"},
+			MspID:      "Org1MSP",
+		},
+	}
+	ctx := mocks.NewMockProviderContextCustom(mocks.NewMockConfigWithChannelPeers(peerCfg), &mocks.MockCryptoSuite{}, mocks.NewMockSigningManager())
+	p := New(ctx)
+	defer p.Close()
+
+	user := mocks.NewMockUserWithMSPID("user", "Org1MSP")
+	client, err := p.CreateEventClient(user, "mychannel")
+	if err != nil {
+		t.Fatalf("Unexpected error creating event client %v", err)
+	}
+	defer client.Close()
+
+	_, ok := client.(*deliverclient.Client)
+	if !ok {
+		t.Fatalf("Unexpected event client impl created: %v", client)
+	}
+
+	// No event source of the user's organization
+	_, err = p.CreateEventClient(mocks.NewMockUserWithMSPID("user", "Org2MSP"), "mychannel")
+	if err == nil {
+		t.Fatalf("Expecting error creating event client without event source")
+	}
+}
+
 func TestCreateUser(t *testing.T) {
 	org := "org1"
 